@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 
@@ -77,35 +76,32 @@ func runAccept(cmd *cobra.Command, args []string) error {
 	// Validate content if not forcing
 	if !forceAccept {
 		fmt.Println("🔎 Validating ADR content...")
-		
-		validation, err := validateADRContent(targetADR)
-		if err != nil {
-			return fmt.Errorf("failed to validate ADR: %w", err)
-		}
+	}
 
-		if !validation.IsComplete {
-			fmt.Println("⚠️  ADR content validation failed:")
-			for _, issue := range validation.Issues {
-				fmt.Printf("   • %s\n", issue)
-			}
-			fmt.Println()
-			fmt.Println("🔧 To fix these issues:")
-			fmt.Printf("   drduck edit %04d       # Edit the ADR\n", adrID)
-			fmt.Printf("   drduck accept %04d --force  # Accept anyway\n", adrID)
-			return fmt.Errorf("ADR is not ready for acceptance")
-		}
+	previousStatus := targetADR.Status
+	issues, err := manager.ChangeStatus(targetADR, adr.StatusAccepted, forceAccept)
+	if err != nil {
+		return fmt.Errorf("failed to accept ADR: %w", err)
+	}
 
-		fmt.Println("✅ ADR content validation passed")
+	if len(issues) > 0 {
+		fmt.Println("⚠️  ADR content validation failed:")
+		for _, issue := range issues {
+			fmt.Printf("   • %s\n", issue)
+		}
+		fmt.Println()
+		fmt.Println("🔧 To fix these issues:")
+		fmt.Printf("   drduck edit %04d       # Edit the ADR\n", adrID)
+		fmt.Printf("   drduck accept %04d --force  # Accept anyway\n", adrID)
+		return fmt.Errorf("ADR is not ready for acceptance")
 	}
 
-	// Update status to accepted
-	fmt.Printf("📊 Accepting ADR-%04d...\n", adrID)
-	if err := manager.UpdateADRStatus(adrID, adr.StatusAccepted); err != nil {
-		return fmt.Errorf("failed to accept ADR: %w", err)
+	if !forceAccept {
+		fmt.Println("✅ ADR content validation passed")
 	}
 
 	fmt.Printf("🎉 ADR-%04d (%s) has been accepted!\n", adrID, targetADR.Title)
-	fmt.Println("✅ Status updated from", targetADR.Status, "to Accepted")
+	fmt.Println("✅ Status updated from", previousStatus, "to Accepted")
 
 	// Show next steps
 	fmt.Println()
@@ -116,93 +112,3 @@ func runAccept(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
-
-// ADRValidation represents the result of ADR content validation
-type ADRValidation struct {
-	IsComplete bool
-	Issues     []string
-}
-
-// validateADRContent checks if an ADR has sufficient content to be accepted
-func validateADRContent(targetADR *adr.ADR) (*ADRValidation, error) {
-	validation := &ADRValidation{
-		IsComplete: true,
-		Issues:     []string{},
-	}
-
-	// Read the full content to analyze
-	content, err := os.ReadFile(targetADR.FilePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read ADR file: %w", err)
-	}
-
-	contentStr := strings.ToLower(string(content))
-
-	// Check for placeholder comments
-	placeholders := []string{
-		"<!-- what is the issue",
-		"<!-- what is the change",
-		"<!-- why are we making",
-		"<!-- what becomes easier",
-		"<!-- what becomes more difficult",
-		"<!-- what other options",
-		"<!-- what problem are we trying",
-		"<!-- what is our solution",
-		"<!-- why did we choose",
-		"<!-- what are the consequences",
-	}
-
-	placeholderCount := 0
-	for _, placeholder := range placeholders {
-		if strings.Contains(contentStr, placeholder) {
-			placeholderCount++
-		}
-	}
-
-	if placeholderCount > 2 {
-		validation.IsComplete = false
-		validation.Issues = append(validation.Issues, fmt.Sprintf("Found %d unfilled placeholder sections", placeholderCount))
-	}
-
-	// Check for minimum content in key sections
-	sections := map[string][]string{
-		"Context": {"## context", "## problem"},
-		"Decision": {"## decision", "## solution"},
-		"Rationale": {"## rationale", "## why"},
-	}
-
-	for sectionName, sectionHeaders := range sections {
-		hasContent := false
-		for _, header := range sectionHeaders {
-			if idx := strings.Index(contentStr, header); idx != -1 {
-				// Look for content after the header
-				afterHeader := contentStr[idx:]
-				nextHeader := strings.Index(afterHeader[len(header):], "##")
-				
-				var sectionContent string
-				if nextHeader == -1 {
-					sectionContent = afterHeader[len(header):]
-				} else {
-					sectionContent = afterHeader[len(header) : len(header)+nextHeader]
-				}
-				
-				// Remove common non-content
-				sectionContent = strings.ReplaceAll(sectionContent, "<!--", "")
-				sectionContent = strings.ReplaceAll(sectionContent, "-->", "")
-				sectionContent = strings.TrimSpace(sectionContent)
-				
-				if len(sectionContent) > 20 { // Minimum meaningful content
-					hasContent = true
-					break
-				}
-			}
-		}
-		
-		if !hasContent {
-			validation.IsComplete = false
-			validation.Issues = append(validation.Issues, fmt.Sprintf("%s section needs more content", sectionName))
-		}
-	}
-
-	return validation, nil
-}
\ No newline at end of file
@@ -2,7 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
 
+	"github.com/SilverFlin/DrDuck/internal/adr"
 	"github.com/SilverFlin/DrDuck/internal/cache"
 	"github.com/SilverFlin/DrDuck/internal/config"
 	"github.com/spf13/cobra"
@@ -35,11 +40,110 @@ var cacheCleanupCmd = &cobra.Command{
 	RunE:  runCacheCleanup,
 }
 
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cached analyses",
+	Long: `List cached analyses, newest first, optionally filtered by branch,
+commit range and resolution state.`,
+	RunE: runCacheList,
+}
+
+var cachePushCmd = &cobra.Command{
+	Use:   "push [remote]",
+	Short: "Share cached analyses with your team through git notes",
+	Long: `Export cached analyses as git notes under refs/notes/drduck and push them
+to a remote (default "origin"). Teammates and CI can then reuse them with
+'drduck cache pull' instead of paying for the same AI analysis again.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCachePush,
+}
+
+var cachePullCmd = &cobra.Command{
+	Use:   "pull [remote]",
+	Short: "Import analyses shared through git notes",
+	Long: `Fetch refs/notes/drduck from a remote (default "origin"), merge it with the
+local notes and import analyses that are missing from the local cache.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCachePull,
+}
+
+var cacheInspectCmd = &cobra.Command{
+	Use:   "inspect <hash>",
+	Short: "Show one cached analysis in full",
+	Long:  `Show one cached analysis in full. The hash may be any unique prefix, as shown by 'drduck cache list'.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runCacheInspect,
+}
+
+var cacheExportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Export cached analyses to a portable JSON bundle",
+	Long:  `Export all unexpired cached analyses to a JSON bundle, written to the file or to stdout.`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runCacheExport,
+}
+
+var cacheImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import a JSON bundle of cached analyses",
+	Long: `Merge a bundle created with 'drduck cache export' into the cache by content hash.
+An imported analysis replaces the local one only if it is resolved or newer.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCacheImport,
+}
+
+var cacheResolveCmd = &cobra.Command{
+	Use:   "resolve <hash> <adr-id>",
+	Short: "Mark a cached analysis as resolved by an existing ADR",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runCacheResolve,
+}
+
+var cacheInvalidateCmd = &cobra.Command{
+	Use:   "invalidate",
+	Short: "Remove cached analyses produced with a given provider, model, persona or prompt",
+	Long: `Remove cached analyses whose analysis configuration matches all given flags.
+At least one flag is required; see 'drduck cache status' for the configurations in use.`,
+	RunE: runCacheInvalidate,
+}
+
+var (
+	cacheInvalidateProvider string
+	cacheInvalidateModel    string
+	cacheInvalidatePersona  string
+	cacheInvalidatePrompt   string
+)
+
+var (
+	cacheListBranch     string
+	cacheListRange      string
+	cacheListResolved   bool
+	cacheListUnresolved bool
+)
+
 func init() {
 	rootCmd.AddCommand(cacheCmd)
 	cacheCmd.AddCommand(cacheStatusCmd)
 	cacheCmd.AddCommand(cacheClearCmd)
 	cacheCmd.AddCommand(cacheCleanupCmd)
+	cacheCmd.AddCommand(cacheListCmd)
+	cacheCmd.AddCommand(cachePushCmd)
+	cacheCmd.AddCommand(cachePullCmd)
+	cacheCmd.AddCommand(cacheInvalidateCmd)
+	cacheCmd.AddCommand(cacheInspectCmd)
+	cacheCmd.AddCommand(cacheExportCmd)
+	cacheCmd.AddCommand(cacheImportCmd)
+	cacheCmd.AddCommand(cacheResolveCmd)
+
+	cacheInvalidateCmd.Flags().StringVar(&cacheInvalidateProvider, "provider", "", "AI provider, e.g. claude-code, cursor or rules")
+	cacheInvalidateCmd.Flags().StringVar(&cacheInvalidateModel, "model", "", "Model name")
+	cacheInvalidateCmd.Flags().StringVar(&cacheInvalidatePersona, "persona", "", "Persona name")
+	cacheInvalidateCmd.Flags().StringVar(&cacheInvalidatePrompt, "prompt", "", "Prompt hash or prefix, as shown by 'drduck cache status'")
+
+	cacheListCmd.Flags().StringVar(&cacheListBranch, "branch", "", "Only list analyses of this branch")
+	cacheListCmd.Flags().StringVar(&cacheListRange, "range", "", "Only list analyses of this commit range (e.g. origin/main..HEAD)")
+	cacheListCmd.Flags().BoolVar(&cacheListResolved, "resolved", false, "Only list analyses resolved by an ADR")
+	cacheListCmd.Flags().BoolVar(&cacheListUnresolved, "unresolved", false, "Only list analyses not yet resolved")
 }
 
 func runCacheStatus(cmd *cobra.Command, args []string) error {
@@ -71,9 +175,16 @@ func runCacheStatus(cmd *cobra.Command, args []string) error {
 	// Display cache status
 	fmt.Println("🦆 DrDuck Analysis Cache Status")
 	fmt.Println("===============================")
+	fmt.Printf("Backend: %v\n", stats["backend"])
 	fmt.Printf("Total entries: %v\n", stats["total_entries"])
 	fmt.Printf("Resolved entries: %v\n", stats["resolved_entries"])
 	fmt.Printf("Unresolved entries: %v\n", stats["unresolved_entries"])
+	fmt.Printf("Cached summaries: %v\n", stats["chunk_summaries"])
+	if hitRate, ok := stats["file_hit_rate"].(float64); ok {
+		fmt.Printf("Per-file hit rate: %.1f%% (%v hits, %v misses)\n", hitRate*100, stats["file_hits"], stats["file_misses"])
+	} else {
+		fmt.Println("Per-file hit rate: n/a (no lookups yet)")
+	}
 	fmt.Printf("Max age (days): %v\n", stats["max_age_days"])
 	fmt.Printf("Max entries: %v\n", stats["max_entries"])
 	fmt.Printf("Cache version: %v\n", stats["version"])
@@ -82,6 +193,18 @@ func runCacheStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Last cleanup: %v\n", lastCleanup)
 	}
 
+	if byConfig, ok := stats["entries_by_config"].(map[string]int); ok && len(byConfig) > 0 {
+		fmt.Println("\n🧩 Entries by analysis configuration:")
+		configs := make([]string, 0, len(byConfig))
+		for analysisConfig := range byConfig {
+			configs = append(configs, analysisConfig)
+		}
+		sort.Strings(configs)
+		for _, analysisConfig := range configs {
+			fmt.Printf("  %4d  %s\n", byConfig[analysisConfig], analysisConfig)
+		}
+	}
+
 	// Show current changes fingerprint for debugging
 	changes, err := cacheManager.GetCurrentChanges()
 	if err == nil && changes != "" {
@@ -175,4 +298,298 @@ func runCacheCleanup(cmd *cobra.Command, args []string) error {
 	fmt.Printf("   Entries removed: %d\n", removed)
 
 	return nil
-}
\ No newline at end of file
+}
+
+func runCacheList(cmd *cobra.Command, args []string) error {
+	// Check if project is initialized
+	initialized, err := config.IsInitialized()
+	if err != nil {
+		return fmt.Errorf("failed to check initialization status: %w", err)
+	}
+
+	if !initialized {
+		return fmt.Errorf("❌ DrDuck is not initialized in this project. Run 'drduck init' first")
+	}
+
+	if cacheListResolved && cacheListUnresolved {
+		return fmt.Errorf("--resolved and --unresolved cannot be combined")
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Create cache manager
+	cacheManager := cache.NewManagerFromMainConfig(cfg.Cache)
+
+	query := cache.Query{Branch: cacheListBranch, CommitRange: cacheListRange}
+	if cacheListResolved || cacheListUnresolved {
+		resolved := cacheListResolved
+		query.Resolved = &resolved
+	}
+
+	entries, err := cacheManager.Query(query)
+	if err != nil {
+		return fmt.Errorf("failed to query cache: %w", err)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("📭 No cached analyses match")
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "HASH\tANALYZED\tBRANCH\tCOMMIT RANGE\tADR NEEDED\tTITLE\tRESOLVED BY")
+	for _, entry := range entries {
+		analysis := entry.Analysis
+		resolvedBy := "-"
+		if analysis.Resolved {
+			resolvedBy = fmt.Sprintf("ADR %04d", analysis.ResolvedADRID)
+		}
+		title := analysis.Title
+		if title == "" {
+			title = "-"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			shortHash(entry.ContentHash),
+			analysis.Timestamp.Format("2006-01-02 15:04"),
+			analysis.Branch,
+			analysis.CommitRange,
+			analysis.Decision,
+			title,
+			resolvedBy)
+	}
+	writer.Flush()
+	fmt.Printf("\n%d analyses (use 'drduck cache inspect <hash>' for details)\n", len(entries))
+
+	return nil
+}
+
+func runCachePush(cmd *cobra.Command, args []string) error {
+	_, cacheManager, err := loadCacheManager()
+	if err != nil {
+		return err
+	}
+
+	remote := remoteArg(args)
+	exported, skipped, err := cacheManager.PushNotes(remote)
+	if err != nil {
+		return fmt.Errorf("failed to push analyses: %w", err)
+	}
+
+	fmt.Printf("✅ Pushed %d analyses to %s (%s)\n", exported, remote, cache.NotesRef)
+	if skipped > 0 {
+		fmt.Printf("   %d analyses were skipped because they predate HEAD commit tracking\n", skipped)
+	}
+	return nil
+}
+
+func runCachePull(cmd *cobra.Command, args []string) error {
+	_, cacheManager, err := loadCacheManager()
+	if err != nil {
+		return err
+	}
+
+	remote := remoteArg(args)
+	imported, err := cacheManager.PullNotes(remote)
+	if err != nil {
+		return fmt.Errorf("failed to pull analyses: %w", err)
+	}
+
+	fmt.Printf("✅ Imported %d analyses from %s (%s)\n", imported, remote, cache.NotesRef)
+	return nil
+}
+
+func runCacheInvalidate(cmd *cobra.Command, args []string) error {
+	filter := cache.AnalysisConfig{
+		Provider:   cacheInvalidateProvider,
+		Model:      cacheInvalidateModel,
+		Persona:    cacheInvalidatePersona,
+		PromptHash: cacheInvalidatePrompt,
+	}
+	if filter == (cache.AnalysisConfig{}) {
+		return fmt.Errorf("specify at least one of --provider, --model, --persona or --prompt")
+	}
+
+	_, cacheManager, err := loadCacheManager()
+	if err != nil {
+		return err
+	}
+
+	removed, err := cacheManager.Invalidate(filter)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate cache entries: %w", err)
+	}
+
+	fmt.Printf("✅ Removed %d cached analyses\n", removed)
+	return nil
+}
+
+func runCacheInspect(cmd *cobra.Command, args []string) error {
+	_, cacheManager, err := loadCacheManager()
+	if err != nil {
+		return err
+	}
+
+	entry, err := cacheManager.FindEntry(args[0])
+	if err != nil {
+		return err
+	}
+
+	analysis := entry.Analysis
+	fmt.Println("🦆 Cached Analysis")
+	fmt.Println("=================")
+	fmt.Printf("Content hash: %s\n", entry.ContentHash)
+	fmt.Printf("Analyzed: %s\n", analysis.Timestamp.Format("2006-01-02 15:04:05"))
+	fmt.Printf("Branch: %s\n", analysis.Branch)
+	fmt.Printf("Commit range: %s\n", analysis.CommitRange)
+	if analysis.HeadCommit != "" {
+		fmt.Printf("HEAD commit: %s\n", analysis.HeadCommit)
+	}
+	fmt.Printf("Configuration: %s\n", analysis.Config)
+	fmt.Printf("ADR needed: %s\n", analysis.Decision)
+	if analysis.Title != "" {
+		fmt.Printf("Suggested title: %s\n", analysis.Title)
+	}
+	if analysis.Resolved {
+		fmt.Printf("Resolved by: ADR %04d\n", analysis.ResolvedADRID)
+	} else {
+		fmt.Println("Resolved by: -")
+	}
+
+	if len(analysis.ChangesAnalyzed) > 0 {
+		files := append([]string(nil), analysis.ChangesAnalyzed...)
+		sort.Strings(files)
+		fmt.Printf("\n📁 Files analyzed (%d):\n", len(files))
+		for _, file := range files {
+			fmt.Printf("  %s\n", file)
+		}
+	}
+
+	fmt.Println("\n💬 Analysis:")
+	fmt.Println(analysis.Suggestion)
+	return nil
+}
+
+func runCacheExport(cmd *cobra.Command, args []string) error {
+	_, cacheManager, err := loadCacheManager()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		_, err := cacheManager.Export(os.Stdout)
+		return err
+	}
+
+	file, err := os.Create(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to create bundle file: %w", err)
+	}
+	defer file.Close()
+
+	exported, err := cacheManager.Export(file)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Exported %d analyses to %s\n", exported, args[0])
+	return nil
+}
+
+func runCacheImport(cmd *cobra.Command, args []string) error {
+	_, cacheManager, err := loadCacheManager()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open bundle file: %w", err)
+	}
+	defer file.Close()
+
+	imported, total, err := cacheManager.Import(file)
+	if err != nil {
+		return fmt.Errorf("failed to import %s: %w", args[0], err)
+	}
+
+	fmt.Printf("✅ Imported %d of %d analyses from %s\n", imported, total, args[0])
+	if skipped := total - imported; skipped > 0 {
+		fmt.Printf("   %d were skipped because the local copy is the same, newer or already resolved\n", skipped)
+	}
+	return nil
+}
+
+func runCacheResolve(cmd *cobra.Command, args []string) error {
+	cfg, cacheManager, err := loadCacheManager()
+	if err != nil {
+		return err
+	}
+
+	return resolveCachedAnalysis(cfg, cacheManager, args[0], args[1])
+}
+
+// resolveCachedAnalysis links the cached analysis matching a hash prefix to an existing ADR
+func resolveCachedAnalysis(cfg *config.Config, cacheManager *cache.Manager, hashPrefix, adrIDArg string) error {
+	adrID, err := strconv.Atoi(adrIDArg)
+	if err != nil {
+		return fmt.Errorf("invalid ADR ID: %s", adrIDArg)
+	}
+
+	targetADR, err := adr.NewManager(cfg).GetADRByID(adrID)
+	if err != nil {
+		return err
+	}
+
+	entry, err := cacheManager.FindEntry(hashPrefix)
+	if err != nil {
+		return err
+	}
+
+	if err := cacheManager.ResolveEntry(entry.ContentHash, adrID); err != nil {
+		return fmt.Errorf("failed to resolve cached analysis: %w", err)
+	}
+
+	fmt.Printf("✅ Analysis %s marked as resolved by ADR %04d: %s\n", shortHash(entry.ContentHash), targetADR.ID, targetADR.Title)
+	return nil
+}
+
+// loadCacheManager checks initialization and creates a cache manager from the project config
+func loadCacheManager() (*config.Config, *cache.Manager, error) {
+	// Check if project is initialized
+	initialized, err := config.IsInitialized()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check initialization status: %w", err)
+	}
+
+	if !initialized {
+		return nil, nil, fmt.Errorf("❌ DrDuck is not initialized in this project. Run 'drduck init' first")
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	return cfg, cache.NewManagerFromMainConfig(cfg.Cache), nil
+}
+
+// remoteArg returns the remote named on the command line, defaulting to origin
+func remoteArg(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	return "origin"
+}
+
+// shortHash abbreviates a content hash for display
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
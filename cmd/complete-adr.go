@@ -11,9 +11,14 @@ import (
 
 	"github.com/SilverFlin/DrDuck/internal/adr"
 	"github.com/SilverFlin/DrDuck/internal/ai"
+	"github.com/SilverFlin/DrDuck/internal/apidiff"
 	"github.com/SilverFlin/DrDuck/internal/cache"
+	"github.com/SilverFlin/DrDuck/internal/chunking"
 	"github.com/SilverFlin/DrDuck/internal/config"
+	"github.com/SilverFlin/DrDuck/internal/diff"
 	"github.com/SilverFlin/DrDuck/internal/prompts/templates"
+	"github.com/SilverFlin/DrDuck/internal/signals"
+	"github.com/SilverFlin/DrDuck/internal/transcript"
 	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
@@ -22,6 +27,10 @@ import (
 var createNewADR bool
 var compareBranch string
 var excludePatterns []string
+var transcriptPath string
+
+// maxTranscriptPromptSize caps how much of a transcript is sent for summarization
+const maxTranscriptPromptSize = 60000
 
 var completeADRCmd = &cobra.Command{
 	Use:   "complete-adr [adr-id]",
@@ -36,10 +45,17 @@ Usage scenarios:
   drduck complete-adr 0001 --compare=develop         # Complete ADR comparing against develop
   drduck complete-adr --create --exclude="*.html"    # Ignore all HTML files from analysis
   drduck complete-adr --create --exclude="*.html,test/*,docs/" # Exclude multiple patterns
+  drduck complete-adr 0001 --transcript chat.md      # Pre-fill answers from a conversation
+  cat session.jsonl | drduck complete-adr --create --transcript -
+
+Transcripts can be ChatGPT exports (conversations.json), Copilot Chat exports,
+Aider chat histories (.aider.chat.history.md), JSON or JSON Lines of role/content
+messages (including Claude Code transcripts), or Markdown/text with speaker
+labels such as "User:" and "Assistant:".
 
 The command will:
 1. Analyze your git changes using AI (comparing against specified branch)
-2. Ask targeted questions based on change type
+2. Ask targeted questions based on change type, pre-filled from --transcript
 3. Generate complete ADR content from your responses
 4. Preview the content and allow confirmation
 5. Save the completed ADR`,
@@ -60,6 +76,7 @@ func init() {
 	completeADRCmd.Flags().BoolVar(&createNewADR, "create", false, "Create a new ADR instead of completing existing one")
 	completeADRCmd.Flags().StringVar(&compareBranch, "compare", "", "Branch to compare changes against (defaults to origin/{current-branch})")
 	completeADRCmd.Flags().StringSliceVar(&excludePatterns, "exclude", []string{}, "File patterns to exclude from analysis (e.g., '*.html', 'test/*')")
+	completeADRCmd.Flags().StringVar(&transcriptPath, "transcript", "", "AI conversation transcript to pre-fill answers from (.md, .txt, .json, .jsonl, or - for stdin)")
 }
 
 func runCompleteADR(cmd *cobra.Command, args []string) error {
@@ -79,10 +96,20 @@ func runCompleteADR(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
+	// Read the transcript first so a bad path fails before any prompts
+	var conversation *transcript.Transcript
+	if transcriptPath != "" {
+		conversation, err = readTranscript(transcriptPath)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Create managers
 	adrManager := adr.NewManager(cfg)
 	aiManager := ai.NewManager(cfg)
 	cacheManager := cache.NewManagerFromMainConfig(cfg.Cache)
+	cacheManager.SetAnalysisConfig(aiManager.AnalysisConfig())
 
 	fmt.Println("🦆 Welcome to AI-Assisted ADR Completion!")
 	fmt.Println("=====================================")
@@ -95,11 +122,14 @@ func runCompleteADR(cmd *cobra.Command, args []string) error {
 	if createNewADR {
 		// Create new ADR workflow
 		fmt.Println("📝 Creating a new ADR based on your recent changes...")
-		targetADR, err = createNewADRFromChanges(adrManager, aiManager, cacheManager)
+		targetADR, isNewADR, err = createNewADRFromChanges(cfg, adrManager, aiManager, cacheManager)
 		if err != nil {
 			return fmt.Errorf("failed to create new ADR: %w", err)
 		}
-		isNewADR = true
+		if targetADR == nil {
+			fmt.Println("👋 ADR creation cancelled")
+			return nil
+		}
 	} else {
 		// Complete existing ADR workflow
 		adrIDStr := args[0]
@@ -121,7 +151,7 @@ func runCompleteADR(cmd *cobra.Command, args []string) error {
 
 	// Step 1: Analyze current changes
 	fmt.Println("🔍 Step 1: Analyzing your code changes...")
-	changes, changeAnalysis, analysisTokenUsage, err := analyzeRecentChanges(aiManager, cacheManager, compareBranch, excludePatterns)
+	changes, changeAnalysis, changeSignals, analysisTokenUsage, err := analyzeRecentChanges(aiManager, cacheManager, compareBranch, excludePatterns)
 	if err != nil {
 		fmt.Printf("⚠️  Could not analyze changes: %v\n", err)
 		fmt.Println("Continuing with manual input...")
@@ -153,16 +183,41 @@ func runCompleteADR(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Pull in what was discussed in the AI session behind these changes
+	sessionContext := ""
+	if aiManager.CanGenerate() {
+		sessionContext, err = aiManager.ExtractContext()
+		if err != nil {
+			fmt.Printf("ℹ️  No AI session context: %v\n", err)
+			sessionContext = ""
+		} else if sessionContext != "" {
+			fmt.Printf("\n🧠 Using context from your %s session:\n", aiManager.GetProviderName())
+			fmt.Println("---")
+			fmt.Println(sessionContext)
+			fmt.Println("---")
+		}
+	}
+
 	// Step 2: Interactive questionnaire
 	fmt.Println("\n💬 Step 2: Let's gather context about your decision...")
-	responses, err := conductInteractiveQuestionnaire(targetADR.Title, changeAnalysis)
+	var prefill *QuestionnaireResponse
+	if conversation != nil {
+		var transcriptTokenUsage *ai.TokenUsage
+		prefill, transcriptTokenUsage = prefillFromTranscript(aiManager, conversation, targetADR.Title)
+		if transcriptTokenUsage != nil {
+			totalTokenUsage.InputTokens += transcriptTokenUsage.InputTokens
+			totalTokenUsage.OutputTokens += transcriptTokenUsage.OutputTokens
+			totalTokenUsage.TotalTokens += transcriptTokenUsage.TotalTokens
+		}
+	}
+	responses, err := conductInteractiveQuestionnaire(targetADR.Title, changeAnalysis, prefill)
 	if err != nil {
 		return fmt.Errorf("questionnaire failed: %w", err)
 	}
 
 	// Step 3: Generate ADR content using AI
 	fmt.Println("\n🤖 Step 3: Generating ADR content with AI...")
-	generatedContent, contentTokenUsage, err := generateADRContent(aiManager, targetADR, changes, changeAnalysis, responses)
+	generatedContent, contentTokenUsage, err := generateADRContent(aiManager, targetADR, changes, changeAnalysis, sessionContext, changeSignals, responses)
 	if err != nil {
 		return fmt.Errorf("failed to generate ADR content: %w", err)
 	}
@@ -224,17 +279,19 @@ func runCompleteADR(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// createNewADRFromChanges creates a new ADR with AI-suggested title
-func createNewADRFromChanges(adrManager *adr.Manager, aiManager *ai.Manager, cacheManager *cache.Manager) (*adr.ADR, error) {
+// createNewADRFromChanges creates a new ADR with AI-suggested title. When the
+// user chooses to amend a similar existing ADR instead, that ADR is returned and
+// isNew is false; a nil ADR means the user cancelled.
+func createNewADRFromChanges(cfg *config.Config, adrManager *adr.Manager, aiManager *ai.Manager, cacheManager *cache.Manager) (targetADR *adr.ADR, isNew bool, err error) {
 	// Get git changes to suggest title
 	changes, err := getGitChangesSummary(compareBranch, excludePatterns)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get git changes: %w", err)
+		return nil, false, fmt.Errorf("failed to get git changes: %w", err)
 	}
 
 	// Use AI to suggest title
 	suggestedTitle := "recent-architectural-changes"
-	if aiManager.IsAvailable() && changes != "" {
+	if aiManager.CanGenerate() && changes != "" {
 		prompt := fmt.Sprintf("Based on these git changes, suggest a concise ADR title in kebab-case format (2-4 words). Respond with just the title, nothing else:\n\n%s", changes)
 		response, err := aiManager.AnalyzeChanges(prompt)
 		if err == nil && response != "" {
@@ -248,11 +305,35 @@ func createNewADRFromChanges(adrManager *adr.Manager, aiManager *ai.Manager, cac
 	// Ask user to confirm or change the suggested title before creating the ADR
 	finalTitle, err := confirmOrChangeTitle(suggestedTitle)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get title confirmation: %w", err)
+		return nil, false, fmt.Errorf("failed to get title confirmation: %w", err)
+	}
+
+	// Offer similar existing ADRs before writing a duplicate
+	choice, err := checkRelatedADRs(cfg, adrManager, finalTitle)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch choice.Action {
+	case relatedCancel:
+		return nil, false, nil
+	case relatedAmend:
+		fmt.Printf("📝 Amending ADR-%04d: %s\n", choice.Target.ID, choice.Target.Title)
+		return choice.Target, false, nil
 	}
 
 	// Create the ADR with the confirmed title
-	return adrManager.Create(finalTitle)
+	newADR, err := adrManager.Create(finalTitle)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if choice.Action == relatedSupersede {
+		if err := supersedeADR(adrManager, choice.Target, newADR); err != nil {
+			return nil, false, err
+		}
+	}
+	return newADR, true, nil
 }
 
 // confirmOrChangeTitle asks user to confirm or change the suggested title
@@ -407,7 +488,25 @@ func isValidTitle(title string) bool {
 }
 
 // analyzeRecentChanges gets git changes and AI analysis with timeout protection
-func analyzeRecentChanges(aiManager *ai.Manager, cacheManager *cache.Manager, branchToCompare string, excludePatterns []string) (changes string, analysis string, tokenUsage *ai.TokenUsage, err error) {
+func analyzeRecentChanges(aiManager *ai.Manager, cacheManager *cache.Manager, branchToCompare string, excludePatterns []string) (changes string, analysis string, changeSignals *signals.Signals, tokenUsage *ai.TokenUsage, err error) {
+	if _, refErr := getCompareRef(branchToCompare); refErr != nil {
+		fmt.Printf("⚠️  %v; only uncommitted changes can be analyzed\n", refErr)
+	}
+
+	// Extract structured signals (e.g. dependency changes) from the full, unfiltered diff
+	if rawDiff, diffErr := getRawDiff(branchToCompare); diffErr == nil {
+		files := diff.Parse(rawDiff)
+		changeSignals = signals.Collect(files)
+		if baseRef, refErr := getCompareRef(branchToCompare); refErr == nil {
+			// Comparing with the base is best effort: a shallow clone may lack the base commit
+			_ = changeSignals.CompareRefs(baseRef, files)
+		}
+		if !changeSignals.Empty() {
+			fmt.Println("📦 Detected structured changes:")
+			fmt.Println(changeSignals.Summary())
+		}
+	}
+
 	// First check if we have cached analysis for current changes
 	if cacheManager != nil {
 		cachedAnalysis, found, cacheErr := cacheManager.GetAnalysis()
@@ -424,7 +523,7 @@ func analyzeRecentChanges(aiManager *ai.Manager, cacheManager *cache.Manager, br
 				cachedAnalysis.Suggestion,
 				cachedAnalysis.Timestamp.Format("2006-01-02 15:04:05"))
 			
-			return changes, analysis, nil, nil
+			return changes, analysis, changeSignals, nil, nil
 		}
 	}
 
@@ -432,22 +531,28 @@ func analyzeRecentChanges(aiManager *ai.Manager, cacheManager *cache.Manager, br
 	changes, err = getGitChangesSummary(branchToCompare, excludePatterns)
 	if err != nil {
 		changes = "Could not detect git changes - proceeding with manual input"
-		return changes, "Git analysis unavailable", nil, nil
+		return changes, "Git analysis unavailable", changeSignals, nil, nil
 	}
 
 	if changes == "" || changes == "No git changes detected" {
-		return changes, "No significant changes detected", nil, nil
+		return changes, "No significant changes detected", changeSignals, nil, nil
 	}
 
 	if !aiManager.IsAvailable() {
-		return changes, "AI analysis not available - using change detection only", nil, nil
+		return changes, "AI analysis not available - using change detection only", changeSignals, nil, nil
 	}
 
 	// Try to get detailed changes for AI analysis (may be large)
 	fmt.Print("Getting detailed changes for AI analysis... ")
-	detailedChanges, wasTruncated, err := getDetailedChanges(branchToCompare, excludePatterns)
+	// AI providers get the schema summary instead of raw migration SQL; the
+	// rules engine parses the diff itself and needs it unchanged
+	condenseSignals := changeSignals
+	if !aiManager.CanGenerate() {
+		condenseSignals = nil
+	}
+	detailedChanges, wasTruncated, err := getDetailedChanges(branchToCompare, excludePatterns, condenseSignals)
 	if err != nil {
-		fmt.Println("failed, using summary")
+		fmt.Printf("failed (%v), using summary\n", err)
 		detailedChanges = changes // Fallback to summary
 		wasTruncated = false
 	} else {
@@ -467,13 +572,42 @@ func analyzeRecentChanges(aiManager *ai.Manager, cacheManager *cache.Manager, br
 		promptChanges = detailedChanges
 	}
 
+	// Large change sets are summarized chunk by chunk instead of being analyzed truncated
+	if wasTruncated {
+		if chunkedAnalysis, chunkedUsage, ok := analyzeInChunks(aiManager, cacheManager, branchToCompare, excludePatterns, changeSignals); ok {
+			return changes, chunkedAnalysis, changeSignals, chunkedUsage, nil
+		}
+	}
+
+	// Files whose diff was already summarized are sent as their cached summary only
+	sections := changeSignals.Sections()
+	var plan *cache.IncrementalPlan
+	if !wasTruncated && cacheManager != nil && aiManager.CanGenerate() {
+		if files := diff.Parse(promptChanges); len(files) > 0 {
+			plan = cacheManager.PlanIncremental(files)
+			if plan.HasCachedFiles() {
+				fmt.Printf("Reusing cached summaries for %d of %d files\n", len(plan.Cached), len(files))
+				promptChanges = diff.Render(plan.Changed)
+				sections = append(sections, templates.AnalysisSection{Title: "Previously Analyzed Files", Content: plan.CachedSummaries()})
+			}
+		}
+	}
+
 	// Run AI analysis with timeout protection
 	fmt.Print("Running AI analysis... ")
-	analysis, tokenUsage, err = analyzeWithTimeout(aiManager, promptChanges, 30*time.Second)
+	analysis, tokenUsage, err = analyzeWithTimeout(aiManager, promptChanges, sections, 30*time.Second)
+	if err == nil {
+		// Keep the per-file summaries for the next run and hide them from the user
+		var fileSummaries map[string]string
+		analysis, fileSummaries = templates.ParseFileSummaries(analysis)
+		if plan != nil {
+			_ = cacheManager.StoreFileSummaries(plan, fileSummaries)
+		}
+	}
 	if err != nil {
 		fmt.Printf("failed (%v), using fallback\n", err)
 		// Provide intelligent fallback analysis based on change patterns
-		analysis = generateFallbackAnalysis(changes, wasTruncated)
+		analysis = generateFallbackAnalysis(changes, changeSignals, wasTruncated)
 		err = nil // Clear error so workflow continues
 	} else {
 		fmt.Println("completed")
@@ -483,11 +617,55 @@ func analyzeRecentChanges(aiManager *ai.Manager, cacheManager *cache.Manager, br
 		}
 	}
 	
-	return changes, analysis, tokenUsage, err
+	return changes, analysis, changeSignals, tokenUsage, err
+}
+
+// analyzeInChunks runs a map-reduce analysis over the full, untruncated change set:
+// each directory chunk is summarized (in parallel, with cached summaries) and the
+// ADR decision is made from the summaries. ok is false when chunking is disabled or fails.
+func analyzeInChunks(aiManager *ai.Manager, cacheManager *cache.Manager, branchToCompare string, excludePatterns []string, changeSignals *signals.Signals) (string, *ai.TokenUsage, bool) {
+	cfg, err := config.Load()
+	if err != nil || !cfg.AISettings.Chunking.Enabled || !aiManager.CanGenerate() {
+		return "", nil, false
+	}
+
+	rawDiff, err := getRawDiff(branchToCompare)
+	if err != nil {
+		return "", nil, false
+	}
+
+	var files []*diff.File
+	for _, file := range diff.Parse(changeSignals.Condense(rawDiff)) {
+		if !shouldSkipFile(file.Path()) && !shouldExcludeFile(file.Path(), excludePatterns) {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return "", nil, false
+	}
+
+	var summaryCache chunking.SummaryCache
+	if cacheManager != nil {
+		summaryCache = cacheManager
+	}
+	settings := cfg.AISettings.Chunking
+	analyzer := chunking.NewAnalyzer(aiManager, summaryCache, settings.MaxChunkSize, settings.MaxConcurrency)
+
+	fmt.Printf("Running chunked AI analysis (%d files, up to %d chunks in parallel)... ", len(files), settings.MaxConcurrency)
+	result, err := analyzer.Analyze(files, "", changeSignals.Sections()...)
+	if err != nil {
+		fmt.Printf("failed (%v), falling back to truncated analysis\n", err)
+		return "", nil, false
+	}
+
+	fmt.Println("completed")
+	fmt.Printf("📊 AI Analysis Token Usage: %d input + %d output = %d total tokens\n",
+		result.TokenUsage.InputTokens, result.TokenUsage.OutputTokens, result.TokenUsage.TotalTokens)
+	return result.Response, &result.TokenUsage, true
 }
 
 // analyzeWithTimeout runs AI analysis with a timeout and returns token usage
-func analyzeWithTimeout(aiManager *ai.Manager, changes string, timeout time.Duration) (string, *ai.TokenUsage, error) {
+func analyzeWithTimeout(aiManager *ai.Manager, changes string, sections []templates.AnalysisSection, timeout time.Duration) (string, *ai.TokenUsage, error) {
 	type result struct {
 		analysis   string
 		tokenUsage *ai.TokenUsage
@@ -499,7 +677,7 @@ func analyzeWithTimeout(aiManager *ai.Manager, changes string, timeout time.Dura
 
 	// Run analysis in goroutine
 	go func() {
-		prompt := templates.ChangeAnalysisPrompt("", changes, "")
+		prompt := templates.ChangeAnalysisPrompt("", changes, "", sections...)
 		analyzeResult, err := aiManager.AnalyzeChangesWithTokens(prompt)
 		if err != nil {
 			// Fallback to regular analysis
@@ -520,7 +698,7 @@ func analyzeWithTimeout(aiManager *ai.Manager, changes string, timeout time.Dura
 }
 
 // generateFallbackAnalysis creates intelligent fallback when AI fails
-func generateFallbackAnalysis(changes string, wasTruncated bool) string {
+func generateFallbackAnalysis(changes string, changeSignals *signals.Signals, wasTruncated bool) string {
 	var analysis strings.Builder
 	
 	if wasTruncated {
@@ -538,7 +716,11 @@ func generateFallbackAnalysis(changes string, wasTruncated bool) string {
 	   strings.Contains(changesLower, "sql") || strings.Contains(changesLower, "schema") {
 		changeTypes = append(changeTypes, "Database/Schema changes")
 	}
-	if strings.Contains(changesLower, "api") || strings.Contains(changesLower, "endpoint") ||
+	if len(changeSignals.BreakingAPIChanges()) > 0 {
+		changeTypes = append(changeTypes, "Breaking public API changes")
+	} else if changeSignals != nil && len(changeSignals.API) > 0 {
+		changeTypes = append(changeTypes, "Public API additions")
+	} else if strings.Contains(changesLower, "endpoint") ||
 	   strings.Contains(changesLower, "route") || strings.Contains(changesLower, "controller") {
 		changeTypes = append(changeTypes, "API/Interface changes")
 	}
@@ -629,32 +811,12 @@ func getGitChangesSummary(branchToCompare string, excludePatterns []string) (str
 
 // getChangesSinceLastPush gets changes since last push (same logic as pre-push hook)
 func getChangesSinceLastPush(branchToCompare string, excludePatterns []string) (string, error) {
-	var remoteBranch string
-	
-	if branchToCompare != "" {
-		// Use the specified branch for comparison
-		remoteBranch = branchToCompare
-	} else {
-		// Get current branch and use origin/{current-branch} as default
-		branchCmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-		branchOutput, err := branchCmd.Output()
-		if err != nil {
-			return "", fmt.Errorf("failed to get current branch: %w", err)
-		}
-		branch := strings.TrimSpace(string(branchOutput))
-		remoteBranch = fmt.Sprintf("origin/%s", branch)
-	}
-
-	// Try to get diff against the specified/default branch
-	diffCmd := exec.Command("git", "diff", remoteBranch+"..HEAD", "--stat")
-	output, err := diffCmd.Output()
-	
-	// Only if no remote branch exists, fallback to last 3 commits
+	baseRef, err := getCompareRef(branchToCompare)
 	if err != nil {
-		diffCmd = exec.Command("git", "diff", "HEAD~3..HEAD", "--stat")
-		output, err = diffCmd.Output()
+		return "", err
 	}
 
+	output, err := exec.Command("git", "diff", baseRef+"..HEAD", "--stat").Output()
 	result := string(output)
 	return filterGitOutputByPatterns(result, excludePatterns), err
 }
@@ -666,40 +828,17 @@ const (
 	MaxFilesChanged = 50    // Maximum files to analyze in detail
 )
 
-// getDetailedChanges gets actual code changes (not just stats) with size limits
-func getDetailedChanges(branchToCompare string, excludePatterns []string) (string, bool, error) {
-	var remoteBranch string
-	
-	if branchToCompare != "" {
-		// Use the specified branch for comparison
-		remoteBranch = branchToCompare
-	} else {
-		// Get current branch and use origin/{current-branch} as default
-		branchCmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-		branchOutput, err := branchCmd.Output()
-		if err != nil {
-			return "", false, fmt.Errorf("failed to get current branch: %w", err)
-		}
-		branch := strings.TrimSpace(string(branchOutput))
-		remoteBranch = fmt.Sprintf("origin/%s", branch)
-	}
-
-	// Try to get full diff content against the specified/default branch
-	cmd := exec.Command("git", "diff", remoteBranch+"..HEAD")
-	output, err := cmd.Output()
-	
-	var changes string
+// getDetailedChanges gets actual code changes (not just stats) with size limits.
+// Migrations summarized in changeSignals are condensed to their headers.
+func getDetailedChanges(branchToCompare string, excludePatterns []string, changeSignals *signals.Signals) (string, bool, error) {
+	changes, err := getRawDiff(branchToCompare)
 	if err != nil {
-		// Fallback to last 3 commits if no remote
-		cmd = exec.Command("git", "diff", "HEAD~3..HEAD")
-		output, err = cmd.Output()
-		if err == nil {
-			changes = string(output)
-		}
-	} else {
-		changes = string(output)
+		return "", false, err
 	}
 
+	// Replace migrations already covered by the schema summary before applying size limits
+	changes = changeSignals.Condense(changes)
+
 	if strings.TrimSpace(changes) == "" {
 		return "No changes detected", false, nil
 	}
@@ -709,6 +848,34 @@ func getDetailedChanges(branchToCompare string, excludePatterns []string) (strin
 	return filteredChanges, wasTruncated, nil
 }
 
+// getRawDiff gets the full, unfiltered diff against the specified/default branch
+func getRawDiff(branchToCompare string) (string, error) {
+	baseRef, err := getCompareRef(branchToCompare)
+	if err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command("git", "diff", baseRef+"..HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to diff against %s: %w", baseRef, err)
+	}
+
+	return string(output), nil
+}
+
+// getCompareRef resolves the ref changes are compared against: the specified
+// branch, or else the branch on origin or where it forked from the default branch
+func getCompareRef(branchToCompare string) (string, error) {
+	if branchToCompare == "" {
+		return apidiff.BaseRef()
+	}
+	if !apidiff.RefExists(branchToCompare) {
+		return "", fmt.Errorf("branch %s not found", branchToCompare)
+	}
+	return branchToCompare, nil
+}
+
 // getCurrentRemoteBranch gets the remote tracking branch
 func getCurrentRemoteBranch() (string, error) {
 	branchCmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
@@ -888,9 +1055,13 @@ type QuestionnaireResponse struct {
 	AdditionalContext string
 }
 
-// conductInteractiveQuestionnaire asks targeted questions based on the ADR context
-func conductInteractiveQuestionnaire(adrTitle, changeAnalysis string) (*QuestionnaireResponse, error) {
+// conductInteractiveQuestionnaire asks targeted questions based on the ADR context.
+// Answers in prefill, if any, are offered for editing.
+func conductInteractiveQuestionnaire(adrTitle, changeAnalysis string, prefill *QuestionnaireResponse) (*QuestionnaireResponse, error) {
 	responses := &QuestionnaireResponse{}
+	if prefill != nil {
+		*responses = *prefill
+	}
 
 	fmt.Println("I'll ask you some questions to help generate comprehensive ADR content.")
 	fmt.Println("You can skip questions by leaving them blank if not applicable.")
@@ -951,6 +1122,67 @@ func conductInteractiveQuestionnaire(adrTitle, changeAnalysis string) (*Question
 	return responses, nil
 }
 
+// readTranscript reads a conversation transcript from a file, or from stdin for "-"
+func readTranscript(path string) (*transcript.Transcript, error) {
+	if path == "-" {
+		conversation, err := transcript.Read(os.Stdin, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read transcript from stdin: %w", err)
+		}
+		return conversation, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer file.Close()
+
+	conversation, err := transcript.Read(file, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transcript %s: %w", path, err)
+	}
+	return conversation, nil
+}
+
+// prefillFromTranscript extracts the decision context of a conversation as
+// questionnaire answers, summarized by the AI provider when it can generate
+// text and found with keyword heuristics otherwise
+func prefillFromTranscript(aiManager *ai.Manager, conversation *transcript.Transcript, adrTitle string) (*QuestionnaireResponse, *ai.TokenUsage) {
+	fmt.Printf("📜 Read %d messages from the %s transcript\n", len(conversation.Messages), conversation.Format)
+
+	var decision transcript.Decision
+	var tokenUsage *ai.TokenUsage
+	if aiManager.CanGenerate() {
+		fmt.Println("🤖 Summarizing the conversation...")
+		prompt := templates.TranscriptSummaryPrompt(adrTitle, transcript.Render(conversation, maxTranscriptPromptSize))
+		result, err := aiManager.AnalyzeChangesWithTokens(prompt)
+		if err != nil {
+			fmt.Printf("⚠️  AI summarization failed, using keyword extraction: %v\n", err)
+		} else {
+			decision = templates.ParseTranscriptSummary(result.Response)
+			tokenUsage = &result.TokenUsage
+		}
+	}
+	if decision.IsEmpty() {
+		decision = transcript.Extract(conversation)
+	}
+
+	if decision.IsEmpty() {
+		fmt.Println("ℹ️  No decision context found in the transcript")
+		return nil, tokenUsage
+	}
+	fmt.Println("✅ Answers pre-filled from the transcript. Review and edit them below.")
+	return &QuestionnaireResponse{
+		ProblemContext:         decision.Problem,
+		DecisionMade:           decision.Decision,
+		WhyThisSolution:        decision.Rationale,
+		AlternativesConsidered: decision.Alternatives,
+		TradeOffs:              decision.TradeOffs,
+		FutureImplications:     decision.Future,
+	}, tokenUsage
+}
+
 // ContextualQuestions holds prompts tailored to the specific change type
 type ContextualQuestions struct {
 	ProblemPrompt     string
@@ -1029,21 +1261,21 @@ func getContextualQuestions(title, analysis string) ContextualQuestions {
 }
 
 // generateADRContent uses AI to create complete ADR content and tracks token usage
-func generateADRContent(aiManager *ai.Manager, targetADR *adr.ADR, changes, changeAnalysis string, responses *QuestionnaireResponse) (string, *ai.TokenUsage, error) {
-	if !aiManager.IsAvailable() {
+func generateADRContent(aiManager *ai.Manager, targetADR *adr.ADR, changes, changeAnalysis, sessionContext string, changeSignals *signals.Signals, responses *QuestionnaireResponse) (string, *ai.TokenUsage, error) {
+	if !aiManager.CanGenerate() {
 		cfg, _ := config.Load() // Load config for template system
-		return generateFallbackContent(targetADR, responses, cfg), nil, nil
+		return addAPIConsequences(generateFallbackContent(targetADR, responses, cfg), changeSignals), nil, nil
 	}
 
 	// Create comprehensive prompt combining all information
-	prompt := createComprehensiveADRPrompt(targetADR, changes, changeAnalysis, responses)
+	prompt := createComprehensiveADRPrompt(targetADR, changes, changeAnalysis, sessionContext, changeSignals, responses)
 
 	// Get AI-generated content with token tracking
 	result, err := aiManager.AnalyzeChangesWithTokens(prompt)
 	if err != nil {
 		fmt.Printf("⚠️  AI generation failed, using fallback: %v\n", err)
 		cfg, _ := config.Load() // Load config for template system
-		return generateFallbackContent(targetADR, responses, cfg), nil, nil
+		return addAPIConsequences(generateFallbackContent(targetADR, responses, cfg), changeSignals), nil, nil
 	}
 
 	// Clean up and format the AI response
@@ -1051,7 +1283,7 @@ func generateADRContent(aiManager *ai.Manager, targetADR *adr.ADR, changes, chan
 }
 
 // createComprehensiveADRPrompt builds a detailed prompt for AI content generation
-func createComprehensiveADRPrompt(targetADR *adr.ADR, changes, changeAnalysis string, responses *QuestionnaireResponse) string {
+func createComprehensiveADRPrompt(targetADR *adr.ADR, changes, changeAnalysis, sessionContext string, changeSignals *signals.Signals, responses *QuestionnaireResponse) string {
 	var promptBuilder strings.Builder
 
 	promptBuilder.WriteString("You are Dr Duck, an expert software architect. Your task is to write a complete ADR (Architectural Decision Record) in proper MADR format.\n\n")
@@ -1066,6 +1298,10 @@ func createComprehensiveADRPrompt(targetADR *adr.ADR, changes, changeAnalysis st
 		promptBuilder.WriteString(fmt.Sprintf("- Code Changes: %s\n", changes))
 	}
 
+	if summary := changeSignals.Summary(); summary != "" {
+		promptBuilder.WriteString(fmt.Sprintf("- Detected Changes:\n%s\n", summary))
+	}
+
 	promptBuilder.WriteString(fmt.Sprintf("- Problem/Context: %s\n", responses.ProblemContext))
 	promptBuilder.WriteString(fmt.Sprintf("- Decision Made: %s\n", responses.DecisionMade))
 	promptBuilder.WriteString(fmt.Sprintf("- Rationale: %s\n", responses.WhyThisSolution))
@@ -1075,6 +1311,9 @@ func createComprehensiveADRPrompt(targetADR *adr.ADR, changes, changeAnalysis st
 	if responses.AdditionalContext != "" {
 		promptBuilder.WriteString(fmt.Sprintf("- Additional Context: %s\n", responses.AdditionalContext))
 	}
+	if sessionContext != "" {
+		promptBuilder.WriteString(fmt.Sprintf("- AI Session Context (what the developer and assistant discussed):\n%s\n", sessionContext))
+	}
 
 	promptBuilder.WriteString("\nGenerate ONLY the ADR content in this exact format:\n\n")
 	promptBuilder.WriteString("# [title]\n\n")
@@ -1096,6 +1335,12 @@ func createComprehensiveADRPrompt(targetADR *adr.ADR, changes, changeAnalysis st
 	promptBuilder.WriteString("## Alternatives Considered\n\n")
 	promptBuilder.WriteString("[Other options that were evaluated]\n\n")
 	
+	if changeSignals != nil && len(changeSignals.API) > 0 {
+		promptBuilder.WriteString("In the Consequences section, add a \"### Public API Changes\" subsection that lists each of these exported API changes and who is affected:\n")
+		promptBuilder.WriteString(apidiff.Summarize(changeSignals.API))
+		promptBuilder.WriteString("\n\n")
+	}
+
 	promptBuilder.WriteString("Write in clear, professional language. Replace [brackets] with actual content. Do NOT include analysis comments or suggestions - just write the ADR content.")
 
 	return promptBuilder.String()
@@ -1116,6 +1361,25 @@ func generateFallbackContent(targetADR *adr.ADR, responses *QuestionnaireRespons
 	return templateContent
 }
 
+// addAPIConsequences lists detected public API changes under the Consequences
+// heading of template content, appending the section when the template has none
+func addAPIConsequences(content string, changeSignals *signals.Signals) string {
+	if changeSignals == nil || len(changeSignals.API) == 0 {
+		return content
+	}
+
+	section := "### Public API Changes\n\n" + apidiff.Summarize(changeSignals.API) + "\n"
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "## Consequences") {
+			rest := strings.Join(lines[i+1:], "\n")
+			return strings.Join(lines[:i+1], "\n") + "\n\n" + section + rest
+		}
+	}
+
+	return strings.TrimRight(content, "\n") + "\n\n## Consequences\n\n" + section
+}
+
 // formatGeneratedContent cleans up and formats AI-generated content
 func formatGeneratedContent(content string, targetADR *adr.ADR) string {
 	// If AI returned complete markdown with front matter, use as-is
@@ -1222,9 +1486,16 @@ func editContentInteractively(content string) (string, error) {
 	defer os.Remove(tempFile) // Clean up
 
 	// Open in editor
+	input, err := editorInput()
+	if err != nil {
+		return "", err
+	}
+	if input != os.Stdin {
+		defer input.Close()
+	}
 	editor := getEditor() // Use same function from edit.go
 	editorCmd := exec.Command(editor, tempFile)
-	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdin = input
 	editorCmd.Stdout = os.Stdout
 	editorCmd.Stderr = os.Stderr
 
@@ -1241,6 +1512,20 @@ func editContentInteractively(content string) (string, error) {
 	return string(editedContent), nil
 }
 
+// editorInput returns the terminal the editor reads keys from. With
+// --transcript - stdin is the piped transcript, so the controlling terminal is
+// opened instead; the caller closes it.
+func editorInput() (*os.File, error) {
+	if isTerminal(os.Stdin) {
+		return os.Stdin, nil
+	}
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open an editor: stdin is not a terminal and /dev/tty is unavailable: %w", err)
+	}
+	return tty, nil
+}
+
 // saveCompletedADR writes the completed content to the ADR file
 func saveCompletedADR(targetADR *adr.ADR, content string) error {
 	// Ensure directory exists
@@ -1373,7 +1658,10 @@ func renameADRFile(adrManager *adr.Manager, targetADR *adr.ADR, newTitle string)
 	}
 	
 	// Generate new filename
-	newFilename := fmt.Sprintf("%04d-%s.md", targetADR.ID, strings.ReplaceAll(strings.ToLower(newTitle), " ", "-"))
+	newFilename, err := adr.FileName(targetADR.ID, newTitle)
+	if err != nil {
+		return err
+	}
 	newFilePath := filepath.Join(filepath.Dir(targetADR.FilePath), newFilename)
 	
 	// Update title in content
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/SilverFlin/DrDuck/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+var debtCmd = &cobra.Command{
+	Use:   "debt",
+	Short: "List decisions that were flagged as needing an ADR but never documented",
+	Long: `List every commit range the analyzer flagged as needing an ADR that was never
+resolved, grouped by author and age. Debt is kept in the cache past its normal
+max age until it is resolved, either by 'drduck complete-adr' or by linking an
+existing ADR with 'drduck debt resolve'.`,
+	RunE: runDebt,
+}
+
+var debtResolveCmd = &cobra.Command{
+	Use:   "resolve <hash> <adr-id>",
+	Short: "Resolve decision debt by linking an existing ADR",
+	Long:  `Resolve decision debt by linking an existing ADR. The hash may be any unique prefix, as shown by 'drduck debt'.`,
+	Args:  cobra.ExactArgs(2),
+	RunE:  runDebtResolve,
+}
+
+var debtAuthor string
+
+// debtAgeBuckets groups debt by how long the decision has gone undocumented
+var debtAgeBuckets = []struct {
+	label  string
+	maxAge time.Duration
+}{
+	{"< 1 week", 7 * 24 * time.Hour},
+	{"1-4 weeks", 28 * 24 * time.Hour},
+	{"1-3 months", 90 * 24 * time.Hour},
+	{"> 3 months", 0},
+}
+
+func init() {
+	rootCmd.AddCommand(debtCmd)
+	debtCmd.AddCommand(debtResolveCmd)
+
+	debtCmd.Flags().StringVar(&debtAuthor, "author", "", "Only list debt of authors whose name contains this text")
+}
+
+func runDebt(cmd *cobra.Command, args []string) error {
+	_, cacheManager, err := loadCacheManager()
+	if err != nil {
+		return err
+	}
+
+	items, err := cacheManager.Debt()
+	if err != nil {
+		return fmt.Errorf("failed to load decision debt: %w", err)
+	}
+
+	if debtAuthor != "" {
+		var filtered []cache.DebtItem
+		for _, item := range items {
+			if strings.Contains(strings.ToLower(item.Author), strings.ToLower(debtAuthor)) {
+				filtered = append(filtered, item)
+			}
+		}
+		items = filtered
+	}
+
+	if len(items) == 0 {
+		fmt.Println("✅ No decision debt: every change flagged as needing an ADR has one")
+		return nil
+	}
+
+	now := time.Now()
+	byAuthor := make(map[string][]cache.DebtItem)
+	var authors []string
+	for _, item := range items {
+		if _, exists := byAuthor[item.Author]; !exists {
+			authors = append(authors, item.Author)
+		}
+		byAuthor[item.Author] = append(byAuthor[item.Author], item)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if len(byAuthor[authors[i]]) != len(byAuthor[authors[j]]) {
+			return len(byAuthor[authors[i]]) > len(byAuthor[authors[j]])
+		}
+		return authors[i] < authors[j]
+	})
+
+	fmt.Printf("🦆 Decision debt: %d undocumented decision(s)\n\n", len(items))
+
+	for _, author := range authors {
+		authorItems := byAuthor[author]
+		fmt.Printf("👤 %s (%d)\n", author, len(authorItems))
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "  HASH\tAGE\tBRANCH\tCOMMITS\tTITLE")
+		for _, item := range authorItems {
+			analysis := item.Entry.Analysis
+			branch := analysis.Branch
+			if branch == "" {
+				branch = "-"
+			}
+			fmt.Fprintf(writer, "  %s\t%s\t%s\t%s\t%s\n",
+				shortHash(item.Entry.ContentHash), formatAge(now.Sub(item.Since)), branch, describeCommits(analysis), analysis.Title)
+		}
+		writer.Flush()
+		fmt.Println()
+	}
+
+	fmt.Println("📅 By age:")
+	counts := make([]int, len(debtAgeBuckets))
+	for _, item := range items {
+		counts[debtAgeBucket(now.Sub(item.Since))]++
+	}
+	for i, bucket := range debtAgeBuckets {
+		fmt.Printf("   %-11s %d\n", bucket.label+":", counts[i])
+	}
+
+	fmt.Println()
+	fmt.Println("💡 Write the ADR with 'drduck new', or link an existing one with 'drduck debt resolve <hash> <adr-id>'")
+	return nil
+}
+
+func runDebtResolve(cmd *cobra.Command, args []string) error {
+	cfg, cacheManager, err := loadCacheManager()
+	if err != nil {
+		return err
+	}
+
+	return resolveCachedAnalysis(cfg, cacheManager, args[0], args[1])
+}
+
+// debtAgeBucket returns the index of the age bucket for an age
+func debtAgeBucket(age time.Duration) int {
+	for i, bucket := range debtAgeBuckets {
+		if bucket.maxAge == 0 || age < bucket.maxAge {
+			return i
+		}
+	}
+	return len(debtAgeBuckets) - 1
+}
+
+// formatAge renders an age in days, weeks or months
+func formatAge(age time.Duration) string {
+	days := int(age.Hours() / 24)
+	switch {
+	case days < 1:
+		return "today"
+	case days < 14:
+		return fmt.Sprintf("%dd", days)
+	case days < 60:
+		return fmt.Sprintf("%dw", days/7)
+	default:
+		return fmt.Sprintf("%dmo", days/30)
+	}
+}
+
+// describeCommits summarizes the commits covered by an analysis
+func describeCommits(analysis *cache.AnalysisResult) string {
+	switch len(analysis.Commits) {
+	case 0:
+		if analysis.CommitRange == "HEAD" {
+			return "uncommitted"
+		}
+		return analysis.CommitRange
+	case 1:
+		return shortCommit(analysis.Commits[0].Hash)
+	default:
+		oldest := analysis.Commits[len(analysis.Commits)-1]
+		newest := analysis.Commits[0]
+		return fmt.Sprintf("%s..%s (%d)", shortCommit(oldest.Hash), shortCommit(newest.Hash), len(analysis.Commits))
+	}
+}
+
+// shortCommit abbreviates a commit hash for display
+func shortCommit(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
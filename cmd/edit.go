@@ -89,7 +89,7 @@ func runEdit(cmd *cobra.Command, args []string) error {
 
 	// Update status if requested
 	if editStatus != "" {
-		newStatus, err := parseStatus(editStatus)
+		newStatus, err := adr.ParseStatus(editStatus)
 		if err != nil {
 			return fmt.Errorf("invalid status: %w", err)
 		}
@@ -132,22 +132,3 @@ func getEditor() string {
 	// Fallback
 	return "vi"
 }
-
-func parseStatus(statusStr string) (adr.Status, error) {
-	statusStr = strings.ToLower(strings.TrimSpace(statusStr))
-	
-	switch statusStr {
-	case "draft":
-		return adr.StatusDraft, nil
-	case "in-progress", "inprogress", "progress":
-		return adr.StatusInProgress, nil
-	case "accepted", "accept":
-		return adr.StatusAccepted, nil
-	case "rejected", "reject":
-		return adr.StatusRejected, nil
-	case "superseded", "supersede":
-		return adr.StatusSuperseded, nil
-	default:
-		return "", fmt.Errorf("unknown status '%s'. Valid statuses: draft, in-progress, accepted, rejected, superseded", statusStr)
-	}
-}
\ No newline at end of file
@@ -96,6 +96,7 @@ func setupInteractive(cfg *config.Config) error {
 	var adrTemplate string
 	var preCommitHook bool
 	var prePushHook bool
+	var commitMsgHook bool
 	var customDocPath string
 	var separateRepoURL string
 
@@ -107,6 +108,7 @@ func setupInteractive(cfg *config.Config) error {
 				Options(
 					huh.NewOption("Claude Code CLI", "claude-code").Selected(cfg.AIProvider == "claude-code"),
 					huh.NewOption("Cursor", "cursor").Selected(cfg.AIProvider == "cursor"),
+					huh.NewOption("None - rules engine only (no AI)", "rules").Selected(cfg.AIProvider == "rules"),
 				).
 				Value(&aiProvider),
 		),
@@ -150,6 +152,13 @@ func setupInteractive(cfg *config.Config) error {
 				Value(&prePushHook).
 				Affirmative("Yes").
 				Negative("No"),
+
+			huh.NewConfirm().
+				Title("Install commit-msg hook?").
+				Description("Suggests and validates 'ADR: 0007' trailers linking commits to ADRs").
+				Value(&commitMsgHook).
+				Affirmative("Yes").
+				Negative("No"),
 		),
 	)
 
@@ -193,6 +202,7 @@ func setupInteractive(cfg *config.Config) error {
 	cfg.ADRTemplate = adrTemplate
 	cfg.Hooks.PreCommit = preCommitHook
 	cfg.Hooks.PrePush = prePushHook
+	cfg.Hooks.CommitMsg = commitMsgHook
 
 	if docStorage == "same-repo" {
 		cfg.DocPath = customDocPath
@@ -262,7 +272,7 @@ This section will be automatically updated by DrDuck as new ADRs are created.
 }
 
 func setupGitHooks(cfg *config.Config) error {
-	if !cfg.Hooks.PreCommit && !cfg.Hooks.PrePush {
+	if !cfg.Hooks.PreCommit && !cfg.Hooks.PrePush && !cfg.Hooks.CommitMsg {
 		return nil // No hooks to install
 	}
 
@@ -300,28 +310,8 @@ fi
 # Always exit successfully - pre-commit never blocks
 exit 0
 `
-		hookPath := filepath.Join(hooksDir, "pre-commit")
-		if err := os.WriteFile(hookPath, []byte(preCommitHook), 0755); err != nil {
-			return fmt.Errorf("failed to create pre-commit hook: %w", err)
-		}
-
-		// Link to git hooks directory if it exists
-		gitHooksDir := ".git/hooks"
-		if _, err := os.Stat(gitHooksDir); err == nil {
-			gitHookPath := filepath.Join(gitHooksDir, "pre-commit")
-			if err := os.Remove(gitHookPath); err != nil && !os.IsNotExist(err) {
-				return fmt.Errorf("failed to remove existing pre-commit hook: %w", err)
-			}
-			if err := os.Symlink(hookPath, gitHookPath); err != nil {
-				// Fallback to copying if symlink fails
-				hookContent, err := os.ReadFile(hookPath)
-				if err != nil {
-					return err
-				}
-				if err := os.WriteFile(gitHookPath, hookContent, 0755); err != nil {
-					return fmt.Errorf("failed to install pre-commit hook: %w", err)
-				}
-			}
+		if err := installGitHook(hooksDir, "pre-commit", preCommitHook); err != nil {
+			return err
 		}
 	}
 
@@ -351,30 +341,60 @@ fi
 echo "✅ All ADR requirements satisfied!"
 exit 0
 `
-		hookPath := filepath.Join(hooksDir, "pre-push")
-		if err := os.WriteFile(hookPath, []byte(prePushHook), 0755); err != nil {
-			return fmt.Errorf("failed to create pre-push hook: %w", err)
+		if err := installGitHook(hooksDir, "pre-push", prePushHook); err != nil {
+			return err
 		}
+	}
 
-		// Link to git hooks directory if it exists
-		gitHooksDir := ".git/hooks"
-		if _, err := os.Stat(gitHooksDir); err == nil {
-			gitHookPath := filepath.Join(gitHooksDir, "pre-push")
-			if err := os.Remove(gitHookPath); err != nil && !os.IsNotExist(err) {
-				return fmt.Errorf("failed to remove existing pre-push hook: %w", err)
-			}
-			if err := os.Symlink(hookPath, gitHookPath); err != nil {
-				// Fallback to copying if symlink fails
-				hookContent, err := os.ReadFile(hookPath)
-				if err != nil {
-					return err
-				}
-				if err := os.WriteFile(gitHookPath, hookContent, 0755); err != nil {
-					return fmt.Errorf("failed to install pre-push hook: %w", err)
-				}
+	if cfg.Hooks.CommitMsg {
+		commitMsgHook := `#!/bin/sh
+# DrDuck commit-msg hook
+# Suggests an "ADR: 0007" trailer for flagged changes and rejects trailers
+# referencing ADRs that do not exist
+
+# Check if DrDuck is available
+if ! command -v drduck >/dev/null 2>&1; then
+    exit 0
+fi
+
+if ! drduck validate --commit-msg "$1"; then
+    echo ""
+    echo "💡 Fix the ADR trailer or bypass this check: git commit --no-verify"
+    exit 1
+fi
+
+exit 0
+`
+		if err := installGitHook(hooksDir, "commit-msg", commitMsgHook); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// installGitHook writes a hook script to the DrDuck hooks directory and links
+// it into .git/hooks when the project is a git repository
+func installGitHook(hooksDir, name, content string) error {
+	hookPath := filepath.Join(hooksDir, name)
+	if err := os.WriteFile(hookPath, []byte(content), 0755); err != nil {
+		return fmt.Errorf("failed to create %s hook: %w", name, err)
+	}
+
+	// Link to git hooks directory if it exists
+	gitHooksDir := ".git/hooks"
+	if _, err := os.Stat(gitHooksDir); err == nil {
+		gitHookPath := filepath.Join(gitHooksDir, name)
+		if err := os.Remove(gitHookPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove existing %s hook: %w", name, err)
+		}
+		if err := os.Symlink(hookPath, gitHookPath); err != nil {
+			// Fallback to copying if symlink fails
+			if err := os.WriteFile(gitHookPath, []byte(content), 0755); err != nil {
+				return fmt.Errorf("failed to install %s hook: %w", name, err)
 			}
 		}
 	}
 
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/SilverFlin/DrDuck/internal/config"
+	"github.com/SilverFlin/DrDuck/internal/lsp"
+	"github.com/spf13/cobra"
+)
+
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Run a language server for ADR files over stdio",
+	Long: `Run a Language Server Protocol server on stdin/stdout so any editor can check
+ADR files while they are written.
+
+Diagnostics:
+  • Missing or invalid front matter and unknown status values
+  • Missing sections and unfilled placeholders, the checks 'drduck accept' runs
+  • ADR-NNNN references to ADRs that do not exist
+
+It also offers go-to-definition and hover previews on ADR-NNNN references, and
+completion for statuses in the front matter and for ADR IDs after "ADR-".
+
+Editors start the server themselves from the project root; see the README for
+VS Code, Neovim and Helix setup.`,
+	RunE: runLSP,
+}
+
+func init() {
+	rootCmd.AddCommand(lspCmd)
+}
+
+func runLSP(cmd *cobra.Command, args []string) error {
+	// Check if project is initialized
+	initialized, err := config.IsInitialized()
+	if err != nil {
+		return fmt.Errorf("failed to check initialization status: %w", err)
+	}
+
+	if !initialized {
+		return fmt.Errorf("❌ DrDuck is not initialized in this project. Run 'drduck init' first")
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Stdout carries the protocol, keep stray prints off it
+	protocolOut := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = protocolOut }()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return lsp.NewServer(cfg, buildVersion).Serve(ctx, os.Stdin, protocolOut)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/SilverFlin/DrDuck/internal/config"
+	"github.com/SilverFlin/DrDuck/internal/mcp"
+	"github.com/spf13/cobra"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Run an MCP server exposing ADR tools over stdio",
+	Long: `Run a Model Context Protocol server on stdin/stdout so AI coding assistants
+can read and write the project's ADRs during a session.
+
+Tools:
+  list_adrs               List ADRs, optionally by status or tag
+  get_adr                 Get the full Markdown of an ADR
+  search_adrs             Search ADRs, with the same filters as 'drduck search'
+  create_adr_draft        Create a Draft ADR and fill in its sections
+  check_changes_need_adr  Run the git hook validation on the current changes
+
+The assistant starts the server itself; register it once:
+  claude mcp add drduck -- drduck mcp
+
+or in .cursor/mcp.json:
+  {"mcpServers": {"drduck": {"command": "drduck", "args": ["mcp"]}}}`,
+	RunE: runMCP,
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
+
+func runMCP(cmd *cobra.Command, args []string) error {
+	// Check if project is initialized
+	initialized, err := config.IsInitialized()
+	if err != nil {
+		return fmt.Errorf("failed to check initialization status: %w", err)
+	}
+
+	if !initialized {
+		return fmt.Errorf("❌ DrDuck is not initialized in this project. Run 'drduck init' first")
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Stdout carries the protocol. Code shared with the CLI prints progress
+	// with fmt.Print, so send that to stderr instead of corrupting messages.
+	protocolOut := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = protocolOut }()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return mcp.NewServer(cfg, buildVersion).Serve(ctx, os.Stdin, protocolOut)
+}
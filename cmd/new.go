@@ -13,18 +13,23 @@ import (
 var newCmd = &cobra.Command{
 	Use:   "new",
 	Short: "Create a new Architectural Decision Record (ADR)",
-	Long:  `Create a new Architectural Decision Record (ADR) with the specified name using the configured template.`,
+	Long: `Create a new Architectural Decision Record (ADR) with the specified name using the configured template.
+
+Existing ADRs similar to the new name are offered to be amended or superseded
+instead, so the same decision is not recorded twice. Use --force to skip this check.`,
 	RunE:  runNew,
 }
 
 var (
-	adrName string
+	adrName  string
+	newForce bool
 )
 
 func init() {
 	rootCmd.AddCommand(newCmd)
 	newCmd.Flags().StringVarP(&adrName, "name", "n", "", "Name of the ADR (required)")
 	newCmd.MarkFlagRequired("name")
+	newCmd.Flags().BoolVar(&newForce, "force", false, "Create the ADR without checking for similar existing ones")
 }
 
 func runNew(cmd *cobra.Command, args []string) error {
@@ -67,12 +72,38 @@ func runNew(cmd *cobra.Command, args []string) error {
 		fmt.Printf("ℹ️  %s not available - creating basic ADR template\n", aiManager.GetProviderName())
 	}
 
+	// Offer similar existing ADRs before writing a duplicate
+	choice := relatedChoice{Action: relatedCreate}
+	if !newForce {
+		choice, err = checkRelatedADRs(cfg, manager, cleanName)
+		if err != nil {
+			return fmt.Errorf("%w (use --force to skip the similarity check)", err)
+		}
+	}
+
+	switch choice.Action {
+	case relatedCancel:
+		fmt.Println("👋 ADR creation cancelled")
+		return nil
+	case relatedAmend:
+		fmt.Printf("📝 Amend ADR-%04d instead:\n", choice.Target.ID)
+		fmt.Printf("   drduck edit %04d\n", choice.Target.ID)
+		fmt.Printf("   drduck complete-adr %04d  # AI-assisted\n", choice.Target.ID)
+		return nil
+	}
+
 	// Create the ADR
 	newADR, err := manager.Create(cleanName)
 	if err != nil {
 		return fmt.Errorf("failed to create ADR: %w", err)
 	}
 
+	if choice.Action == relatedSupersede {
+		if err := supersedeADR(manager, choice.Target, newADR); err != nil {
+			return err
+		}
+	}
+
 	fmt.Printf("✅ ADR-%04d created successfully!\n", newADR.ID)
 	fmt.Printf("📝 File: %s\n", newADR.FilePath)
 	fmt.Printf("🔧 Status: %s\n", newADR.Status)
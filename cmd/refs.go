@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/config"
+	"github.com/SilverFlin/DrDuck/internal/refs"
+	"github.com/spf13/cobra"
+)
+
+var refsCmd = &cobra.Command{
+	Use:   "refs",
+	Short: "Index ADR references in code comments",
+	Long: `Scan the repository for comments referencing ADRs, such as "// ADR-0007" or
+"# adr: 7", and show which code implements which decision.
+
+Also reports references to ADRs that are missing, rejected or superseded, and
+accepted ADRs that no code references.
+
+Examples:
+  drduck refs            # Show the code ↔ ADR index and any issues
+  drduck refs --check    # Exit with an error when there are issues (for CI)`,
+	RunE: runRefs,
+}
+
+var refsCheck bool
+
+func init() {
+	rootCmd.AddCommand(refsCmd)
+	refsCmd.Flags().BoolVar(&refsCheck, "check", false, "Exit with an error when references are missing or stale")
+}
+
+func runRefs(cmd *cobra.Command, args []string) error {
+	// Check if project is initialized
+	initialized, err := config.IsInitialized()
+	if err != nil {
+		return fmt.Errorf("failed to check initialization status: %w", err)
+	}
+
+	if !initialized {
+		return fmt.Errorf("❌ DrDuck is not initialized in this project. Run 'drduck init' first")
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	report, err := refs.CheckProject(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to scan ADR references: %w", err)
+	}
+
+	adrs, err := adr.NewManager(cfg).List()
+	if err != nil {
+		return fmt.Errorf("failed to list ADRs: %w", err)
+	}
+	titles := make(map[int]*adr.ADR, len(adrs))
+	for _, a := range adrs {
+		titles[a.ID] = a
+	}
+
+	if len(report.ByADR) == 0 {
+		fmt.Println("🔗 No code references any ADR yet.")
+		fmt.Println("💡 Add a comment such as '// ADR-0001' next to the code implementing a decision")
+	} else {
+		fmt.Printf("🔗 %d ADR(s) referenced from code:\n\n", len(report.ByADR))
+		for _, id := range report.ADRIDs() {
+			a := titles[id]
+			fmt.Printf("%s ADR-%04d: %s\n", getStatusIcon(a.Status), a.ID, a.Title)
+			for _, ref := range report.ByADR[id] {
+				fmt.Printf("   %s\n", ref.Location())
+			}
+			fmt.Println()
+		}
+	}
+
+	if !report.HasProblems() {
+		fmt.Println("✅ No missing or stale ADR references")
+		return nil
+	}
+
+	if len(report.Missing) > 0 {
+		fmt.Println("❓ References to ADRs that do not exist:")
+		for _, ref := range report.Missing {
+			fmt.Printf("   %s  ADR-%04d  %s\n", ref.Location(), ref.ADRID, ref.Text)
+		}
+		fmt.Println()
+	}
+
+	if len(report.Stale) > 0 {
+		fmt.Println("⏭️  References to rejected or superseded ADRs:")
+		for _, ref := range report.Stale {
+			fmt.Printf("   %s  ADR-%04d (%s)\n", ref.Location(), ref.ADRID, strings.ToLower(string(ref.Status)))
+		}
+		fmt.Println()
+	}
+
+	if len(report.Unreferenced) > 0 {
+		fmt.Println("🔍 Accepted ADRs that no code references:")
+		for _, a := range report.Unreferenced {
+			fmt.Printf("   ADR-%04d: %s\n", a.ID, a.Title)
+		}
+		fmt.Println()
+	}
+
+	if refsCheck {
+		return fmt.Errorf("ADR reference issues found")
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/config"
+	"github.com/SilverFlin/DrDuck/internal/search"
+	"github.com/spf13/cobra"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Search ADRs",
+	Long: `Search the text of all ADRs, best matches first, with highlighted excerpts.
+
+The query can contain field filters:
+  status:<status>      Only ADRs with this status (repeat to allow several)
+  tag:<tag>            Only ADRs with this tag in their front matter (repeat to require several)
+  after:YYYY-MM-DD     Only ADRs dated on or after this day
+  before:YYYY-MM-DD    Only ADRs dated before this day
+
+The index is kept in .drduck/search/ and only changed ADR files are re-indexed.
+
+Examples:
+  drduck search redis caching
+  drduck search status:accepted tag:database after:2025-01-01
+  drduck search "message queue" status:proposed status:in-progress`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+var (
+	searchLimit   int
+	searchRebuild bool
+)
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 10, "Maximum number of results (0 for all)")
+	searchCmd.Flags().BoolVar(&searchRebuild, "rebuild", false, "Re-index every ADR instead of only changed ones")
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	// Check if project is initialized
+	initialized, err := config.IsInitialized()
+	if err != nil {
+		return fmt.Errorf("failed to check initialization status: %w", err)
+	}
+
+	if !initialized {
+		return fmt.Errorf("❌ DrDuck is not initialized in this project. Run 'drduck init' first")
+	}
+
+	query, err := search.ParseQuery(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	index, err := openSearchIndex(cfg, searchRebuild)
+	if err != nil {
+		return err
+	}
+
+	results := index.Search(query, searchLimit)
+	if len(results) == 0 {
+		fmt.Println("🔍 No matching ADRs found")
+		return nil
+	}
+
+	color := isTerminal(os.Stdout)
+	for i, result := range results {
+		if i > 0 {
+			fmt.Println()
+		}
+
+		entry := result.Entry
+		fmt.Printf("%s ADR-%04d: %s\n", getStatusIcon(adr.Status(entry.Status)), entry.ID,
+			emphasize(entry.Title, result.TitleHighlights, color))
+
+		details := []string{entry.Status, entry.Date.Format("2006-01-02")}
+		if len(entry.Tags) > 0 {
+			details = append(details, "tags: "+strings.Join(entry.Tags, ", "))
+		}
+		if result.Score > 0 {
+			details = append(details, fmt.Sprintf("%d%% match", int(result.Score*100)))
+		}
+		fmt.Printf("   %s\n", strings.Join(details, " · "))
+
+		for _, snippet := range result.Snippets {
+			fmt.Printf("   %s: %s\n", snippet.Section, emphasize(snippet.Text, snippet.Highlights, color))
+		}
+	}
+
+	return nil
+}
+
+// openSearchIndex loads the search index and brings it up to date with the
+// ADR files, saving it if anything changed
+func openSearchIndex(cfg *config.Config, rebuild bool) (*search.Index, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config directory: %w", err)
+	}
+
+	adrs, err := adr.NewManager(cfg).List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ADRs: %w", err)
+	}
+
+	return search.Load(configDir, adrs, rebuild)
+}
+
+// emphasize marks the highlighted spans of a text, in bold yellow on a
+// terminal and with asterisks otherwise
+func emphasize(text string, spans []search.Span, color bool) string {
+	before, after := "**", "**"
+	if color {
+		before, after = "\033[1;33m", "\033[0m"
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, span := range spans {
+		b.WriteString(text[last:span.Start])
+		b.WriteString(before + text[span.Start:span.End] + after)
+		last = span.End
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// isTerminal reports whether a file is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/SilverFlin/DrDuck/internal/web"
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Browse ADRs in a local web UI",
+	Long: `Start a local web server to browse, search and read ADRs in the browser.
+
+The UI shows the same dashboard as 'drduck status', the analysis cache and
+decision debt, and lets you change an ADR's status following the same rules as
+'drduck set-status' and 'drduck accept'. Open pages reload when ADR files change.
+
+With --api, a versioned JSON API (list, get, create, change status, validate
+current changes, search) is also served under /api/v1, described by
+/api/v1/openapi.json.
+
+The server listens on localhost only unless --host is given. On another
+address, changes need the token given by --token or the DRDUCK_API_TOKEN
+environment variable, and the server refuses to start without one. Once a
+token is set, the UI asks for it on its sign-in page before changing status,
+and API requests that change ADRs or run validation must send
+"Authorization: Bearer <token>".
+
+Examples:
+  drduck serve                 # http://localhost:8080
+  drduck serve --port 3000
+  drduck serve --api           # Also serve the JSON API under /api/v1
+  DRDUCK_API_TOKEN=secret drduck serve --host 0.0.0.0  # Share on the local network
+  DRDUCK_API_TOKEN=secret drduck serve --api --host 0.0.0.0`,
+	RunE: runServe,
+}
+
+var (
+	servePort  int
+	serveHost  string
+	serveAPI   bool
+	serveToken string
+)
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().IntVarP(&servePort, "port", "p", 8080, "Port to listen on")
+	serveCmd.Flags().StringVar(&serveHost, "host", "localhost", "Address to listen on")
+	serveCmd.Flags().BoolVar(&serveAPI, "api", false, "Also serve the JSON API under "+web.APIPrefix)
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "Token required to change ADRs from the UI or the API (default $DRDUCK_API_TOKEN)")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	token := serveToken
+	if token == "" {
+		token = os.Getenv("DRDUCK_API_TOKEN")
+	}
+	if token == "" && !web.IsLoopback(serveHost) {
+		return fmt.Errorf("serving on %s lets anyone on the network change ADRs; set --token or DRDUCK_API_TOKEN", serveHost)
+	}
+
+	cfg, cacheManager, err := loadCacheManager()
+	if err != nil {
+		return err
+	}
+
+	server, err := web.New(web.Options{
+		Config: cfg,
+		Cache:  cacheManager,
+		Host:   serveHost,
+		API:    serveAPI,
+		Token:  token,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create web server: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	go server.Watch(ctx, time.Second)
+
+	addr := net.JoinHostPort(serveHost, strconv.Itoa(servePort))
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           server.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	fmt.Printf("🦆 DrDuck UI running at http://%s\n", addr)
+	if serveAPI {
+		fmt.Printf("🔌 API at http://%s%s (spec: %s/openapi.json)\n", addr, web.APIPrefix, web.APIPrefix)
+	}
+	fmt.Println("   Press Ctrl+C to stop")
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("web server failed: %w", err)
+	}
+
+	fmt.Println("👋 Stopped")
+	return nil
+}
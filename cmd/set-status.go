@@ -53,7 +53,7 @@ func runSetStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	// Parse status
-	newStatus, err := parseStatus(args[1])
+	newStatus, err := adr.ParseStatus(args[1])
 	if err != nil {
 		return fmt.Errorf("invalid status: %w", err)
 	}
@@ -82,13 +82,11 @@ func runSetStatus(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Validate status transition
-	if err := validateStatusTransition(targetADR.Status, newStatus); err != nil {
-		return fmt.Errorf("invalid status transition: %w", err)
-	}
-
 	// Special case: redirect to accept command for accepted status
 	if newStatus == adr.StatusAccepted {
+		if err := adr.ValidateTransition(targetADR.Status, newStatus); err != nil {
+			return err
+		}
 		fmt.Println("💡 For accepted status, use 'drduck accept' command which includes content validation")
 		fmt.Printf("   drduck accept %04d\n", adrID)
 		return nil
@@ -96,11 +94,12 @@ func runSetStatus(cmd *cobra.Command, args []string) error {
 
 	// Update status
 	fmt.Printf("📊 Updating status to %s...\n", newStatus)
-	if err := manager.UpdateADRStatus(adrID, newStatus); err != nil {
-		return fmt.Errorf("failed to update status: %w", err)
+	previousStatus := targetADR.Status
+	if _, err := manager.ChangeStatus(targetADR, newStatus, false); err != nil {
+		return err
 	}
 
-	fmt.Printf("✅ ADR-%04d status updated from %s to %s\n", adrID, targetADR.Status, newStatus)
+	fmt.Printf("✅ ADR-%04d status updated from %s to %s\n", adrID, previousStatus, newStatus)
 
 	// Show appropriate next steps based on new status
 	showNextSteps(newStatus, adrID)
@@ -108,46 +107,6 @@ func runSetStatus(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// validateStatusTransition checks if a status transition is valid
-func validateStatusTransition(currentStatus, newStatus adr.Status) error {
-	// Define valid transitions
-	validTransitions := map[adr.Status][]adr.Status{
-		adr.StatusDraft: {
-			adr.StatusInProgress,
-			adr.StatusAccepted,
-			adr.StatusRejected,
-		},
-		adr.StatusInProgress: {
-			adr.StatusDraft,      // Back to draft for more work
-			adr.StatusAccepted,
-			adr.StatusRejected,
-		},
-		adr.StatusAccepted: {
-			adr.StatusSuperseded, // Can only be superseded once accepted
-		},
-		adr.StatusRejected: {
-			adr.StatusDraft,      // Can reconsider
-			adr.StatusInProgress,
-		},
-		adr.StatusSuperseded: {
-			// Superseded ADRs generally shouldn't change status
-		},
-	}
-
-	allowedTransitions, exists := validTransitions[currentStatus]
-	if !exists {
-		return fmt.Errorf("unknown current status: %s", currentStatus)
-	}
-
-	for _, allowedStatus := range allowedTransitions {
-		if newStatus == allowedStatus {
-			return nil // Valid transition
-		}
-	}
-
-	return fmt.Errorf("cannot transition from %s to %s", currentStatus, newStatus)
-}
-
 // showNextSteps provides guidance based on the new status
 func showNextSteps(status adr.Status, adrID int) {
 	fmt.Println()
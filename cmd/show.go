@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var showCmd = &cobra.Command{
+	Use:   "show [adr-id]",
+	Short: "Show an ADR",
+	Long: `Show an ADR's status and content. With --commits, also list the commits that
+reference it with an "ADR: 0007" trailer, on any branch or tag.
+
+Examples:
+  drduck show 0012            # Show ADR-0012
+  drduck show 12 --commits    # Also list the commits implementing it`,
+	Args: cobra.ExactArgs(1),
+	RunE: runShow,
+}
+
+var showCommits bool
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+	showCmd.Flags().BoolVar(&showCommits, "commits", false, "List the commits carrying an ADR trailer for this ADR")
+}
+
+func runShow(cmd *cobra.Command, args []string) error {
+	// Check if project is initialized
+	initialized, err := config.IsInitialized()
+	if err != nil {
+		return fmt.Errorf("failed to check initialization status: %w", err)
+	}
+
+	if !initialized {
+		return fmt.Errorf("❌ DrDuck is not initialized in this project. Run 'drduck init' first")
+	}
+
+	// Parse ADR ID
+	adrIDStr := args[0]
+	adrID, err := strconv.Atoi(strings.TrimLeft(adrIDStr, "0"))
+	if err != nil {
+		return fmt.Errorf("invalid ADR ID: %s", adrIDStr)
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	targetADR, err := adr.NewManager(cfg).GetADRByID(adrID)
+	if err != nil {
+		return fmt.Errorf("ADR not found: %w", err)
+	}
+
+	fmt.Printf("%s ADR-%04d: %s\n", getStatusIcon(targetADR.Status), targetADR.ID, targetADR.Title)
+	fmt.Printf("📊 Status: %s\n", targetADR.Status)
+	fmt.Printf("📅 Date: %s\n", targetADR.Date.Format("2006-01-02"))
+	fmt.Printf("📄 File: %s\n", targetADR.FilePath)
+	if len(targetADR.Governs) > 0 {
+		fmt.Printf("🧭 Governs: %s\n", strings.Join(targetADR.Governs, ", "))
+	}
+
+	if showCommits {
+		return printADRCommits(targetADR)
+	}
+
+	content, err := os.ReadFile(targetADR.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read ADR file: %w", err)
+	}
+	fmt.Println()
+	fmt.Println(strings.TrimRight(string(content), "\n"))
+	return nil
+}
+
+// printADRCommits lists the commits whose ADR trailer references the ADR
+func printADRCommits(target *adr.ADR) error {
+	commits, err := adr.CommitsForADR(target.ID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if len(commits) == 0 {
+		fmt.Printf("🔗 No commits reference this ADR yet. Link one by ending its message with:\n   %s\n", adr.FormatTrailer(target.ID))
+		return nil
+	}
+
+	fmt.Printf("🔗 %d commit(s) implement this ADR:\n", len(commits))
+	for _, commit := range commits {
+		fmt.Printf("   %s  %s  %-16s %s\n", shortCommit(commit.Hash), commit.Date.Format("2006-01-02"), commit.Author, commit.Subject)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/config"
+	"github.com/SilverFlin/DrDuck/internal/similarity"
+	"github.com/charmbracelet/huh"
+)
+
+// What to do about existing ADRs similar to a proposed one
+const (
+	relatedCreate    = "create"
+	relatedAmend     = "amend"
+	relatedSupersede = "supersede"
+	relatedCancel    = "cancel"
+)
+
+// relatedChoice is the user's answer when similar ADRs already exist
+type relatedChoice struct {
+	Action string
+	Target *adr.ADR // The ADR to amend or supersede
+}
+
+// checkRelatedADRs looks for existing ADRs similar to a proposed title and
+// offers to amend or supersede one of them instead of writing a duplicate
+func checkRelatedADRs(cfg *config.Config, adrManager *adr.Manager, title string) (relatedChoice, error) {
+	create := relatedChoice{Action: relatedCreate}
+
+	adrs, err := adrManager.List()
+	if err != nil || len(adrs) == 0 {
+		return create, nil
+	}
+
+	matches, err := similarity.FindSimilar(adrs, title, cfg.Similarity)
+	if err != nil {
+		fmt.Printf("⚠️  Could not search existing ADRs: %v\n", err)
+		return create, nil
+	}
+	if len(matches) == 0 {
+		return create, nil
+	}
+
+	fmt.Println("🔎 Existing ADRs look similar:")
+	for _, match := range matches {
+		fmt.Printf("   %s ADR-%04d: %s (%s, %d%% match)\n",
+			getStatusIcon(match.ADR.Status), match.ADR.ID, match.ADR.Title, match.ADR.Status, int(match.Score*100))
+	}
+	fmt.Println()
+
+	options := []huh.Option[string]{huh.NewOption("Create a new ADR anyway", relatedCreate)}
+	targets := make(map[string]*adr.ADR)
+	for _, match := range matches {
+		key := fmt.Sprintf("%s:%d", relatedAmend, match.ADR.ID)
+		targets[key] = match.ADR
+		options = append(options, huh.NewOption(fmt.Sprintf("Amend ADR-%04d: %s instead", match.ADR.ID, match.ADR.Title), key))
+
+		// Only accepted decisions can be superseded
+		if match.ADR.Status == adr.StatusAccepted {
+			key := fmt.Sprintf("%s:%d", relatedSupersede, match.ADR.ID)
+			targets[key] = match.ADR
+			options = append(options, huh.NewOption(fmt.Sprintf("Supersede ADR-%04d: %s with the new ADR", match.ADR.ID, match.ADR.Title), key))
+		}
+	}
+	options = append(options, huh.NewOption("Cancel", relatedCancel))
+
+	var selected string
+	err = huh.NewSelect[string]().
+		Title("Amend or supersede an existing ADR instead?").
+		Options(options...).
+		Value(&selected).
+		Run()
+	if err != nil {
+		return relatedChoice{}, fmt.Errorf("failed to get choice: %w", err)
+	}
+
+	switch selected {
+	case relatedCreate, relatedCancel:
+		return relatedChoice{Action: selected}, nil
+	}
+
+	target := targets[selected]
+	if selected == fmt.Sprintf("%s:%d", relatedSupersede, target.ID) {
+		return relatedChoice{Action: relatedSupersede, Target: target}, nil
+	}
+	return relatedChoice{Action: relatedAmend, Target: target}, nil
+}
+
+// supersedeADR marks an existing ADR as superseded by a newly created one
+func supersedeADR(adrManager *adr.Manager, old, replacement *adr.ADR) error {
+	if err := adrManager.UpdateADRStatus(old.ID, adr.StatusSuperseded); err != nil {
+		return fmt.Errorf("failed to supersede ADR-%04d: %w", old.ID, err)
+	}
+	fmt.Printf("⏭️  ADR-%04d marked as superseded by ADR-%04d\n", old.ID, replacement.ID)
+	return nil
+}
@@ -91,6 +91,13 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	} else {
 		fmt.Println("❌ Disabled")
 	}
+
+	fmt.Printf("Commit-msg: ")
+	if cfg.Hooks.CommitMsg {
+		fmt.Println("✅ Enabled (suggests and validates ADR trailers)")
+	} else {
+		fmt.Println("❌ Disabled")
+	}
 	fmt.Println()
 
 	// ADR status overview
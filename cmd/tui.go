@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/config"
+	"github.com/SilverFlin/DrDuck/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse and manage ADRs in a full-screen terminal UI",
+	Long: `Open a full-screen browser with the ADR list next to a rendered preview of
+the selected ADR.
+
+Keys:
+  ↑/↓ or j/k     Move through the list (pgup/pgdown scroll the preview)
+  /              Filter by title or ID
+  tab            Cycle the status filter
+  t              Cycle the tag filter
+  e or enter     Edit the ADR in $EDITOR
+  s              Change status, following the rules of 'drduck set-status' and 'drduck accept'
+  a              Show 'drduck suggest' for the ADR
+  g              Show the ADRs it refers to and is referred to by
+  r              Reload from disk
+  q              Quit`,
+	RunE: runTUI,
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
+
+func runTUI(cmd *cobra.Command, args []string) error {
+	// Check if project is initialized
+	initialized, err := config.IsInitialized()
+	if err != nil {
+		return fmt.Errorf("failed to check initialization status: %w", err)
+	}
+
+	if !initialized {
+		return fmt.Errorf("❌ DrDuck is not initialized in this project. Run 'drduck init' first")
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate drduck executable: %w", err)
+	}
+
+	return tui.Run(tui.Options{
+		Config: cfg,
+		Editor: getEditor(),
+		// Suggestions run as a subprocess so their output can be shown in the UI
+		SuggestCommand: func(target *adr.ADR) *exec.Cmd {
+			return exec.Command(executable, "suggest", fmt.Sprintf("%04d", target.ID))
+		},
+	})
+}
@@ -11,6 +11,8 @@ import (
 var (
 	preCommitFlag bool
 	prePushFlag   bool
+	commitMsgFile string
+	refsFlag      bool
 )
 
 var validateCmd = &cobra.Command{
@@ -22,7 +24,9 @@ This command previews what the git hooks will check without making any commits o
 Examples:
   drduck validate                # General validation
   drduck validate --pre-commit   # Preview pre-commit validation
-  drduck validate --pre-push     # Preview pre-push validation`,
+  drduck validate --pre-push     # Preview pre-push validation
+  drduck validate --commit-msg .git/COMMIT_EDITMSG  # Check a commit message's ADR trailer
+  drduck validate --refs         # Check ADR references in code comments`,
 	RunE: runValidate,
 }
 
@@ -30,6 +34,8 @@ func init() {
 	rootCmd.AddCommand(validateCmd)
 	validateCmd.Flags().BoolVar(&preCommitFlag, "pre-commit", false, "Run pre-commit validation")
 	validateCmd.Flags().BoolVar(&prePushFlag, "pre-push", false, "Run pre-push validation")
+	validateCmd.Flags().BoolVar(&refsFlag, "refs", false, "Check ADR references in code comments")
+	validateCmd.Flags().StringVar(&commitMsgFile, "commit-msg", "", "Validate the ADR trailer of the commit message in this file")
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
@@ -54,10 +60,15 @@ func runValidate(cmd *cobra.Command, args []string) error {
 
 	// Determine which validation to run
 	switch {
+	case commitMsgFile != "":
+		return runCommitMsgValidation(validator, commitMsgFile)
 	case preCommitFlag:
 		return runPreCommitValidation(validator)
 	case prePushFlag:
 		return runPrePushValidation(validator)
+	case refsFlag:
+		fmt.Println(validator.ValidateRefs().Message)
+		return nil
 	default:
 		return runGeneralValidation(validator)
 	}
@@ -74,6 +85,19 @@ func runPreCommitValidation(validator *hooks.Validator) error {
 	return nil
 }
 
+func runCommitMsgValidation(validator *hooks.Validator, messageFile string) error {
+	result := validator.ValidateCommitMsg(messageFile)
+	if result.Message != "" {
+		fmt.Println(result.Message)
+	}
+
+	if result.ShouldBlock {
+		return fmt.Errorf("invalid ADR trailer")
+	}
+
+	return nil
+}
+
 func runPrePushValidation(validator *hooks.Validator) error {
 	fmt.Println("🦆 DrDuck: Running pre-push validation preview...")
 	fmt.Println()
@@ -104,6 +128,11 @@ func runGeneralValidation(validator *hooks.Validator) error {
 	prePushResult := validator.ValidatePrePush()
 	fmt.Println(prePushResult.Message)
 
+	fmt.Println()
+	fmt.Println("## ADR References (Warning Only)")
+	refsResult := validator.ValidateRefs()
+	fmt.Println(refsResult.Message)
+
 	fmt.Println()
 	fmt.Println("## Summary")
 	if len(preCommitResult.DraftADRs) > 0 {
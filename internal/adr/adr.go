@@ -1,6 +1,7 @@
 package adr
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/SilverFlin/DrDuck/internal/config"
 	"gopkg.in/yaml.v3"
@@ -33,6 +35,8 @@ type ADR struct {
 	Rationale   string    `yaml:"rationale"`
 	Consequences string   `yaml:"consequences"`
 	Alternatives string   `yaml:"alternatives,omitempty"`
+	Governs     []string  `yaml:"governs,omitempty"` // Glob patterns of the paths the decision governs
+	Tags        []string  `yaml:"tags,omitempty"`
 	FilePath    string    `yaml:"-"`
 }
 
@@ -66,8 +70,39 @@ func (m *Manager) GetNextID() (int, error) {
 	return maxID + 1, nil
 }
 
+// ErrInvalidTitle is returned for a title that cannot name an ADR file
+var ErrInvalidTitle = errors.New("invalid ADR title")
+
+// FileName returns the file name of an ADR: its zero-padded ID followed by the
+// title in lowercase, with every run of other characters than letters and
+// digits replaced by a dash. The title can therefore never leave the ADR directory.
+func FileName(id int, title string) (string, error) {
+	var slug strings.Builder
+	separate := false
+	for _, r := range strings.ToLower(title) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			separate = true
+			continue
+		}
+		if separate && slug.Len() > 0 {
+			slug.WriteByte('-')
+		}
+		slug.WriteRune(r)
+		separate = false
+	}
+	if slug.Len() == 0 {
+		return "", fmt.Errorf("%w: %q has no letters or digits", ErrInvalidTitle, title)
+	}
+	return fmt.Sprintf("%04d-%s.md", id, slug.String()), nil
+}
+
 // Create creates a new ADR with the given name and template
 func (m *Manager) Create(name string) (*ADR, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: a title is required", ErrInvalidTitle)
+	}
+
 	id, err := m.GetNextID()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get next ID: %w", err)
@@ -82,16 +117,11 @@ func (m *Manager) Create(name string) (*ADR, error) {
 	}
 
 	// Generate file path
-	filename := fmt.Sprintf("%04d-%s.md", id, strings.ReplaceAll(strings.ToLower(name), " ", "-"))
-	
-	var adrPath string
-	if m.config.DocStorage == "same-repo" {
-		adrPath = filepath.Join(m.config.DocPath, filename)
-	} else {
-		// For separate repo, we'll create in a temporary location for now
-		// TODO: Implement separate repo handling
-		adrPath = filepath.Join("temp_adrs", filename)
+	filename, err := FileName(id, name)
+	if err != nil {
+		return nil, err
 	}
+	adrPath := filepath.Join(m.Dir(), filename)
 
 	adr.FilePath = adrPath
 
@@ -115,15 +145,19 @@ func (m *Manager) Create(name string) (*ADR, error) {
 	return adr, nil
 }
 
-// List returns all ADRs in the project
-func (m *Manager) List() ([]*ADR, error) {
-	var adrDir string
+// Dir returns the directory holding the project's ADR files
+func (m *Manager) Dir() string {
 	if m.config.DocStorage == "same-repo" {
-		adrDir = m.config.DocPath
-	} else {
-		// TODO: Implement separate repo handling
-		adrDir = "temp_adrs"
+		return m.config.DocPath
 	}
+	// For separate repo, ADRs live in a temporary location for now
+	// TODO: Implement separate repo handling
+	return "temp_adrs"
+}
+
+// List returns all ADRs in the project
+func (m *Manager) List() ([]*ADR, error) {
+	adrDir := m.Dir()
 
 	if _, err := os.Stat(adrDir); os.IsNotExist(err) {
 		return []*ADR{}, nil // No ADRs yet
@@ -170,10 +204,12 @@ func (m *Manager) List() ([]*ADR, error) {
 
 // FrontMatter represents the YAML front matter in ADR files
 type FrontMatter struct {
-	ID     int    `yaml:"id"`
-	Title  string `yaml:"title"`
-	Status string `yaml:"status"`
-	Date   string `yaml:"date"`
+	ID      int      `yaml:"id"`
+	Title   string   `yaml:"title"`
+	Status  string   `yaml:"status"`
+	Date    string   `yaml:"date"`
+	Governs []string `yaml:"governs,omitempty"`
+	Tags    []string `yaml:"tags,omitempty"`
 }
 
 // parseADRFile parses an ADR file and extracts metadata
@@ -211,6 +247,9 @@ func (m *Manager) parseADRFile(filePath string, id int) (*ADR, error) {
 	adr.ID = frontMatter.ID
 	adr.Title = frontMatter.Title
 	adr.Status = Status(frontMatter.Status)
+	adr.Governs = frontMatter.Governs
+	adr.Tags = frontMatter.Tags
+	adr.Decision = markdownSection(contentStr[endIndex+8:], "Decision")
 	if parsedDate, err := time.Parse("2006-01-02", frontMatter.Date); err == nil {
 		adr.Date = parsedDate
 	}
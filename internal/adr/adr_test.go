@@ -0,0 +1,57 @@
+package adr
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SilverFlin/DrDuck/internal/config"
+)
+
+func TestFileName(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{title: "Use Redis for caching", want: "0007-use-redis-for-caching.md"},
+		{title: "  Use gRPC / HTTP2  ", want: "0007-use-grpc-http2.md"},
+		{title: "../../etc/passwd", want: "0007-etc-passwd.md"},
+		{title: `..\windows\system32`, want: "0007-windows-system32.md"},
+		{title: "Café au lait", want: "0007-café-au-lait.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			got, err := FileName(7, tt.title)
+			if err != nil {
+				t.Fatalf("FileName(%q): %v", tt.title, err)
+			}
+			if got != tt.want {
+				t.Errorf("FileName(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateKeepsFilesInADRDirectory(t *testing.T) {
+	t.Chdir(t.TempDir())
+	manager := NewManager(config.DefaultConfig())
+
+	created, err := manager.Create("../../outside")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if dir := filepath.Dir(created.FilePath); dir != manager.Dir() {
+		t.Errorf("created %s outside %s", created.FilePath, manager.Dir())
+	}
+	if _, err := os.Stat(created.FilePath); err != nil {
+		t.Errorf("ADR file not written: %v", err)
+	}
+
+	for _, title := range []string{"", "   ", "../.."} {
+		if _, err := manager.Create(title); !errors.Is(err, ErrInvalidTitle) {
+			t.Errorf("Create(%q) error = %v, want ErrInvalidTitle", title, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package adr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ContentIssue is a reason an ADR is not ready to be accepted
+type ContentIssue struct {
+	Message string
+	Lines   []int // Zero-based lines the issue is about; empty when it concerns the whole file
+}
+
+// placeholders are the template comments left in unwritten sections
+var placeholders = []string{
+	"<!-- what is the issue",
+	"<!-- what is the change",
+	"<!-- why are we making",
+	"<!-- what becomes easier",
+	"<!-- what becomes more difficult",
+	"<!-- what other options",
+	"<!-- what problem are we trying",
+	"<!-- what is our solution",
+	"<!-- why did we choose",
+	"<!-- what are the consequences",
+}
+
+// requiredSections are the sections that need content, with the headings that
+// satisfy them
+var requiredSections = []struct {
+	name    string
+	headers []string
+}{
+	{"Context", []string{"## context", "## problem"}},
+	{"Decision", []string{"## decision", "## solution"}},
+	{"Rationale", []string{"## rationale", "## why"}},
+}
+
+// CheckContent returns what keeps an ADR's content from being accepted: more
+// than two unfilled template placeholders, and key sections that are missing
+// or too short
+func CheckContent(content string) []ContentIssue {
+	lower := strings.ToLower(content)
+	// Lowercasing keeps the newlines, so offsets in lower map to the same lines
+	lineOf := func(offset int) int {
+		return strings.Count(lower[:offset], "\n")
+	}
+
+	var issues []ContentIssue
+
+	var placeholderLines []int
+	for _, placeholder := range placeholders {
+		if idx := strings.Index(lower, placeholder); idx != -1 {
+			placeholderLines = append(placeholderLines, lineOf(idx))
+		}
+	}
+	if len(placeholderLines) > 2 {
+		issues = append(issues, ContentIssue{
+			Message: fmt.Sprintf("Found %d unfilled placeholder sections", len(placeholderLines)),
+			Lines:   placeholderLines,
+		})
+	}
+
+	for _, section := range requiredSections {
+		hasContent := false
+		var headerLines []int
+		for _, header := range section.headers {
+			idx := strings.Index(lower, header)
+			if idx == -1 {
+				continue
+			}
+			headerLines = append(headerLines, lineOf(idx))
+
+			// Look for content after the header
+			afterHeader := lower[idx+len(header):]
+			if nextHeader := strings.Index(afterHeader, "##"); nextHeader != -1 {
+				afterHeader = afterHeader[:nextHeader]
+			}
+
+			// Remove common non-content
+			sectionContent := strings.ReplaceAll(afterHeader, "<!--", "")
+			sectionContent = strings.ReplaceAll(sectionContent, "-->", "")
+			sectionContent = strings.TrimSpace(sectionContent)
+
+			if len(sectionContent) > 20 { // Minimum meaningful content
+				hasContent = true
+				break
+			}
+		}
+
+		if !hasContent {
+			issues = append(issues, ContentIssue{
+				Message: fmt.Sprintf("%s section needs more content", section.name),
+				Lines:   headerLines,
+			})
+		}
+	}
+
+	return issues
+}
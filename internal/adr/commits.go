@@ -0,0 +1,138 @@
+package adr
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TrailerKey is the git trailer linking a commit to the ADRs it implements,
+// e.g. "ADR: 0007"
+const TrailerKey = "ADR"
+
+// Commit is a commit carrying an ADR trailer
+type Commit struct {
+	Hash    string
+	Author  string
+	Date    time.Time
+	Subject string
+	ADRIDs  []int
+}
+
+// scissorsLine marks where "git commit -v" appends the diff; git drops it and
+// everything below it from the message
+const scissorsLine = "------------------------ >8 ------------------------"
+
+var (
+	trailerLinePattern = regexp.MustCompile(`^([A-Za-z0-9-]+):\s*(.*)$`)
+	adrIDPattern       = regexp.MustCompile(`\d+`)
+)
+
+// FormatTrailer returns the trailer line linking a commit to an ADR
+func FormatTrailer(id int) string {
+	return fmt.Sprintf("%s: %04d", TrailerKey, id)
+}
+
+// ParseTrailerIDs returns the ADR IDs in the trailer block of a commit message.
+// Values may list several IDs and use any prefix, e.g. "0007, ADR-12".
+func ParseTrailerIDs(message string) []int {
+	var ids []int
+	for _, line := range trailerBlock(message) {
+		match := trailerLinePattern.FindStringSubmatch(line)
+		if match == nil || !strings.EqualFold(match[1], TrailerKey) {
+			continue
+		}
+		ids = append(ids, parseIDs(match[2])...)
+	}
+	return ids
+}
+
+// CommitsForADR lists the commits on any branch or tag whose ADR trailer
+// references the ADR, newest first
+func CommitsForADR(id int) ([]Commit, error) {
+	format := "--format=%H%x1f%an%x1f%aI%x1f%s%x1f%(trailers:key=" + TrailerKey + ",valueonly,separator=%x2C)%x1e"
+	output, err := exec.Command("git", "log", "--branches", "--remotes", "--tags", format).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git log: %w", err)
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(string(output), "\x1e") {
+		fields := strings.Split(strings.TrimSpace(record), "\x1f")
+		if len(fields) != 5 || fields[4] == "" {
+			continue
+		}
+
+		ids := parseIDs(fields[4])
+		if !containsID(ids, id) {
+			continue
+		}
+
+		date, _ := time.Parse(time.RFC3339, fields[2])
+		commits = append(commits, Commit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Date:    date,
+			Subject: fields[3],
+			ADRIDs:  ids,
+		})
+	}
+	return commits, nil
+}
+
+// trailerBlock returns the lines of the last paragraph of a commit message,
+// ignoring comment lines and the scissors section that git strips
+func trailerBlock(message string) []string {
+	var paragraphs [][]string
+	var current []string
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(line, "#") {
+			if strings.TrimSpace(line[1:]) == scissorsLine {
+				break
+			}
+			continue
+		}
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			if len(current) > 0 {
+				paragraphs = append(paragraphs, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		paragraphs = append(paragraphs, current)
+	}
+
+	// The subject line alone is never a trailer block
+	if len(paragraphs) < 2 {
+		return nil
+	}
+	return paragraphs[len(paragraphs)-1]
+}
+
+// parseIDs extracts the ADR IDs from a trailer value
+func parseIDs(value string) []int {
+	var ids []int
+	for _, number := range adrIDPattern.FindAllString(value, -1) {
+		if id, err := strconv.Atoi(number); err == nil && !containsID(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// containsID reports whether id is in ids
+func containsID(ids []int, id int) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
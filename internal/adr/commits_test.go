@@ -0,0 +1,64 @@
+package adr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTrailerIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []int
+	}{
+		{
+			name:    "single trailer",
+			message: "Add cache\n\nADR: 0007\n",
+			want:    []int{7},
+		},
+		{
+			name:    "several IDs and other trailers",
+			message: "Add cache\n\nBody text.\n\nSigned-off-by: Dev <dev@example.com>\nadr: 0007, ADR-12\n",
+			want:    []int{7, 12},
+		},
+		{
+			name:    "trailer outside the last paragraph",
+			message: "Add cache\n\nADR: 0007\n\nMore body text.\n",
+			want:    nil,
+		},
+		{
+			name:    "subject only",
+			message: "ADR: 0007\n",
+			want:    nil,
+		},
+		{
+			name:    "comment lines",
+			message: "Add cache\n\nADR: 0007\n# Please enter the commit message for your changes.\n#\n# On branch main\n",
+			want:    []int{7},
+		},
+		{
+			name: "verbose commit with diff below the scissors",
+			message: "Add cache\n\nADR: 0007\n" +
+				"# Please enter the commit message for your changes.\n" +
+				"# ------------------------ >8 ------------------------\n" +
+				"# Do not modify or remove the line above.\n" +
+				"diff --git a/cache.go b/cache.go\n" +
+				"index 0000000..1111111 100644\n" +
+				"--- a/cache.go\n" +
+				"+++ b/cache.go\n" +
+				"@@ -1 +1,2 @@\n" +
+				" package cache\n" +
+				"+\n" +
+				"+var store map[string]string\n",
+			want: []int{7},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseTrailerIDs(tt.message); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTrailerIDs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package adr
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+var (
+	// mentionPattern finds ADR mentions such as ADR-0007
+	mentionPattern = regexp.MustCompile(`(?i)\bADR-(\d+)\b`)
+	// fileLinkPattern finds links to ADR files such as (0007-use-redis.md)
+	fileLinkPattern = regexp.MustCompile(`\(\s*(?:\./)?(\d+)-[^)\s]*\.md\s*\)`)
+)
+
+// ReferencedIDs returns the IDs of the ADRs an ADR's content mentions or links
+// to, in ascending order and without the ADR's own ID
+func ReferencedIDs(content string, self int) []int {
+	seen := map[int]bool{self: true}
+	var ids []int
+	for _, pattern := range []*regexp.Regexp{mentionPattern, fileLinkPattern} {
+		for _, match := range pattern.FindAllStringSubmatch(content, -1) {
+			id, err := strconv.Atoi(match[1])
+			if err != nil || seen[id] {
+				continue
+			}
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
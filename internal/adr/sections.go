@@ -0,0 +1,144 @@
+package adr
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Section is a "## " section of an ADR body
+type Section struct {
+	Title   string `json:"title"`   // Heading text; empty for the text before the first section
+	Content string `json:"content"` // Text up to the next "## " heading, without HTML comments
+}
+
+var htmlCommentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+// SplitFrontMatter separates the YAML front matter from the markdown body.
+// Content without front matter is returned as the body.
+func SplitFrontMatter(content string) (frontMatter, body string) {
+	if !strings.HasPrefix(content, "---\n") {
+		return "", content
+	}
+	endIndex := strings.Index(content[4:], "\n---\n")
+	if endIndex == -1 {
+		return "", content
+	}
+	return content[4 : endIndex+4], content[endIndex+9:]
+}
+
+// ParseSections splits an ADR body into its "## " sections. Template
+// placeholder comments are removed.
+func ParseSections(body string) []Section {
+	body = htmlCommentPattern.ReplaceAllString(body, "")
+
+	var sections []Section
+	current := Section{}
+	var lines []string
+	flush := func() {
+		current.Content = strings.TrimSpace(strings.Join(lines, "\n"))
+		if current.Title != "" || current.Content != "" {
+			sections = append(sections, current)
+		}
+		lines = nil
+	}
+
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			flush()
+			current = Section{Title: strings.TrimSpace(line[3:])}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	flush()
+	return sections
+}
+
+// markdownSection returns the content of the section with the given title,
+// compared case-insensitively
+func markdownSection(body, title string) string {
+	for _, section := range ParseSections(body) {
+		if strings.EqualFold(section.Title, title) {
+			return section.Content
+		}
+	}
+	return ""
+}
+
+// FillSections replaces the content of the ADR's "## " sections with the given
+// markdown, keyed by section title compared case-insensitively. Sections the
+// template does not have are added before the closing footer, in title order.
+func (m *Manager) FillSections(target *ADR, sections map[string]string) error {
+	content, err := os.ReadFile(target.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read ADR file: %w", err)
+	}
+
+	frontMatter, body := SplitFrontMatter(string(content))
+
+	titles := make([]string, 0, len(sections))
+	for title := range sections {
+		titles = append(titles, title)
+	}
+	sort.Strings(titles)
+
+	filled := make(map[string]bool, len(sections))
+	contentFor := func(heading string) (string, bool) {
+		for _, title := range titles {
+			if strings.EqualFold(strings.TrimSpace(title), heading) {
+				filled[title] = true
+				return strings.TrimSpace(sections[title]), true
+			}
+		}
+		return "", false
+	}
+
+	lines := strings.Split(body, "\n")
+	var out []string
+	footer := -1 // Index in out of the "---" rule before the template footer
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		out = append(out, line)
+		if line == "---" {
+			footer = len(out) - 1
+		}
+		if !strings.HasPrefix(line, "## ") {
+			continue
+		}
+		footer = -1 // A rule followed by a section is not the footer
+
+		text, ok := contentFor(strings.TrimSpace(line[3:]))
+		if !ok {
+			continue
+		}
+		// Skip the old content up to the next section or the footer
+		for i+1 < len(lines) && !strings.HasPrefix(lines[i+1], "## ") && lines[i+1] != "---" {
+			i++
+		}
+		out = append(out, "", text, "")
+	}
+
+	var missing []string
+	for _, title := range titles {
+		if !filled[title] {
+			missing = append(missing, "## "+strings.TrimSpace(title), "", strings.TrimSpace(sections[title]), "")
+		}
+	}
+	if footer == -1 {
+		out = append(out, missing...)
+	} else {
+		out = append(out[:footer], append(missing, out[footer:]...)...)
+	}
+
+	updated := strings.Join(out, "\n")
+	if frontMatter != "" {
+		updated = "---\n" + frontMatter + "\n---\n" + updated
+	}
+	if err := os.WriteFile(target.FilePath, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("failed to write ADR file: %w", err)
+	}
+	return nil
+}
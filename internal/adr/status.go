@@ -0,0 +1,114 @@
+package adr
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrInvalidTransition is returned for a status change the transition rules forbid
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// validTransitions lists the statuses each status may move to
+var validTransitions = map[Status][]Status{
+	StatusDraft: {
+		StatusInProgress,
+		StatusAccepted,
+		StatusRejected,
+	},
+	StatusInProgress: {
+		StatusDraft, // Back to draft for more work
+		StatusAccepted,
+		StatusRejected,
+	},
+	StatusAccepted: {
+		StatusSuperseded, // Can only be superseded once accepted
+	},
+	StatusRejected: {
+		StatusDraft, // Can reconsider
+		StatusInProgress,
+	},
+	StatusSuperseded: {
+		// Superseded ADRs generally shouldn't change status
+	},
+}
+
+// ValidateTransition checks if an ADR may move from one status to another
+func ValidateTransition(currentStatus, newStatus Status) error {
+	allowedTransitions, exists := validTransitions[currentStatus]
+	if !exists {
+		return fmt.Errorf("%w: unknown current status: %s", ErrInvalidTransition, currentStatus)
+	}
+
+	for _, allowedStatus := range allowedTransitions {
+		if newStatus == allowedStatus {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: cannot transition from %s to %s", ErrInvalidTransition, currentStatus, newStatus)
+}
+
+// ParseStatus parses a status as typed on the command line, e.g. "in-progress"
+func ParseStatus(statusStr string) (Status, error) {
+	statusStr = strings.ToLower(strings.TrimSpace(statusStr))
+
+	switch statusStr {
+	case "draft":
+		return StatusDraft, nil
+	case "in-progress", "inprogress", "progress":
+		return StatusInProgress, nil
+	case "accepted", "accept":
+		return StatusAccepted, nil
+	case "rejected", "reject":
+		return StatusRejected, nil
+	case "superseded", "supersede":
+		return StatusSuperseded, nil
+	default:
+		return "", fmt.Errorf("unknown status '%s'. Valid statuses: draft, in-progress, accepted, rejected, superseded", statusStr)
+	}
+}
+
+// AcceptanceIssues lists what keeps an ADR from being accepted, if anything
+func AcceptanceIssues(target *ADR) ([]string, error) {
+	content, err := os.ReadFile(target.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ADR file: %w", err)
+	}
+
+	var issues []string
+	for _, issue := range CheckContent(string(content)) {
+		issues = append(issues, issue.Message)
+	}
+	return issues, nil
+}
+
+// ChangeStatus moves an ADR to a new status following the transition rules
+// and, unless forced, the content checks for acceptance. When the content
+// checks fail, the ADR is left unchanged and the issues are returned; forcing
+// accepts it anyway. Changing to the current status does nothing.
+func (m *Manager) ChangeStatus(target *ADR, newStatus Status, force bool) ([]string, error) {
+	if target.Status == newStatus {
+		return nil, nil
+	}
+
+	if err := ValidateTransition(target.Status, newStatus); err != nil {
+		return nil, err
+	}
+
+	if newStatus == StatusAccepted && !force {
+		issues, err := AcceptanceIssues(target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate ADR: %w", err)
+		}
+		if len(issues) > 0 {
+			return issues, nil
+		}
+	}
+
+	if err := m.UpdateADRStatus(target.ID, newStatus); err != nil {
+		return nil, fmt.Errorf("failed to update status: %w", err)
+	}
+	target.Status = newStatus
+	return nil, nil
+}
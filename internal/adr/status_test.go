@@ -0,0 +1,48 @@
+package adr
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/SilverFlin/DrDuck/internal/config"
+)
+
+func TestChangeStatus(t *testing.T) {
+	t.Chdir(t.TempDir())
+	manager := NewManager(config.DefaultConfig())
+
+	draft, err := manager.Create("Use Redis for caching")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// A fresh draft still has its template placeholders
+	issues, err := manager.ChangeStatus(draft, StatusAccepted, false)
+	if err != nil {
+		t.Fatalf("ChangeStatus: %v", err)
+	}
+	if len(issues) == 0 || draft.Status != StatusDraft {
+		t.Fatalf("accepted an unfinished draft: issues %v, status %s", issues, draft.Status)
+	}
+
+	if issues, err := manager.ChangeStatus(draft, StatusAccepted, true); err != nil || issues != nil {
+		t.Fatalf("forced ChangeStatus = %v, %v", issues, err)
+	}
+	stored, err := manager.GetADRByID(draft.ID)
+	if err != nil {
+		t.Fatalf("GetADRByID: %v", err)
+	}
+	if draft.Status != StatusAccepted || stored.Status != StatusAccepted {
+		t.Errorf("status is %s, stored %s, want Accepted", draft.Status, stored.Status)
+	}
+
+	if _, err := manager.ChangeStatus(draft, StatusDraft, true); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Accepted to Draft error = %v, want ErrInvalidTransition", err)
+	}
+
+	os.Remove(draft.FilePath)
+	if _, err := manager.ChangeStatus(&ADR{ID: draft.ID, FilePath: draft.FilePath, Status: StatusDraft}, StatusAccepted, false); err == nil {
+		t.Error("expected an error for a missing ADR file")
+	}
+}
@@ -1,9 +1,17 @@
 package ai
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/SilverFlin/DrDuck/internal/cache"
 	"github.com/SilverFlin/DrDuck/internal/config"
+	"github.com/SilverFlin/DrDuck/internal/prompts/templates"
+	"github.com/SilverFlin/DrDuck/internal/rules"
 	"github.com/SilverFlin/DrDuck/pkg/claude"
 	"github.com/SilverFlin/DrDuck/pkg/cursor"
 )
@@ -43,12 +51,14 @@ func NewManager(cfg *config.Config) *Manager {
 
 	switch cfg.AIProvider {
 	case "claude-code":
-		provider = &ClaudeProvider{integration: claude.NewIntegration()}
+		provider = newClaudeProvider(cfg)
 	case "cursor":
-		provider = &CursorProvider{integration: cursor.NewIntegration()}
+		provider = &CursorProvider{integration: cursor.NewIntegration(), fallback: newRulesProvider(cfg)}
+	case "rules":
+		provider = newRulesProvider(cfg)
 	default:
 		// Default to Claude
-		provider = &ClaudeProvider{integration: claude.NewIntegration()}
+		provider = newClaudeProvider(cfg)
 	}
 
 	return &Manager{
@@ -67,6 +77,36 @@ func (m *Manager) GetProviderName() string {
 	return m.config.AIProvider
 }
 
+// AnalysisConfig identifies the provider, model, persona and prompt version
+// that analyses are produced with, for cache fingerprinting
+func (m *Manager) AnalysisConfig() cache.AnalysisConfig {
+	promptHash := templates.PromptVersion()
+	if _, isRules := m.provider.(*RulesProvider); isRules {
+		// The rules provider ignores the prompt text; its answers depend on the rules
+		rulesData, _ := json.Marshal(m.config.Rules)
+		hash := sha256.Sum256(rulesData)
+		promptHash = hex.EncodeToString(hash[:])
+	}
+
+	return cache.AnalysisConfig{
+		Provider:   m.config.AIProvider,
+		Model:      m.config.AISettings.Model,
+		Persona:    m.config.AISettings.Persona,
+		PromptHash: promptHash,
+	}
+}
+
+// CanGenerate reports whether the provider can write free-form content such as
+// ADR titles and bodies (the rules engine can only classify changes, and Cursor
+// cannot be prompted from the command line, so it answers through the rules engine)
+func (m *Manager) CanGenerate() bool {
+	switch m.provider.(type) {
+	case *RulesProvider, *CursorProvider:
+		return false
+	}
+	return m.provider.IsAvailable()
+}
+
 // GetChangedFiles returns files modified in the current AI session
 func (m *Manager) GetChangedFiles() ([]string, error) {
 	return m.provider.GetChangedFiles()
@@ -95,6 +135,13 @@ func (m *Manager) AnalyzeChangesWithTokens(prompt string) (AnalyzeResult, error)
 // ClaudeProvider implements Provider for Claude Code CLI
 type ClaudeProvider struct {
 	integration *claude.Integration
+	fallback    *RulesProvider // Answers change analyses when the CLI fails
+}
+
+func newClaudeProvider(cfg *config.Config) *ClaudeProvider {
+	integration := claude.NewIntegration()
+	integration.Model = cfg.AISettings.Model
+	return &ClaudeProvider{integration: integration, fallback: newRulesProvider(cfg)}
 }
 
 func (p *ClaudeProvider) IsAvailable() bool {
@@ -119,13 +166,17 @@ func (p *ClaudeProvider) ExtractContext() (string, error) {
 }
 
 func (p *ClaudeProvider) AnalyzeChanges(prompt string) (string, error) {
-	return p.integration.AnalyzeChanges(prompt)
+	response, err := p.integration.AnalyzeChanges(prompt)
+	if err != nil {
+		return p.fallback.analyzeInstead(prompt, err)
+	}
+	return response, nil
 }
 
 func (p *ClaudeProvider) AnalyzeChangesWithTokens(prompt string) (AnalyzeResult, error) {
 	response, tokenUsage, err := p.integration.AnalyzeChangesWithTokens(prompt)
 	if err != nil {
-		return AnalyzeResult{}, err
+		return p.fallback.analyzeWithTokensInstead(prompt, err)
 	}
 	
 	// Convert claude.TokenUsage to ai.TokenUsage
@@ -147,6 +198,7 @@ func (p *ClaudeProvider) AnalyzeChangesWithTokens(prompt string) (AnalyzeResult,
 // CursorProvider implements Provider for Cursor
 type CursorProvider struct {
 	integration *cursor.Integration
+	fallback    *RulesProvider // Answers change analyses, which Cursor cannot run from the command line
 }
 
 func (p *CursorProvider) IsAvailable() bool {
@@ -171,13 +223,17 @@ func (p *CursorProvider) ExtractContext() (string, error) {
 }
 
 func (p *CursorProvider) AnalyzeChanges(prompt string) (string, error) {
-	return p.integration.AnalyzeChanges(prompt)
+	response, err := p.integration.AnalyzeChanges(prompt)
+	if err != nil {
+		return p.fallback.analyzeInstead(prompt, err)
+	}
+	return response, nil
 }
 
 func (p *CursorProvider) AnalyzeChangesWithTokens(prompt string) (AnalyzeResult, error) {
 	response, tokenUsage, err := p.integration.AnalyzeChangesWithTokens(prompt)
 	if err != nil {
-		return AnalyzeResult{}, err
+		return p.fallback.analyzeWithTokensInstead(prompt, err)
 	}
 	
 	// Convert cursor.TokenUsage to ai.TokenUsage
@@ -194,4 +250,110 @@ func (p *CursorProvider) AnalyzeChangesWithTokens(prompt string) (AnalyzeResult,
 		Response:   response,
 		TokenUsage: aiTokenUsage,
 	}, nil
-}
\ No newline at end of file
+}
+
+// RulesProvider implements Provider with the deterministic rules engine, for
+// teams that cannot (or prefer not to) send code to an AI service
+type RulesProvider struct {
+	engine *rules.Engine
+	err    error
+}
+
+func newRulesProvider(cfg *config.Config) *RulesProvider {
+	repoRoot, err := os.Getwd()
+	if err != nil {
+		repoRoot = "."
+	}
+	engine, err := rules.NewEngine(cfg.Rules, repoRoot)
+	return &RulesProvider{engine: engine, err: err}
+}
+
+func (p *RulesProvider) IsAvailable() bool {
+	return p.err == nil
+}
+
+func (p *RulesProvider) GetChangedFiles() ([]string, error) {
+	return nil, fmt.Errorf("not supported: the rules engine does not track AI sessions")
+}
+
+func (p *RulesProvider) SuggestADRContent(adrName string) (map[string]string, error) {
+	return nil, fmt.Errorf("not supported: the rules engine cannot generate ADR content")
+}
+
+func (p *RulesProvider) ExtractContext() (string, error) {
+	return "", fmt.Errorf("not supported: the rules engine does not track AI sessions")
+}
+
+func (p *RulesProvider) AnalyzeChanges(prompt string) (string, error) {
+	if p.err != nil {
+		return "", fmt.Errorf("invalid rules configuration: %w", p.err)
+	}
+
+	changes, ok := extractChangesFromPrompt(prompt)
+	if !ok {
+		return "", fmt.Errorf("not supported: the rules engine only answers change analysis prompts")
+	}
+
+	result, err := p.engine.EvaluateDiff(changes)
+	if err != nil {
+		return "", err
+	}
+
+	return result.Report(), nil
+}
+
+func (p *RulesProvider) AnalyzeChangesWithTokens(prompt string) (AnalyzeResult, error) {
+	response, err := p.AnalyzeChanges(prompt)
+	if err != nil {
+		return AnalyzeResult{}, err
+	}
+
+	// No tokens are consumed when no model is involved
+	return AnalyzeResult{Response: response}, nil
+}
+
+// analyzeInstead answers a change analysis prompt that an AI provider failed
+// on, so every provider gives the same verdicts without AI. Prompts the rules
+// engine cannot answer report the provider's error.
+func (p *RulesProvider) analyzeInstead(prompt string, providerErr error) (string, error) {
+	response, err := p.AnalyzeChanges(prompt)
+	if err != nil {
+		return "", providerErr
+	}
+	return response, nil
+}
+
+// analyzeWithTokensInstead is analyzeInstead for AnalyzeChangesWithTokens
+func (p *RulesProvider) analyzeWithTokensInstead(prompt string, providerErr error) (AnalyzeResult, error) {
+	result, err := p.AnalyzeChangesWithTokens(prompt)
+	if err != nil {
+		return AnalyzeResult{}, providerErr
+	}
+	return result, nil
+}
+
+// extractChangesFromPrompt pulls the diff out of a ChangeAnalysisPrompt
+func extractChangesFromPrompt(prompt string) (string, bool) {
+	lines := strings.Split(prompt, "\n")
+	inChangesSection := false
+	found := false
+	var changes []string
+
+	for _, line := range lines {
+		if strings.Contains(line, "## Code Changes to Analyze") {
+			inChangesSection = true
+			found = true
+			continue
+		}
+		if inChangesSection {
+			if strings.HasPrefix(line, "##") && !strings.Contains(line, "Code Changes") {
+				break
+			}
+			if !strings.HasPrefix(line, "```") {
+				changes = append(changes, line)
+			}
+		}
+	}
+
+	return strings.Join(changes, "\n"), found
+}
@@ -0,0 +1,150 @@
+package apidiff
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ChangeKind classifies how an exported identifier changed
+type ChangeKind string
+
+const (
+	KindAdded   ChangeKind = "added"
+	KindRemoved ChangeKind = "removed"
+	KindChanged ChangeKind = "changed"
+)
+
+// Change describes a single exported identifier that differs between two refs
+type Change struct {
+	Package string // Package directory, relative to the repository root
+	Name    string // Identifier, e.g. "Manager.Load" for a method
+	Kind    ChangeKind
+	Old     string // Signature at the base ref
+	New     string // Signature at the head ref
+}
+
+// Breaking reports whether the change can break code that imports the package
+func (c Change) Breaking() bool {
+	return c.Kind == KindRemoved || c.Kind == KindChanged
+}
+
+// String renders the change as a single human-readable line
+func (c Change) String() string {
+	switch c.Kind {
+	case KindAdded:
+		return fmt.Sprintf("%s: added %s", c.Package, c.New)
+	case KindRemoved:
+		return fmt.Sprintf("%s: removed %s", c.Package, c.Old)
+	default:
+		return fmt.Sprintf("%s: changed %s → %s", c.Package, c.Old, c.New)
+	}
+}
+
+// Compare reports the exported API changes between two refs, limited to the
+// packages that contain one of the changed files. Either ref may be WorkingTree.
+func Compare(baseRef, headRef string, changedFiles []string) ([]Change, error) {
+	dirs := packageDirs(changedFiles)
+	if len(dirs) == 0 {
+		return nil, nil
+	}
+
+	before, err := LoadSurface(baseRef, dirs)
+	if err != nil {
+		return nil, err
+	}
+	after, err := LoadSurface(headRef, dirs)
+	if err != nil {
+		return nil, err
+	}
+	return Diff(before, after), nil
+}
+
+// Diff compares two API surfaces. Fields and methods of a type that was added
+// or removed as a whole are folded into that type's change.
+func Diff(before, after Surface) []Change {
+	var changes []Change
+
+	for key, old := range before {
+		current, exists := after[key]
+		switch {
+		case !exists:
+			if !memberOf(old, before, after) {
+				changes = append(changes, Change{Package: old.Package, Name: old.Name, Kind: KindRemoved, Old: old.Signature})
+			}
+		case current.Signature != old.Signature:
+			changes = append(changes, Change{Package: old.Package, Name: old.Name, Kind: KindChanged, Old: old.Signature, New: current.Signature})
+		}
+	}
+	for key, current := range after {
+		if _, exists := before[key]; !exists && !memberOf(current, after, before) {
+			changes = append(changes, Change{Package: current.Package, Name: current.Name, Kind: KindAdded, New: current.Signature})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Package != changes[j].Package {
+			return changes[i].Package < changes[j].Package
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+// memberOf reports whether the entry is a field or method of a type that
+// exists in its own surface but not in the other one
+func memberOf(entry Entry, own, other Surface) bool {
+	idx := strings.Index(entry.Name, ".")
+	if idx == -1 {
+		return false
+	}
+	parent := entry.Package + "." + entry.Name[:idx]
+	_, inOwn := own[parent]
+	_, inOther := other[parent]
+	return inOwn && !inOther
+}
+
+// packageDirs returns the unique directories of changed API files
+func packageDirs(files []string) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, file := range files {
+		file = strings.ReplaceAll(file, "\\", "/")
+		if !IsAPIFile(file) {
+			continue
+		}
+		dir := path.Dir(file)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// BreakingChanges filters the changes down to the ones that can break importers
+func BreakingChanges(changes []Change) []Change {
+	var result []Change
+	for _, change := range changes {
+		if change.Breaking() {
+			result = append(result, change)
+		}
+	}
+	return result
+}
+
+// Summarize renders changes as a markdown bullet list, breaking changes marked
+func Summarize(changes []Change) string {
+	var builder strings.Builder
+	for _, change := range changes {
+		builder.WriteString("- ")
+		builder.WriteString(change.String())
+		if change.Breaking() {
+			builder.WriteString(" ⚠️ breaking")
+		}
+		builder.WriteString("\n")
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
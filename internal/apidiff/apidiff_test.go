@@ -0,0 +1,258 @@
+package apidiff
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const storeBase = `package store
+
+import "context"
+
+// Store keeps records
+type Store struct {
+	Path    string
+	Timeout int
+	cache   map[string]string
+}
+
+type cursor struct{}
+
+func (c *cursor) Next() bool { return false }
+
+func Open(path string) (*Store, error) { return &Store{Path: path}, nil }
+
+func Close(s *Store) error { return nil }
+
+func (s *Store) Get(ctx context.Context, key string) (string, error) { return "", nil }
+
+func helper() {}
+
+const MaxKeys = 100
+`
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name  string
+		after string
+		want  []string
+	}{
+		{
+			name:  "unchanged",
+			after: storeBase,
+		},
+		{
+			name:  "body and comment edits",
+			after: strings.Replace(strings.Replace(storeBase, "return &Store{Path: path}, nil", "return nil, nil", 1), "// Store keeps records", "// Store keeps records on disk", 1),
+		},
+		{
+			name:  "added func",
+			after: storeBase + "\nfunc Remove(s *Store, key string) error { return nil }\n",
+			want:  []string{"pkg/store: added func Remove(*Store, string) error"},
+		},
+		{
+			name:  "removed func",
+			after: strings.Replace(storeBase, "func Close(s *Store) error { return nil }\n", "", 1),
+			want:  []string{"pkg/store: removed func Close(*Store) error"},
+		},
+		{
+			name:  "changed signature",
+			after: strings.Replace(storeBase, "func Open(path string) (*Store, error)", "func Open(path string, readOnly bool) (*Store, error)", 1),
+			want:  []string{"pkg/store: changed func Open(string) (*Store, error) → func Open(string, bool) (*Store, error)"},
+		},
+		{
+			name:  "changed method",
+			after: strings.Replace(storeBase, "key string) (string, error)", "key string) ([]byte, error)", 1),
+			want:  []string{"pkg/store: changed func (Store) Get(context.Context, string) (string, error) → func (Store) Get(context.Context, string) ([]byte, error)"},
+		},
+		{
+			name:  "exported struct fields",
+			after: strings.Replace(strings.Replace(storeBase, "Timeout int", "Timeout int64", 1), "Path    string", "Path    string\n\tReadOnly bool", 1),
+			want: []string{
+				"pkg/store: added Store.ReadOnly bool",
+				"pkg/store: changed Store.Timeout int → Store.Timeout int64",
+			},
+		},
+		{
+			name:  "unexported field, type, method and func",
+			after: strings.Replace(strings.Replace(strings.Replace(storeBase, "cache   map[string]string", "cache   []string", 1), "func (c *cursor) Next() bool", "func (c *cursor) Next(n int) bool", 1), "func helper() {}", "func helper(n int) int { return n }", 1),
+		},
+		{
+			name:  "method on unexported type made exported",
+			after: strings.Replace(strings.Replace(storeBase, "type cursor struct{}", "type Cursor struct{}", 1), "func (c *cursor) Next()", "func (c *Cursor) Next()", 1),
+			want:  []string{"pkg/store: added type Cursor struct"},
+		},
+		{
+			name:  "removed type folds its fields and methods",
+			after: "package store\n\nconst MaxKeys = 100\n",
+			want: []string{
+				"pkg/store: removed func Close(*Store) error",
+				"pkg/store: removed func Open(string) (*Store, error)",
+				"pkg/store: removed type Store struct",
+			},
+		},
+		{
+			name:  "changed constant type",
+			after: strings.Replace(storeBase, "const MaxKeys = 100", "const MaxKeys int64 = 100", 1),
+			want:  []string{"pkg/store: changed const MaxKeys → const MaxKeys int64"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, after := Surface{}, Surface{}
+			if err := before.AddFile("pkg/store/store.go", []byte(storeBase)); err != nil {
+				t.Fatal(err)
+			}
+			if err := after.AddFile("pkg/store/store.go", []byte(tt.after)); err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, change := range Diff(before, after) {
+				got = append(got, change.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestAddFile(t *testing.T) {
+	surface := Surface{}
+	if err := surface.AddFile("cmd/tool/main.go", []byte("package main\n\nfunc Run() {}\n")); err != nil {
+		t.Fatal(err)
+	}
+	if len(surface) != 0 {
+		t.Errorf("package main contributed %v", surface)
+	}
+
+	if err := surface.AddFile("pkg/broken/broken.go", []byte("package broken\n\nfunc (")); err == nil {
+		t.Error("expected a parse error")
+	}
+}
+
+func TestIsAPIFile(t *testing.T) {
+	tests := map[string]bool{
+		"pkg/store/store.go":            true,
+		"main.go":                       true,
+		"pkg/store/store_test.go":       false,
+		"internal/cache/cache.go":       false,
+		"pkg/internal/util/util.go":     false,
+		"pkg/store/testdata/fixture.go": false,
+		"vendor/github.com/x/y/y.go":    false,
+		".github/tools/gen.go":          false,
+		"pkg/store/README.md":           false,
+		`pkg\store\store.go`:            true,
+	}
+	for filePath, want := range tests {
+		if got := IsAPIFile(filePath); got != want {
+			t.Errorf("IsAPIFile(%q) = %v, want %v", filePath, got, want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	changes := []Change{
+		{Package: "pkg/a", Name: "New", Kind: KindAdded, New: "func New()"},
+		{Package: "pkg/a", Name: "Old", Kind: KindRemoved, Old: "func Old()"},
+	}
+	want := "- pkg/a: added func New()\n- pkg/a: removed func Old() ⚠️ breaking"
+	if got := Summarize(changes); got != want {
+		t.Errorf("Summarize() =\n%s\nwant\n%s", got, want)
+	}
+	if breaking := BreakingChanges(changes); len(breaking) != 1 || breaking[0].Name != "Old" {
+		t.Errorf("BreakingChanges() = %v", breaking)
+	}
+}
+
+// git runs a git command in the current directory
+func git(t *testing.T, args ...string) string {
+	t.Helper()
+
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Dev")
+	t.Setenv("GIT_AUTHOR_EMAIL", "dev@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Dev")
+	t.Setenv("GIT_COMMITTER_EMAIL", "dev@example.com")
+	git(t, "init", "-q", "-b", "main")
+
+	writeFile(t, "pkg/store/store.go", storeBase)
+	writeFile(t, "pkg/store/extra.go", "package store\n\nfunc Extra() {}\n")
+	writeFile(t, "internal/db/db.go", "package db\n\nfunc Connect() {}\n")
+	git(t, "add", "-A")
+	git(t, "commit", "-q", "-m", "base")
+
+	if _, err := BaseRef(); err == nil {
+		t.Error("BaseRef succeeded without an upstream")
+	}
+
+	git(t, "checkout", "-q", "-b", "feature")
+	writeFile(t, "pkg/store/store.go", strings.Replace(storeBase, "func Open(path string)", "func Open(path string, readOnly bool)", 1))
+	os.Remove("pkg/store/extra.go")
+	writeFile(t, "internal/db/db.go", "package db\n\nfunc Connect(dsn string) {}\n")
+
+	want := []string{
+		"pkg/store: removed func Extra()",
+		"pkg/store: changed func Open(string) (*Store, error) → func Open(string, bool) (*Store, error)",
+	}
+	changedFiles := []string{"pkg/store/store.go", "pkg/store/extra.go", "internal/db/db.go"}
+
+	// Uncommitted changes are compared on disk, committed ones at their ref
+	for _, headRef := range []string{WorkingTree, "HEAD"} {
+		if headRef == "HEAD" {
+			git(t, "add", "-A")
+			git(t, "commit", "-q", "-m", "feature")
+		}
+		changes, err := Compare("main", headRef, changedFiles)
+		if err != nil {
+			t.Fatalf("Compare(%q): %v", headRef, err)
+		}
+		var got []string
+		for _, change := range changes {
+			got = append(got, change.String())
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Compare(%q) =\n%s\nwant\n%s", headRef, strings.Join(got, "\n"), strings.Join(want, "\n"))
+		}
+	}
+
+	// With a remote, the base is where the branch forked from the default branch
+	git(t, "update-ref", "refs/remotes/origin/main", "main")
+	forkPoint := git(t, "rev-parse", "main")
+	if base, err := BaseRef(); err != nil || base != forkPoint {
+		t.Errorf("BaseRef() = %q, %v, want %s", base, err, forkPoint)
+	}
+
+	// Once pushed, the base is the branch on origin
+	git(t, "update-ref", "refs/remotes/origin/feature", "HEAD")
+	if base, err := BaseRef(); err != nil || base != "origin/feature" {
+		t.Errorf("BaseRef() = %q, %v, want origin/feature", base, err)
+	}
+}
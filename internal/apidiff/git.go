@@ -0,0 +1,184 @@
+package apidiff
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// WorkingTree is the ref for the files on disk, including uncommitted changes
+const WorkingTree = ""
+
+// LoadSurface builds the public API surface of the given package directories
+// as they exist at a git ref, or on disk for WorkingTree. Files that fail to
+// parse are skipped.
+func LoadSurface(ref string, dirs []string) (Surface, error) {
+	surface := make(Surface)
+	if len(dirs) == 0 {
+		return surface, nil
+	}
+
+	paths, err := listGoFiles(ref, dirs)
+	if err != nil {
+		return nil, err
+	}
+	contents, err := readBlobs(ref, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, filePath := range paths {
+		if src, ok := contents[filePath]; ok {
+			_ = surface.AddFile(filePath, src) // Unparseable files simply contribute nothing
+		}
+	}
+	return surface, nil
+}
+
+// listGoFiles lists the API files directly inside the given directories at a ref
+func listGoFiles(ref string, dirs []string) ([]string, error) {
+	if ref == WorkingTree {
+		return listWorkingTreeFiles(dirs)
+	}
+
+	wanted := make(map[string]bool)
+	args := []string{"ls-tree", "--name-only", ref, "--"}
+	for _, dir := range dirs {
+		wanted[dir] = true
+		if dir == "." {
+			args = append(args, ".")
+		} else {
+			args = append(args, dir+"/")
+		}
+	}
+
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files at %s: %w", ref, err)
+	}
+
+	var paths []string
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && IsAPIFile(line) && wanted[path.Dir(line)] {
+			paths = append(paths, line)
+		}
+	}
+	return paths, nil
+}
+
+// listWorkingTreeFiles lists the API files directly inside the given directories on disk
+func listWorkingTreeFiles(dirs []string) ([]string, error) {
+	var paths []string
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue // The package was deleted
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files in %s: %w", dir, err)
+		}
+		for _, entry := range entries {
+			filePath := path.Join(dir, entry.Name())
+			if !entry.IsDir() && IsAPIFile(filePath) {
+				paths = append(paths, filePath)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// readBlobs reads many files at a ref with a single "git cat-file --batch" process
+func readBlobs(ref string, paths []string) (map[string][]byte, error) {
+	contents := make(map[string][]byte)
+	if len(paths) == 0 {
+		return contents, nil
+	}
+	if ref == WorkingTree {
+		for _, filePath := range paths {
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+			}
+			contents[filePath] = content
+		}
+		return contents, nil
+	}
+
+	var input bytes.Buffer
+	for _, filePath := range paths {
+		input.WriteString(ref + ":" + filePath + "\n")
+	}
+
+	cmd := exec.Command("git", "cat-file", "--batch")
+	cmd.Stdin = &input
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read files at %s: %w", ref, err)
+	}
+
+	reader := bufio.NewReader(bytes.NewReader(output))
+	for _, filePath := range paths {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("failed to read git object header: %w", err)
+		}
+		fields := strings.Fields(header)
+		if len(fields) == 2 && fields[1] == "missing" {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected git object header: %q", header)
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid git object size: %w", err)
+		}
+
+		content := make([]byte, size+1) // Content is followed by a newline
+		if _, err := io.ReadFull(reader, content); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+		}
+		contents[filePath] = content[:size]
+	}
+	return contents, nil
+}
+
+// RefExists reports whether git can resolve the ref to a commit
+func RefExists(ref string) bool {
+	return exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}").Run() == nil
+}
+
+// BaseRef returns the commit the current branch's changes are compared
+// against: the branch on origin when it was pushed, otherwise the commit it
+// forked from the remote's default branch. It fails when there is neither, as
+// on a branch that was never pushed to a repository without a remote.
+func BaseRef() (string, error) {
+	if output, err := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD").Output(); err == nil {
+		if remoteBranch := "origin/" + strings.TrimSpace(string(output)); RefExists(remoteBranch) {
+			return remoteBranch, nil
+		}
+	}
+
+	candidates := []string{"origin/main", "origin/master"}
+	if output, err := exec.Command("git", "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD").Output(); err == nil {
+		candidates = append([]string{strings.TrimSpace(string(output))}, candidates...)
+	}
+	for _, defaultBranch := range candidates {
+		if !RefExists(defaultBranch) {
+			continue
+		}
+		output, err := exec.Command("git", "merge-base", defaultBranch, "HEAD").Output()
+		if err != nil {
+			return "", fmt.Errorf("failed to find where HEAD forked from %s: %w", defaultBranch, err)
+		}
+		return strings.TrimSpace(string(output)), nil
+	}
+	return "", fmt.Errorf("no upstream to compare against: push the branch or add an origin remote with a default branch")
+}
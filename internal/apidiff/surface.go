@@ -0,0 +1,231 @@
+package apidiff
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Entry is one exported identifier and its rendered signature
+type Entry struct {
+	Package   string // Package directory, relative to the repository root
+	Name      string // Identifier, e.g. "Manager.Load" for a method
+	Kind      string // func, method, type, field, const or var
+	Signature string
+}
+
+// Surface maps "package/dir.Identifier" keys to the exported API of a set of files
+type Surface map[string]Entry
+
+// IsAPIFile reports whether a Go file can contribute to the public API surface:
+// tests, testdata, vendored code and internal packages are excluded
+func IsAPIFile(filePath string) bool {
+	filePath = strings.ReplaceAll(filePath, "\\", "/")
+	if !strings.HasSuffix(filePath, ".go") || strings.HasSuffix(filePath, "_test.go") {
+		return false
+	}
+	for _, dir := range strings.Split(path.Dir(filePath), "/") {
+		switch dir {
+		case "internal", "testdata", "vendor":
+			return false
+		}
+		if strings.HasPrefix(dir, ".") && dir != "." {
+			return false
+		}
+	}
+	return true
+}
+
+// add records an entry under its "package/dir.Identifier" key
+func (s Surface) add(pkg, name, kind, signature string) {
+	s[pkg+"."+name] = Entry{Package: pkg, Name: name, Kind: kind, Signature: signature}
+}
+
+// AddFile parses a Go source file and adds its exported declarations to the surface.
+// Files of package main are skipped since nothing can import them.
+func (s Surface) AddFile(filePath string, src []byte) error {
+	file, err := parser.ParseFile(token.NewFileSet(), filePath, src, parser.SkipObjectResolution)
+	if err != nil {
+		return err
+	}
+	if file.Name.Name == "main" {
+		return nil
+	}
+
+	pkg := path.Dir(strings.ReplaceAll(filePath, "\\", "/"))
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			s.addFunc(pkg, d)
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch sp := spec.(type) {
+				case *ast.TypeSpec:
+					s.addType(pkg, sp)
+				case *ast.ValueSpec:
+					s.addValue(pkg, d.Tok, sp)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// addFunc records an exported function or a method of an exported type
+func (s Surface) addFunc(pkg string, fn *ast.FuncDecl) {
+	if !fn.Name.IsExported() {
+		return
+	}
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		s.add(pkg, fn.Name.Name, "func", "func "+fn.Name.Name+funcSignature(fn.Type))
+		return
+	}
+
+	receiver := receiverName(fn.Recv.List[0].Type)
+	if !ast.IsExported(receiver) {
+		return
+	}
+	s.add(pkg, receiver+"."+fn.Name.Name, "method", "func ("+receiver+") "+fn.Name.Name+funcSignature(fn.Type))
+}
+
+// addType records an exported type; struct fields are tracked individually so
+// that adding a field is not reported as a change to the whole type
+func (s Surface) addType(pkg string, spec *ast.TypeSpec) {
+	if !spec.Name.IsExported() {
+		return
+	}
+	typeName := spec.Name.Name
+	name := typeName + typeParams(spec.TypeParams)
+
+	if spec.Assign.IsValid() {
+		s.add(pkg, typeName, "type", "type "+name+" = "+types.ExprString(spec.Type))
+		return
+	}
+
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		s.add(pkg, typeName, "type", "type "+name+" struct")
+		for _, field := range t.Fields.List {
+			fieldType := types.ExprString(field.Type)
+			names := field.Names
+			if len(names) == 0 {
+				// Embedded field: its name is the type name without pointer or package
+				embedded := receiverName(field.Type)
+				if idx := strings.LastIndex(embedded, "."); idx != -1 {
+					embedded = embedded[idx+1:]
+				}
+				names = []*ast.Ident{ast.NewIdent(embedded)}
+			}
+			for _, fieldName := range names {
+				if fieldName.IsExported() {
+					s.add(pkg, typeName+"."+fieldName.Name, "field", typeName+"."+fieldName.Name+" "+fieldType)
+				}
+			}
+		}
+	case *ast.InterfaceType:
+		s.add(pkg, typeName, "type", "type "+name+" "+interfaceSignature(t))
+	default:
+		s.add(pkg, typeName, "type", "type "+name+" "+types.ExprString(spec.Type))
+	}
+}
+
+// addValue records exported constants and variables with their declared type
+func (s Surface) addValue(pkg string, tok token.Token, spec *ast.ValueSpec) {
+	for _, ident := range spec.Names {
+		if !ident.IsExported() {
+			continue
+		}
+		signature := tok.String() + " " + ident.Name
+		if spec.Type != nil {
+			signature += " " + types.ExprString(spec.Type)
+		}
+		s.add(pkg, ident.Name, tok.String(), signature)
+	}
+}
+
+// funcSignature renders parameters and results by type only, so renaming a
+// parameter is not reported as an API change
+func funcSignature(ft *ast.FuncType) string {
+	signature := typeParams(ft.TypeParams) + "(" + fieldTypes(ft.Params) + ")"
+	if ft.Results == nil || len(ft.Results.List) == 0 {
+		return signature
+	}
+	results := fieldTypes(ft.Results)
+	if len(ft.Results.List) == 1 && len(ft.Results.List[0].Names) <= 1 {
+		return signature + " " + results
+	}
+	return signature + " (" + results + ")"
+}
+
+// fieldTypes renders the types of a field list, repeating shared types
+func fieldTypes(fields *ast.FieldList) string {
+	if fields == nil {
+		return ""
+	}
+	var parts []string
+	for _, field := range fields.List {
+		fieldType := types.ExprString(field.Type)
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			parts = append(parts, fieldType)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// typeParams renders a type parameter list such as "[K comparable, V any]"
+func typeParams(fields *ast.FieldList) string {
+	if fields == nil || len(fields.List) == 0 {
+		return ""
+	}
+	var parts []string
+	for _, field := range fields.List {
+		var names []string
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+		parts = append(parts, strings.Join(names, ", ")+" "+types.ExprString(field.Type))
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// interfaceSignature renders an interface with its methods sorted, since
+// adding a method breaks every implementation outside the package
+func interfaceSignature(t *ast.InterfaceType) string {
+	var members []string
+	for _, field := range t.Methods.List {
+		if ft, ok := field.Type.(*ast.FuncType); ok && len(field.Names) > 0 {
+			for _, name := range field.Names {
+				members = append(members, name.Name+funcSignature(ft))
+			}
+			continue
+		}
+		members = append(members, types.ExprString(field.Type)) // Embedded interface or constraint
+	}
+	sort.Strings(members)
+	return "interface{ " + strings.Join(members, "; ") + " }"
+}
+
+// receiverName returns the base type name of a receiver or embedded field
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(t.X)
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.IndexListExpr:
+		return receiverName(t.X)
+	case *ast.SelectorExpr:
+		return types.ExprString(t)
+	case *ast.Ident:
+		return t.Name
+	}
+	return types.ExprString(expr)
+}
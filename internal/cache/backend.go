@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"sort"
+)
+
+const (
+	BackendJSON     = "json"     // Single JSON file, rewritten on every change
+	BackendEmbedded = "embedded" // bbolt database with indexes
+)
+
+// Backend persists analysis results, file and chunk summaries and cache metadata
+type Backend interface {
+	// Get retrieves an unexpired analysis result by content hash
+	Get(contentHash string) (*AnalysisResult, bool)
+	// Put stores an analysis result with the given content hash
+	Put(contentHash string, analysis *AnalysisResult) error
+	// GetSummaries returns the unexpired summaries for the given diff hashes
+	GetSummaries(hashes []string) map[string]string
+	// PutSummaries stores summaries keyed by diff hash
+	PutSummaries(summaries map[string]string) error
+	// RecordFileLookups adds per-file summary cache hits and misses to the metadata
+	RecordFileLookups(hits, misses int) error
+	// MarkResolved marks an analysis as resolved with the given ADR ID
+	MarkResolved(contentHash string, adrID int) error
+	// Delete removes the entries with the given content hashes
+	Delete(contentHashes []string) error
+	// Query returns the unexpired entries matching the query, newest first
+	Query(query Query) ([]CacheEntry, error)
+	// Stats returns entry counts and metadata
+	Stats() (*Stats, error)
+	// Cleanup removes resolved entries and expired entries that are not decision debt
+	Cleanup() error
+	// Clear removes all entries and summaries
+	Clear() error
+}
+
+// Query selects cache entries. Empty fields match everything.
+type Query struct {
+	Branch         string
+	CommitRange    string
+	Resolved       *bool
+	IncludeExpired bool // Also return entries past the cache max age
+}
+
+// Matches reports whether an entry satisfies the query
+func (q Query) Matches(entry CacheEntry) bool {
+	if entry.Analysis == nil {
+		return false
+	}
+	if q.Branch != "" && entry.Analysis.Branch != q.Branch {
+		return false
+	}
+	if q.CommitRange != "" && entry.Analysis.CommitRange != q.CommitRange {
+		return false
+	}
+	if q.Resolved != nil && entry.Analysis.Resolved != *q.Resolved {
+		return false
+	}
+	return true
+}
+
+// Stats summarizes the contents of a cache backend
+type Stats struct {
+	Backend    string
+	Version    string
+	Entries    int
+	Resolved   int
+	Unresolved int
+	Summaries  int
+	Metadata   CacheMetadata
+}
+
+// NewBackend creates the cache backend selected in the configuration
+func NewBackend(workingDir string, config CacheConfig) Backend {
+	switch config.Backend {
+	case BackendEmbedded:
+		return NewEmbeddedStore(workingDir, config)
+	default:
+		return NewStorage(workingDir, config)
+	}
+}
+
+// sortNewestFirst orders entries by analysis timestamp, newest first
+func sortNewestFirst(entries []CacheEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Analysis.Timestamp.After(entries[j].Analysis.Timestamp)
+	})
+}
+
+// countResolved counts resolved and unresolved entries
+func countResolved(entries map[string]CacheEntry) (resolved, unresolved int) {
+	for _, entry := range entries {
+		if entry.Analysis != nil && entry.Analysis.Resolved {
+			resolved++
+		} else {
+			unresolved++
+		}
+	}
+	return resolved, unresolved
+}
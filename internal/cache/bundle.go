@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const BundleVersion = "1"
+
+// Bundle is a portable export of cached analyses
+type Bundle struct {
+	Version    string       `json:"version"`
+	ExportedAt time.Time    `json:"exported_at"`
+	Entries    []CacheEntry `json:"entries"`
+}
+
+// FindEntry returns the cached analysis whose content hash starts with prefix.
+// The prefix must match exactly one entry.
+func (m *Manager) FindEntry(prefix string) (*CacheEntry, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("content hash must not be empty")
+	}
+
+	entries, err := m.storage.Query(Query{IncludeExpired: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []CacheEntry
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.ContentHash, prefix) {
+			matches = append(matches, entry)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no cached analysis matches %s", prefix)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf("%d cached analyses match %s, use a longer prefix", len(matches), prefix)
+	}
+}
+
+// ResolveEntry marks the cached analysis with the given content hash as
+// resolved by an existing ADR
+func (m *Manager) ResolveEntry(contentHash string, adrID int) error {
+	if err := m.storage.MarkResolved(contentHash, adrID); err != nil {
+		return err
+	}
+
+	// Debt entries may be past the cache max age, so look the entry up by query
+	if entry, err := m.FindEntry(contentHash); err == nil {
+		m.shareAnalysis(contentHash, entry.Analysis)
+	}
+	return nil
+}
+
+// Export writes all unexpired cached analyses as a JSON bundle
+func (m *Manager) Export(w io.Writer) (int, error) {
+	entries, err := m.storage.Query(Query{})
+	if err != nil {
+		return 0, err
+	}
+
+	bundle := Bundle{
+		Version:    BundleVersion,
+		ExportedAt: time.Now(),
+		Entries:    entries,
+	}
+	if bundle.Entries == nil {
+		bundle.Entries = []CacheEntry{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(bundle); err != nil {
+		return 0, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return len(entries), nil
+}
+
+// Import merges a JSON bundle into the cache by content hash. An imported
+// analysis replaces the local one only if it is resolved or newer.
+func (m *Manager) Import(r io.Reader) (imported, total int, err error) {
+	var bundle Bundle
+	if err := json.NewDecoder(r).Decode(&bundle); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse bundle: %w", err)
+	}
+	if bundle.Version != BundleVersion {
+		return 0, 0, fmt.Errorf("unsupported bundle version %q", bundle.Version)
+	}
+
+	imported, err = m.merge(bundle.Entries)
+	return imported, len(bundle.Entries), err
+}
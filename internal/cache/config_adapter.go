@@ -58,6 +58,16 @@ func (ca *ConfigAdapter) ConvertConfig(cfg interface{}) CacheConfig {
 			config.CleanupAfter = val
 		}
 	}
+	if field := v.FieldByName("GitNotes"); field.IsValid() && field.CanInterface() {
+		if val, ok := field.Interface().(bool); ok {
+			config.GitNotes = val
+		}
+	}
+	if field := v.FieldByName("Backend"); field.IsValid() && field.CanInterface() {
+		if val, ok := field.Interface().(string); ok && val != "" {
+			config.Backend = val
+		}
+	}
 
 	return config
 }
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"sort"
+	"time"
+)
+
+// UncommittedAuthor groups debt from analyses of uncommitted changes, which
+// cover no commits and therefore have no author
+const UncommittedAuthor = "(uncommitted changes)"
+
+// DebtItem is an analysis that asked for an ADR that was never written
+type DebtItem struct {
+	Entry  CacheEntry
+	Author string    // Author of most commits in the analyzed range
+	Since  time.Time // Date of the oldest covered commit, or of the analysis
+}
+
+// Debt returns the unresolved "ADR needed" decisions in the cache, oldest
+// first. An analysis whose commits are all covered by a newer unresolved
+// analysis, e.g. an earlier run on the same branch, is left out.
+func (m *Manager) Debt() ([]DebtItem, error) {
+	unresolved := false
+	entries, err := m.storage.Query(Query{Resolved: &unresolved, IncludeExpired: true})
+	if err != nil {
+		return nil, err
+	}
+
+	// Entries are newest first, so newer analyses claim their commits first
+	covered := make(map[string]bool)
+	var items []DebtItem
+	for _, entry := range entries {
+		if !entry.Analysis.IsDebt() {
+			continue
+		}
+
+		commits := entry.Analysis.Commits
+		if len(commits) > 0 && allCovered(commits, covered) {
+			continue
+		}
+		for _, commit := range commits {
+			covered[commit.Hash] = true
+		}
+
+		items = append(items, DebtItem{
+			Entry:  entry,
+			Author: primaryAuthor(commits),
+			Since:  debtSince(entry.Analysis),
+		})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Since.Before(items[j].Since)
+	})
+	return items, nil
+}
+
+// allCovered reports whether every commit is in the covered set
+func allCovered(commits []CoveredCommit, covered map[string]bool) bool {
+	for _, commit := range commits {
+		if !covered[commit.Hash] {
+			return false
+		}
+	}
+	return true
+}
+
+// primaryAuthor returns the author of most commits, preferring the author of
+// the oldest commit on a tie
+func primaryAuthor(commits []CoveredCommit) string {
+	if len(commits) == 0 {
+		return UncommittedAuthor
+	}
+
+	counts := make(map[string]int)
+	for _, commit := range commits {
+		counts[commit.Author]++
+	}
+
+	// Commits are newest first, so walk them backwards to prefer older authors
+	author := commits[len(commits)-1].Author
+	for i := len(commits) - 1; i >= 0; i-- {
+		if counts[commits[i].Author] > counts[author] {
+			author = commits[i].Author
+		}
+	}
+	return author
+}
+
+// debtSince returns when the undocumented decision was made: the date of the
+// oldest covered commit, or the analysis time for uncommitted changes
+func debtSince(analysis *AnalysisResult) time.Time {
+	since := analysis.Timestamp
+	for _, commit := range analysis.Commits {
+		if !commit.Date.IsZero() && commit.Date.Before(since) {
+			since = commit.Date
+		}
+	}
+	return since
+}
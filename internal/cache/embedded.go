@@ -0,0 +1,438 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const EmbeddedDBFileName = "analysis.db"
+
+// Buckets of the embedded store. The index buckets hold "<value>\x00<content hash>"
+// keys with empty values, so a prefix scan finds the entries for one value.
+var (
+	entriesBucket     = []byte("entries")
+	summariesBucket   = []byte("summaries")
+	metadataBucket    = []byte("metadata")
+	branchIndex       = []byte("by_branch")
+	rangeIndex        = []byte("by_range")
+	unresolvedIndex   = []byte("unresolved")
+	metadataKey       = []byte("metadata")
+	embeddedBuckets   = [][]byte{entriesBucket, summariesBucket, metadataBucket, branchIndex, rangeIndex, unresolvedIndex}
+	indexKeySeparator = []byte{0}
+)
+
+// EmbeddedStore is the cache backend for large caches, stored in a bbolt
+// database. Changes only touch the keys involved instead of rewriting the whole
+// cache, and entries are indexed by branch, commit range and resolution state.
+// Entries are only removed by expiry or cleanup, not by MaxEntries.
+type EmbeddedStore struct {
+	cacheDir string
+	dbFile   string
+	config   CacheConfig
+}
+
+// NewEmbeddedStore creates an embedded cache backend
+func NewEmbeddedStore(workingDir string, config CacheConfig) *EmbeddedStore {
+	cacheDir := filepath.Join(workingDir, DefaultCacheDir)
+
+	return &EmbeddedStore{
+		cacheDir: cacheDir,
+		dbFile:   filepath.Join(cacheDir, EmbeddedDBFileName),
+		config:   config,
+	}
+}
+
+// Get retrieves an unexpired analysis result by content hash
+func (s *EmbeddedStore) Get(contentHash string) (*AnalysisResult, bool) {
+	var analysis *AnalysisResult
+	err := s.view(func(tx *bolt.Tx) error {
+		entry, exists := getEntry(tx, []byte(contentHash))
+		if exists && !s.isExpiredAt(entry.Analysis.Timestamp) {
+			analysis = entry.Analysis
+		}
+		return nil
+	})
+	return analysis, err == nil && analysis != nil
+}
+
+// Put stores an analysis result with the given content hash
+func (s *EmbeddedStore) Put(contentHash string, analysis *AnalysisResult) error {
+	return s.update(func(tx *bolt.Tx) error {
+		return putEntry(tx, CacheEntry{ContentHash: contentHash, Analysis: analysis})
+	})
+}
+
+// GetSummaries retrieves the unexpired summaries for the given diff hashes
+func (s *EmbeddedStore) GetSummaries(hashes []string) map[string]string {
+	summaries := make(map[string]string)
+	s.view(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(summariesBucket)
+		for _, hash := range hashes {
+			var summary ChunkSummary
+			data := bucket.Get([]byte(hash))
+			if data == nil || json.Unmarshal(data, &summary) != nil {
+				continue
+			}
+			if !s.isExpiredAt(summary.Timestamp) {
+				summaries[hash] = summary.Summary
+			}
+		}
+		return nil
+	})
+	return summaries
+}
+
+// PutSummaries stores summaries keyed by diff hash
+func (s *EmbeddedStore) PutSummaries(summaries map[string]string) error {
+	return s.update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(summariesBucket)
+		now := time.Now()
+		for hash, summary := range summaries {
+			data, err := json.Marshal(ChunkSummary{Summary: summary, Timestamp: now})
+			if err != nil {
+				return fmt.Errorf("failed to marshal summary: %w", err)
+			}
+			if err := bucket.Put([]byte(hash), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RecordFileLookups adds per-file summary cache hits and misses to the metadata
+func (s *EmbeddedStore) RecordFileLookups(hits, misses int) error {
+	if hits == 0 && misses == 0 {
+		return nil
+	}
+
+	return s.update(func(tx *bolt.Tx) error {
+		metadata := s.getMetadata(tx)
+		metadata.FileHits += hits
+		metadata.FileMisses += misses
+		return putMetadata(tx, metadata)
+	})
+}
+
+// MarkResolved marks an analysis as resolved with the given ADR ID
+func (s *EmbeddedStore) MarkResolved(contentHash string, adrID int) error {
+	return s.update(func(tx *bolt.Tx) error {
+		entry, exists := getEntry(tx, []byte(contentHash))
+		if !exists {
+			return fmt.Errorf("cache entry not found for hash: %s", contentHash)
+		}
+
+		entry.Analysis.Resolved = true
+		entry.Analysis.ResolvedADRID = adrID
+		return putEntry(tx, entry)
+	})
+}
+
+// Delete removes the entries with the given content hashes
+func (s *EmbeddedStore) Delete(contentHashes []string) error {
+	if len(contentHashes) == 0 {
+		return nil
+	}
+
+	return s.update(func(tx *bolt.Tx) error {
+		for _, hash := range contentHashes {
+			if err := deleteEntry(tx, []byte(hash)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Query returns the unexpired entries matching the query, newest first. The
+// branch, commit range or unresolved index narrows the entries scanned.
+func (s *EmbeddedStore) Query(query Query) ([]CacheEntry, error) {
+	var entries []CacheEntry
+	err := s.view(func(tx *bolt.Tx) error {
+		return forEachCandidate(tx, query, func(entry CacheEntry) {
+			if query.Matches(entry) && (query.IncludeExpired || !s.isExpiredAt(entry.Analysis.Timestamp)) {
+				entries = append(entries, entry)
+			}
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortNewestFirst(entries)
+	return entries, nil
+}
+
+// Stats returns entry counts and metadata of the embedded store
+func (s *EmbeddedStore) Stats() (*Stats, error) {
+	var stats *Stats
+	err := s.view(func(tx *bolt.Tx) error {
+		entries := tx.Bucket(entriesBucket).Stats().KeyN
+		unresolved := tx.Bucket(unresolvedIndex).Stats().KeyN
+
+		metadata := s.getMetadata(tx)
+		metadata.TotalSize = entries
+		stats = &Stats{
+			Backend:    BackendEmbedded,
+			Version:    CacheVersion,
+			Entries:    entries,
+			Resolved:   entries - unresolved,
+			Unresolved: unresolved,
+			Summaries:  tx.Bucket(summariesBucket).Stats().KeyN,
+			Metadata:   metadata,
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// Cleanup removes resolved entries and expired entries that are not decision
+// debt, and expired summaries. bbolt reuses the freed pages for later writes.
+func (s *EmbeddedStore) Cleanup() error {
+	return s.update(func(tx *bolt.Tx) error {
+		var staleEntries [][]byte
+		err := tx.Bucket(entriesBucket).ForEach(func(key, data []byte) error {
+			var entry CacheEntry
+			if json.Unmarshal(data, &entry) != nil || entry.Analysis == nil {
+				staleEntries = append(staleEntries, bytes.Clone(key))
+				return nil
+			}
+			// Unresolved "ADR needed" decisions are kept as decision debt
+			if (s.isExpiredAt(entry.Analysis.Timestamp) && !entry.Analysis.IsDebt()) || entry.Analysis.Resolved {
+				staleEntries = append(staleEntries, bytes.Clone(key))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range staleEntries {
+			if err := deleteEntry(tx, key); err != nil {
+				return err
+			}
+		}
+
+		var staleSummaries [][]byte
+		summaries := tx.Bucket(summariesBucket)
+		err = summaries.ForEach(func(key, data []byte) error {
+			var summary ChunkSummary
+			if json.Unmarshal(data, &summary) != nil || s.isExpiredAt(summary.Timestamp) {
+				staleSummaries = append(staleSummaries, bytes.Clone(key))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range staleSummaries {
+			if err := summaries.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		metadata := s.getMetadata(tx)
+		metadata.LastCleanup = time.Now()
+		return putMetadata(tx, metadata)
+	})
+}
+
+// Clear removes all entries and summaries
+func (s *EmbeddedStore) Clear() error {
+	return s.update(func(tx *bolt.Tx) error {
+		for _, name := range embeddedBuckets {
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+			if _, err := tx.CreateBucket(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// open opens the database, waiting up to lockTimeout for other DrDuck
+// processes. bbolt locks the file itself: read-only opens share the lock,
+// read-write opens hold it exclusively.
+func (s *EmbeddedStore) open(readOnly bool) (*bolt.DB, error) {
+	if err := os.MkdirAll(s.cacheDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	db, err := bolt.Open(s.dbFile, 0644, &bolt.Options{Timeout: lockTimeout, ReadOnly: readOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache database: %w", err)
+	}
+	return db, nil
+}
+
+// view runs fn in a read-only transaction. The database is only held open for
+// the transaction, so hooks and editor integrations do not block each other.
+func (s *EmbeddedStore) view(fn func(tx *bolt.Tx) error) error {
+	if _, err := os.Stat(s.dbFile); os.IsNotExist(err) {
+		// Create the database and its buckets, as a read-only open cannot
+		if err := s.update(func(tx *bolt.Tx) error { return nil }); err != nil {
+			return err
+		}
+	}
+
+	db, err := s.open(true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.View(func(tx *bolt.Tx) error {
+		for _, name := range embeddedBuckets {
+			if tx.Bucket(name) == nil {
+				return fmt.Errorf("cache database %s has no %s bucket", s.dbFile, name)
+			}
+		}
+		return fn(tx)
+	})
+}
+
+// update runs fn in a read-write transaction, creating missing buckets first.
+// bbolt commits the transaction atomically or not at all.
+func (s *EmbeddedStore) update(fn func(tx *bolt.Tx) error) error {
+	db, err := s.open(false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.Update(func(tx *bolt.Tx) error {
+		for _, name := range embeddedBuckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return fn(tx)
+	})
+}
+
+// getMetadata returns the stored metadata, or fresh metadata if none is stored
+func (s *EmbeddedStore) getMetadata(tx *bolt.Tx) CacheMetadata {
+	metadata := CacheMetadata{LastCleanup: time.Now(), MaxAge: s.config.MaxAge}
+	if data := tx.Bucket(metadataBucket).Get(metadataKey); data != nil {
+		json.Unmarshal(data, &metadata)
+	}
+	return metadata
+}
+
+// isExpiredAt checks if something created at the given time is past the max age
+func (s *EmbeddedStore) isExpiredAt(timestamp time.Time) bool {
+	maxAge := time.Duration(s.config.MaxAge) * 24 * time.Hour
+	return time.Since(timestamp) > maxAge
+}
+
+// putMetadata stores the cache metadata
+func putMetadata(tx *bolt.Tx, metadata CacheMetadata) error {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache metadata: %w", err)
+	}
+	return tx.Bucket(metadataBucket).Put(metadataKey, data)
+}
+
+// getEntry reads one entry; unreadable entries are treated as missing
+func getEntry(tx *bolt.Tx, key []byte) (CacheEntry, bool) {
+	var entry CacheEntry
+	data := tx.Bucket(entriesBucket).Get(key)
+	if data == nil || json.Unmarshal(data, &entry) != nil || entry.Analysis == nil {
+		return CacheEntry{}, false
+	}
+	return entry, true
+}
+
+// putEntry stores an entry and replaces its index records
+func putEntry(tx *bolt.Tx, entry CacheEntry) error {
+	key := []byte(entry.ContentHash)
+	if err := deleteEntry(tx, key); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+	if err := tx.Bucket(entriesBucket).Put(key, data); err != nil {
+		return err
+	}
+
+	if err := tx.Bucket(branchIndex).Put(indexKey(entry.Analysis.Branch, key), nil); err != nil {
+		return err
+	}
+	if err := tx.Bucket(rangeIndex).Put(indexKey(entry.Analysis.CommitRange, key), nil); err != nil {
+		return err
+	}
+	if !entry.Analysis.Resolved {
+		return tx.Bucket(unresolvedIndex).Put(key, nil)
+	}
+	return nil
+}
+
+// deleteEntry removes an entry and its index records, if it exists
+func deleteEntry(tx *bolt.Tx, key []byte) error {
+	if entry, exists := getEntry(tx, key); exists {
+		if err := tx.Bucket(branchIndex).Delete(indexKey(entry.Analysis.Branch, key)); err != nil {
+			return err
+		}
+		if err := tx.Bucket(rangeIndex).Delete(indexKey(entry.Analysis.CommitRange, key)); err != nil {
+			return err
+		}
+	}
+	if err := tx.Bucket(unresolvedIndex).Delete(key); err != nil {
+		return err
+	}
+	return tx.Bucket(entriesBucket).Delete(key)
+}
+
+// forEachCandidate calls fn for the entries of the first index that applies to
+// the query, or for every entry if none does
+func forEachCandidate(tx *bolt.Tx, query Query, fn func(entry CacheEntry)) error {
+	visit := func(key []byte) {
+		if entry, exists := getEntry(tx, key); exists {
+			fn(entry)
+		}
+	}
+
+	switch {
+	case query.Branch != "":
+		scanIndex(tx.Bucket(branchIndex), query.Branch, visit)
+	case query.CommitRange != "":
+		scanIndex(tx.Bucket(rangeIndex), query.CommitRange, visit)
+	case query.Resolved != nil && !*query.Resolved:
+		return tx.Bucket(unresolvedIndex).ForEach(func(key, _ []byte) error {
+			visit(key)
+			return nil
+		})
+	default:
+		return tx.Bucket(entriesBucket).ForEach(func(key, _ []byte) error {
+			visit(key)
+			return nil
+		})
+	}
+	return nil
+}
+
+// scanIndex calls visit with the content hash of every index record for value
+func scanIndex(bucket *bolt.Bucket, value string, visit func(key []byte)) {
+	prefix := indexKey(value, nil)
+	cursor := bucket.Cursor()
+	for key, _ := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, _ = cursor.Next() {
+		visit(key[len(prefix):])
+	}
+}
+
+// indexKey builds the index record key for an entry
+func indexKey(value string, key []byte) []byte {
+	indexed := append([]byte(value), indexKeySeparator...)
+	return append(indexed, key...)
+}
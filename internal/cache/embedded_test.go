@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEmbeddedStoreQueryIndexes(t *testing.T) {
+	store := NewEmbeddedStore(t.TempDir(), testConfig())
+
+	analyses := map[string]*AnalysisResult{
+		"main-debt":     {Decision: "yes", Branch: "main", CommitRange: "a..b", Timestamp: time.Now()},
+		"main-no":       {Decision: "no", Branch: "main", CommitRange: "b..c", Timestamp: time.Now()},
+		"feature-debt":  {Decision: "yes", Branch: "feature", CommitRange: "a..b", Timestamp: time.Now()},
+		"main-resolved": {Decision: "yes", Branch: "main", CommitRange: "c..d", Timestamp: time.Now(), Resolved: true},
+	}
+	for key, analysis := range analyses {
+		if err := store.Put(key, analysis); err != nil {
+			t.Fatalf("put %s: %v", key, err)
+		}
+	}
+
+	unresolved := false
+	tests := []struct {
+		name  string
+		query Query
+		want  int
+	}{
+		{"all", Query{}, 4},
+		{"branch", Query{Branch: "main"}, 3},
+		{"commit range", Query{CommitRange: "a..b"}, 2},
+		{"unresolved", Query{Resolved: &unresolved}, 3},
+		{"branch and unresolved", Query{Branch: "main", Resolved: &unresolved}, 2},
+		{"unknown branch", Query{Branch: "missing"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := store.Query(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != tt.want {
+				t.Errorf("got %d entries, want %d", len(entries), tt.want)
+			}
+		})
+	}
+
+	// Moving an entry to another branch and resolving it must update the indexes
+	moved := *analyses["main-debt"]
+	moved.Branch = "release"
+	if err := store.Put("main-debt", &moved); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.MarkResolved("main-debt", 7); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := store.Query(Query{Branch: "main"}); len(entries) != 2 {
+		t.Errorf("main branch still indexes a moved entry: got %d entries, want 2", len(entries))
+	}
+	if entries, _ := store.Query(Query{Resolved: &unresolved}); len(entries) != 2 {
+		t.Errorf("unresolved index still holds a resolved entry: got %d entries, want 2", len(entries))
+	}
+
+	stats, err := store.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Entries != 4 || stats.Unresolved != 2 {
+		t.Errorf("stats = %d entries, %d unresolved; want 4 and 2", stats.Entries, stats.Unresolved)
+	}
+}
+
+func TestEmbeddedStoreCleanupKeepsDebt(t *testing.T) {
+	store := NewEmbeddedStore(t.TempDir(), testConfig())
+	expired := time.Now().AddDate(0, 0, -testConfig().MaxAge-1)
+
+	store.Put("expired-debt", &AnalysisResult{Decision: "yes", Timestamp: expired})
+	store.Put("expired-no", &AnalysisResult{Decision: "no", Timestamp: expired})
+	store.Put("resolved", &AnalysisResult{Decision: "yes", Timestamp: time.Now(), Resolved: true})
+	store.Put("fresh", &AnalysisResult{Decision: "no", Timestamp: time.Now()})
+
+	if err := store.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := store.Query(Query{IncludeExpired: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept := make(map[string]bool)
+	for _, entry := range entries {
+		kept[entry.ContentHash] = true
+	}
+	if len(kept) != 2 || !kept["expired-debt"] || !kept["fresh"] {
+		t.Errorf("cleanup kept %v, want expired-debt and fresh", kept)
+	}
+
+	if _, found := store.Get("expired-debt"); found {
+		t.Error("expired debt must not be returned as a cache hit")
+	}
+}
+
+func TestEmbeddedStoreConcurrentGoroutines(t *testing.T) {
+	dir := t.TempDir()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrentWriters)
+	for writer := 0; writer < concurrentWriters; writer++ {
+		wg.Add(1)
+		go func(writer int) {
+			defer wg.Done()
+			if err := putAndGet(NewEmbeddedStore(dir, testConfig()), writer); err != nil {
+				errs <- err
+			}
+		}(writer)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	entries, err := NewEmbeddedStore(dir, testConfig()).Query(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := concurrentWriters * putsPerWriter; len(entries) != want {
+		t.Errorf("got %d entries, want %d", len(entries), want)
+	}
+}
@@ -6,13 +6,18 @@ import (
 	"fmt"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/SilverFlin/DrDuck/internal/apidiff"
+	"github.com/SilverFlin/DrDuck/internal/diff"
 )
 
 // Fingerprinter generates content-based fingerprints for git changes
 type Fingerprinter struct {
-	config CacheConfig
+	config   CacheConfig
+	analysis AnalysisConfig
 }
 
 // NewFingerprinter creates a new change fingerprinter
@@ -48,6 +53,8 @@ func (f *Fingerprinter) GenerateFingerprint() (string, *ChangeFingerprint, error
 		Files:       make(map[string]string),
 		CommitRange: commitRange,
 		Branch:      branch,
+		HeadCommit:  f.getHeadCommit(),
+		Analysis:    f.analysis,
 		Timestamp:   time.Now(),
 	}
 
@@ -72,25 +79,49 @@ func (f *Fingerprinter) getCurrentBranch() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// getHeadCommit returns the full hash of HEAD, or "" in a repository without commits
+func (f *Fingerprinter) getHeadCommit() string {
+	output, err := exec.Command("git", "rev-parse", "--verify", "--quiet", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// CoveredCommits lists the commits in a commit range, newest first. The
+// "HEAD" range stands for uncommitted changes and covers no commits.
+func (f *Fingerprinter) CoveredCommits(commitRange string) ([]CoveredCommit, error) {
+	if commitRange == "HEAD" || commitRange == "" {
+		return nil, nil
+	}
+
+	output, err := exec.Command("git", "log", "--format=%H%x1f%an%x1f%aI%x1f%s", commitRange).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []CoveredCommit
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.SplitN(line, "\x1f", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, fields[2])
+		commits = append(commits, CoveredCommit{Hash: fields[0], Author: fields[1], Date: date, Subject: fields[3]})
+	}
+	return commits, nil
+}
+
 // getCommitRange determines the appropriate commit range for analysis
 func (f *Fingerprinter) getCommitRange() (string, error) {
 	// Try different strategies to get a meaningful commit range
 	
-	// Strategy 1: Changes since last push (origin/branch)
-	branch, err := f.getCurrentBranch()
-	if err == nil {
-		remoteBranch := fmt.Sprintf("origin/%s", branch)
-		if f.remoteExists(remoteBranch) {
-			return fmt.Sprintf("%s..HEAD", remoteBranch), nil
-		}
+	// Strategy 1: Changes since the branch's base, as the pre-push hook sees them
+	if baseRef, err := apidiff.BaseRef(); err == nil {
+		return fmt.Sprintf("%s..HEAD", baseRef), nil
 	}
 
-	// Strategy 2: Last few commits if no remote
-	if f.hasCommits("HEAD~3") {
-		return "HEAD~3..HEAD", nil
-	}
-
-	// Strategy 3: All uncommitted changes
+	// Strategy 2: All uncommitted changes
 	return "HEAD", nil
 }
 
@@ -218,51 +249,47 @@ func (f *Fingerprinter) matchesPattern(filePath, pattern string) bool {
 	return filePath == pattern || strings.HasPrefix(filePath, pattern)
 }
 
-// generateFileHashes creates individual hashes for changed files
-func (f *Fingerprinter) generateFileHashes(diff string, fingerprint *ChangeFingerprint) error {
-	if diff == "" {
+// generateFileHashes creates individual hashes for changed files, so that
+// per-file summaries survive changes to other files
+func (f *Fingerprinter) generateFileHashes(filteredDiff string, fingerprint *ChangeFingerprint) error {
+	if filteredDiff == "" {
 		return nil
 	}
 
-	// For simplicity, we'll hash the entire filtered diff
-	// In a more sophisticated implementation, we could hash individual file changes
-	hasher := sha256.New()
-	hasher.Write([]byte(diff))
-	diffHash := hex.EncodeToString(hasher.Sum(nil))
-
-	fingerprint.Files["filtered_diff"] = diffHash
+	for _, file := range diff.Parse(filteredDiff) {
+		fingerprint.Files[file.Path()] = hashFileDiff(file)
+	}
 	return nil
 }
 
-// hashFingerprint generates a single hash from the entire fingerprint
+// hashFingerprint generates a single hash from the changed files and the
+// analysis configuration. The branch and commit range are left out, so a CI job
+// on a detached HEAD or a teammate on another branch finds the analysis shared
+// through git notes for the same changes.
 func (f *Fingerprinter) hashFingerprint(fingerprint *ChangeFingerprint) string {
 	hasher := sha256.New()
 
-	// Include commit range and branch
-	hasher.Write([]byte(fingerprint.CommitRange))
-	hasher.Write([]byte(fingerprint.Branch))
+	// Include the analysis configuration, so answers from another provider,
+	// model, persona or prompt version are not reused
+	hasher.Write([]byte(fingerprint.Analysis.Provider))
+	hasher.Write([]byte(fingerprint.Analysis.Model))
+	hasher.Write([]byte(fingerprint.Analysis.Persona))
+	hasher.Write([]byte(fingerprint.Analysis.PromptHash))
 
 	// Include all file hashes in a consistent order
-	for filePath, fileHash := range fingerprint.Files {
+	filePaths := make([]string, 0, len(fingerprint.Files))
+	for filePath := range fingerprint.Files {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+	for _, filePath := range filePaths {
 		hasher.Write([]byte(filePath))
-		hasher.Write([]byte(fileHash))
+		hasher.Write([]byte(fingerprint.Files[filePath]))
 	}
 
 	return hex.EncodeToString(hasher.Sum(nil))
 }
 
-// remoteExists checks if a remote branch exists
-func (f *Fingerprinter) remoteExists(remoteBranch string) bool {
-	cmd := exec.Command("git", "rev-parse", "--verify", remoteBranch)
-	return cmd.Run() == nil
-}
-
-// hasCommits checks if a commit reference exists
-func (f *Fingerprinter) hasCommits(ref string) bool {
-	cmd := exec.Command("git", "rev-parse", "--verify", ref)
-	return cmd.Run() == nil
-}
-
 // GetCurrentChanges returns a human-readable summary of current changes
 // This can be used for logging and debugging
 func (f *Fingerprinter) GetCurrentChanges() (string, error) {
@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/SilverFlin/DrDuck/internal/diff"
+)
+
+// hashFileDiff hashes the diff of a single file. The same hash keys
+// ChangeFingerprint.Files and the per-file summaries.
+func hashFileDiff(file *diff.File) string {
+	hasher := sha256.New()
+	hasher.Write([]byte(file.Raw))
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// summaryKey namespaces the hash of a file or chunk diff by the provider and
+// model that summarize it, so switching either does not reuse another model's summaries
+func (m *Manager) summaryKey(diffHash string) string {
+	analysis := m.fingerprinter.analysis
+	hasher := sha256.New()
+	hasher.Write([]byte(analysis.Provider + "\x00" + analysis.Model + "\x00" + diffHash))
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// IncrementalPlan splits a change set into files whose summaries are already
+// cached and files that still have to be sent to the AI provider
+type IncrementalPlan struct {
+	Cached  map[string]string // File path -> cached summary
+	Changed []*diff.File      // Files without a cached summary
+	hashes  map[string]string // File path -> summary key
+}
+
+// PlanIncremental looks up a cached summary for every file in the change set
+// and records the per-file hit rate
+func (m *Manager) PlanIncremental(files []*diff.File) *IncrementalPlan {
+	plan := &IncrementalPlan{
+		Cached: make(map[string]string),
+		hashes: make(map[string]string),
+	}
+
+	hashes := make([]string, 0, len(files))
+	for _, file := range files {
+		fileHash := m.summaryKey(hashFileDiff(file))
+		plan.hashes[file.Path()] = fileHash
+		hashes = append(hashes, fileHash)
+	}
+
+	cached := m.storage.GetSummaries(hashes)
+	for _, file := range files {
+		if summary, found := cached[plan.hashes[file.Path()]]; found {
+			plan.Cached[file.Path()] = summary
+		} else {
+			plan.Changed = append(plan.Changed, file)
+		}
+	}
+
+	// Hit rate bookkeeping is best effort
+	_ = m.storage.RecordFileLookups(len(plan.Cached), len(plan.Changed))
+
+	return plan
+}
+
+// HasCachedFiles reports whether any file can be represented by its cached summary
+func (p *IncrementalPlan) HasCachedFiles() bool {
+	return len(p.Cached) > 0
+}
+
+// CachedSummaries renders the cached file summaries as a markdown list, sorted by path
+func (p *IncrementalPlan) CachedSummaries() string {
+	paths := make([]string, 0, len(p.Cached))
+	for filePath := range p.Cached {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	var builder strings.Builder
+	for _, filePath := range paths {
+		builder.WriteString(fmt.Sprintf("- %s: %s\n", filePath, p.Cached[filePath]))
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+// StoreFileSummaries caches the summaries the provider returned for the files
+// that were sent in full. Summaries for unknown paths are ignored.
+func (m *Manager) StoreFileSummaries(plan *IncrementalPlan, summaries map[string]string) error {
+	byHash := make(map[string]string)
+	for _, file := range plan.Changed {
+		if summary, ok := summaries[file.Path()]; ok && summary != "" {
+			byHash[plan.hashes[file.Path()]] = summary
+		}
+	}
+	if len(byHash) == 0 {
+		return nil
+	}
+	return m.storage.PutSummaries(byHash)
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	lockTimeout       = 10 * time.Second
+	lockRetryInterval = 25 * time.Millisecond
+	lockFileSuffix    = ".lock"
+)
+
+// errLocked is returned by tryLock when another process holds the lock
+var errLocked = errors.New("cache is locked by another process")
+
+// lock acquires the advisory lock on the JSON cache file
+func (s *Storage) lock() (*fileLock, error) {
+	return acquireLock(s.cacheDir, s.cacheFile+lockFileSuffix)
+}
+
+// acquireLock takes the advisory cache lock shared by every DrDuck process working
+// in this repository (hooks, IDE integrations and manual runs), waiting up to lockTimeout
+func acquireLock(cacheDir, lockPath string) (*fileLock, error) {
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		lock, err := tryLock(lockPath)
+		if err == nil {
+			return lock, nil
+		}
+		if !errors.Is(err, errLocked) {
+			return nil, fmt.Errorf("failed to lock cache: %w", err)
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out after %v waiting for cache lock %s", lockTimeout, lockPath)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
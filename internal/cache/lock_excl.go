@@ -0,0 +1,43 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+
+package cache
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// staleLockAge is how old a lock file must be before it is assumed to belong
+// to a process that died without releasing it
+const staleLockAge = 2 * time.Minute
+
+// fileLock is a lock file created exclusively, used where flock(2) is unavailable (e.g. Windows)
+type fileLock struct {
+	path string
+	file *os.File
+}
+
+// tryLock creates the lock file with O_EXCL, failing with errLocked if it already exists
+func tryLock(path string) (*fileLock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		// Break locks left behind by crashed processes
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(path)
+		}
+		return nil, errLocked
+	}
+
+	fmt.Fprintf(file, "%d\n", os.Getpid())
+	return &fileLock{path: path, file: file}, nil
+}
+
+// unlock releases the lock by removing the lock file
+func (l *fileLock) unlock() error {
+	l.file.Close()
+	return os.Remove(l.path)
+}
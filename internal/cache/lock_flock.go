@@ -0,0 +1,40 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package cache
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// fileLock is an flock(2) lock held on the cache lock file
+type fileLock struct {
+	file *os.File
+}
+
+// tryLock takes an exclusive, non-blocking flock on the lock file. The lock is
+// released by the kernel if the process dies, so there are no stale locks.
+func tryLock(path string) (*fileLock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, errLocked
+		}
+		return nil, err
+	}
+
+	return &fileLock{file: file}, nil
+}
+
+// unlock releases the lock. The lock file itself is kept, as removing it would
+// let two processes lock different files with the same name.
+func (l *fileLock) unlock() error {
+	defer l.file.Close()
+	return syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
+}
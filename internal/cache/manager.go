@@ -2,24 +2,27 @@ package cache
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
 // Manager provides high-level cache operations for ADR analysis
 type Manager struct {
-	storage      *Storage
+	storage      Backend
 	fingerprinter *Fingerprinter
+	notes        *GitNotes
 	config       CacheConfig
 }
 
 // NewManager creates a new cache manager
 func NewManager(workingDir string, config CacheConfig) *Manager {
-	storage := NewStorage(workingDir, config)
+	storage := NewBackend(workingDir, config)
 	fingerprinter := NewFingerprinter(config)
 
 	return &Manager{
 		storage:       storage,
 		fingerprinter: fingerprinter,
+		notes:         NewGitNotes(workingDir),
 		config:        config,
 	}
 }
@@ -41,10 +44,16 @@ func NewManagerFromConfig(workingDir string, cfg interface{}) *Manager {
 	return NewManager(workingDir, cacheConfig)
 }
 
+// SetAnalysisConfig sets the provider, model, persona and prompt version that
+// analyses are produced with. It becomes part of every fingerprint.
+func (m *Manager) SetAnalysisConfig(analysisConfig AnalysisConfig) {
+	m.fingerprinter.analysis = analysisConfig
+}
+
 // GetAnalysis retrieves cached analysis for current changes, if available
 func (m *Manager) GetAnalysis() (*AnalysisResult, bool, error) {
 	// Generate fingerprint for current changes
-	contentHash, _, err := m.fingerprinter.GenerateFingerprint()
+	contentHash, fingerprint, err := m.fingerprinter.GenerateFingerprint()
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to generate fingerprint: %w", err)
 	}
@@ -52,7 +61,11 @@ func (m *Manager) GetAnalysis() (*AnalysisResult, bool, error) {
 	// Look up analysis in cache
 	analysis, found := m.storage.Get(contentHash)
 	if !found {
-		return nil, false, nil
+		// A teammate or CI may already have analyzed the same changes
+		analysis, found = m.findSharedAnalysis(contentHash, fingerprint.HeadCommit)
+		if !found {
+			return nil, false, nil
+		}
 	}
 
 	// Double-check that analysis is not resolved
@@ -74,11 +87,13 @@ func (m *Manager) StoreAnalysis(decision, suggestion, title string) error {
 	// Extract changed file paths for metadata
 	changedFiles := make([]string, 0, len(fingerprint.Files))
 	for filePath := range fingerprint.Files {
-		if filePath != "filtered_diff" { // Skip our internal key
-			changedFiles = append(changedFiles, filePath)
-		}
+		changedFiles = append(changedFiles, filePath)
 	}
 
+	// Commits are recorded so unresolved decisions can be traced back later;
+	// this is best effort, e.g. in a repository without commits
+	commits, _ := m.fingerprinter.CoveredCommits(fingerprint.CommitRange)
+
 	// Create analysis result
 	analysis := &AnalysisResult{
 		Decision:        decision,
@@ -87,12 +102,41 @@ func (m *Manager) StoreAnalysis(decision, suggestion, title string) error {
 		Timestamp:       time.Now(),
 		ChangesAnalyzed: changedFiles,
 		CommitRange:     fingerprint.CommitRange,
+		Branch:          fingerprint.Branch,
+		HeadCommit:      fingerprint.HeadCommit,
+		Config:          fingerprint.Analysis,
+		Commits:         commits,
 		Resolved:        false,
 		ResolvedADRID:   0,
 	}
 
 	// Store in cache
-	return m.storage.Put(contentHash, analysis)
+	if err := m.storage.Put(contentHash, analysis); err != nil {
+		return err
+	}
+
+	m.shareAnalysis(contentHash, analysis)
+	return nil
+}
+
+// GetChunkSummary retrieves a cached summary for one chunk of a large change set
+func (m *Manager) GetChunkSummary(chunkHash string) (string, bool) {
+	key := m.summaryKey(chunkHash)
+	summary, found := m.storage.GetSummaries([]string{key})[key]
+	return summary, found
+}
+
+// StoreChunkSummaries caches summaries for chunks of a large change set
+func (m *Manager) StoreChunkSummaries(summaries map[string]string) error {
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	byKey := make(map[string]string, len(summaries))
+	for chunkHash, summary := range summaries {
+		byKey[m.summaryKey(chunkHash)] = summary
+	}
+	return m.storage.PutSummaries(byKey)
 }
 
 // MarkResolved marks the current changes as resolved by creating an ADR
@@ -103,12 +147,89 @@ func (m *Manager) MarkResolved(adrID int) error {
 		return fmt.Errorf("failed to generate fingerprint: %w", err)
 	}
 
-	return m.storage.MarkResolved(contentHash, adrID)
+	return m.ResolveEntry(contentHash, adrID)
+}
+
+// findSharedAnalysis looks up an analysis in the git note on HEAD when git
+// notes sharing is enabled, and copies it into the local cache
+func (m *Manager) findSharedAnalysis(contentHash, headCommit string) (*AnalysisResult, bool) {
+	if !m.config.GitNotes || headCommit == "" {
+		return nil, false
+	}
+
+	analysis, found := m.notes.Find(headCommit, contentHash)
+	if !found {
+		return nil, false
+	}
+
+	_ = m.storage.Put(contentHash, analysis) // Best effort, the analysis is usable either way
+	return analysis, true
+}
+
+// shareAnalysis writes an analysis to the git note on its HEAD commit when git
+// notes sharing is enabled. Sharing is best effort and never fails the caller.
+func (m *Manager) shareAnalysis(contentHash string, analysis *AnalysisResult) {
+	if !m.config.GitNotes || analysis.HeadCommit == "" {
+		return
+	}
+	_ = m.notes.Write(analysis.HeadCommit, []NoteRecord{{ContentHash: contentHash, Analysis: analysis}})
+}
+
+// PushNotes exports all cached analyses to git notes and pushes them to the remote
+func (m *Manager) PushNotes(remote string) (exported, skipped int, err error) {
+	entries, err := m.storage.Query(Query{})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	exported, skipped, err = m.notes.Export(entries)
+	if err != nil {
+		return exported, skipped, err
+	}
+
+	return exported, skipped, m.notes.Push(remote)
+}
+
+// PullNotes fetches git notes from the remote and imports analyses that are
+// missing locally or newer than the local copy
+func (m *Manager) PullNotes(remote string) (imported int, err error) {
+	if err := m.notes.Pull(remote); err != nil {
+		return 0, err
+	}
+
+	records, err := m.notes.All()
+	if err != nil {
+		return 0, err
+	}
+
+	entries := make([]CacheEntry, 0, len(records))
+	for _, record := range records {
+		entries = append(entries, CacheEntry{ContentHash: record.ContentHash, Analysis: record.Analysis})
+	}
+	return m.merge(entries)
+}
+
+// merge stores entries that are missing locally or newer than the local copy
+func (m *Manager) merge(entries []CacheEntry) (merged int, err error) {
+	for _, entry := range entries {
+		if entry.ContentHash == "" || entry.Analysis == nil {
+			continue
+		}
+		local, found := m.storage.Get(entry.ContentHash)
+		if found && !newerAnalysis(entry.Analysis, local) {
+			continue
+		}
+		if err := m.storage.Put(entry.ContentHash, entry.Analysis); err != nil {
+			return merged, err
+		}
+		merged++
+	}
+	return merged, nil
 }
 
 // HasUnresolvedAnalysis checks if there are any unresolved analyses
 func (m *Manager) HasUnresolvedAnalysis() (bool, error) {
-	unresolved, err := m.storage.GetUnresolvedAnalyses()
+	unresolved, err := m.GetUnresolvedAnalyses()
 	if err != nil {
 		return false, err
 	}
@@ -118,7 +239,47 @@ func (m *Manager) HasUnresolvedAnalysis() (bool, error) {
 
 // GetUnresolvedAnalyses returns all unresolved analyses
 func (m *Manager) GetUnresolvedAnalyses() (map[string]*AnalysisResult, error) {
-	return m.storage.GetUnresolvedAnalyses()
+	resolved := false
+	entries, err := m.storage.Query(Query{Resolved: &resolved})
+	if err != nil {
+		return nil, err
+	}
+
+	unresolved := make(map[string]*AnalysisResult, len(entries))
+	for _, entry := range entries {
+		unresolved[entry.ContentHash] = entry.Analysis
+	}
+	return unresolved, nil
+}
+
+// Invalidate removes cached analyses whose configuration matches the non-empty
+// fields of the filter and returns how many were removed
+func (m *Manager) Invalidate(filter AnalysisConfig) (int, error) {
+	entries, err := m.storage.Query(Query{})
+	if err != nil {
+		return 0, err
+	}
+
+	var hashes []string
+	for _, entry := range entries {
+		config := entry.Analysis.Config
+		if (filter.Provider == "" || config.Provider == filter.Provider) &&
+			(filter.Model == "" || config.Model == filter.Model) &&
+			(filter.Persona == "" || config.Persona == filter.Persona) &&
+			(filter.PromptHash == "" || strings.HasPrefix(config.PromptHash, filter.PromptHash)) {
+			hashes = append(hashes, entry.ContentHash)
+		}
+	}
+
+	if err := m.storage.Delete(hashes); err != nil {
+		return 0, err
+	}
+	return len(hashes), nil
+}
+
+// Query returns the cached analyses matching the query, newest first
+func (m *Manager) Query(query Query) ([]CacheEntry, error) {
+	return m.storage.Query(query)
 }
 
 // Cleanup removes expired and resolved entries from cache
@@ -158,31 +319,37 @@ func (m *Manager) ShouldAnalyze() (bool, string, error) {
 
 // GetCacheStats returns statistics about the cache
 func (m *Manager) GetCacheStats() (map[string]interface{}, error) {
-	cache, err := m.storage.Load()
+	cacheStats, err := m.storage.Stats()
 	if err != nil {
 		return nil, err
 	}
 
 	stats := make(map[string]interface{})
-	stats["total_entries"] = len(cache.Entries)
-	stats["version"] = cache.Version
-	stats["last_cleanup"] = cache.Metadata.LastCleanup
-	
-	// Count resolved vs unresolved
-	resolved := 0
-	unresolved := 0
-	for _, entry := range cache.Entries {
-		if entry.Analysis.Resolved {
-			resolved++
-		} else {
-			unresolved++
-		}
+	stats["backend"] = cacheStats.Backend
+	stats["total_entries"] = cacheStats.Entries
+	stats["version"] = cacheStats.Version
+	stats["last_cleanup"] = cacheStats.Metadata.LastCleanup
+	stats["chunk_summaries"] = cacheStats.Summaries
+	stats["file_hits"] = cacheStats.Metadata.FileHits
+	stats["file_misses"] = cacheStats.Metadata.FileMisses
+	if lookups := cacheStats.Metadata.FileHits + cacheStats.Metadata.FileMisses; lookups > 0 {
+		stats["file_hit_rate"] = float64(cacheStats.Metadata.FileHits) / float64(lookups)
 	}
-	
-	stats["resolved_entries"] = resolved
-	stats["unresolved_entries"] = unresolved
+	stats["resolved_entries"] = cacheStats.Resolved
+	stats["unresolved_entries"] = cacheStats.Unresolved
 	stats["max_age_days"] = m.config.MaxAge
 	stats["max_entries"] = m.config.MaxEntries
 
+	// Break entries down by the configuration that produced them
+	entries, err := m.storage.Query(Query{})
+	if err != nil {
+		return nil, err
+	}
+	byConfig := make(map[string]int)
+	for _, entry := range entries {
+		byConfig[entry.Analysis.Config.String()]++
+	}
+	stats["entries_by_config"] = byConfig
+
 	return stats, nil
-}
\ No newline at end of file
+}
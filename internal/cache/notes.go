@@ -0,0 +1,270 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	NotesRef       = "refs/notes/drduck"
+	notesRemoteRef = "refs/notes/drduck-remote" // Fetched notes waiting to be merged
+)
+
+// NoteRecord is one analysis stored in a git note. Notes hold one JSON record
+// per line, so concurrent edits can be merged with git's cat_sort_uniq strategy.
+type NoteRecord struct {
+	ContentHash string          `json:"content_hash"`
+	Analysis    *AnalysisResult `json:"analysis"`
+}
+
+// GitNotes shares analysis results through git notes attached to the commit
+// that was HEAD when the analysis ran, synced over the normal git remote
+type GitNotes struct {
+	dir string // Repository working directory; empty for the current directory
+}
+
+// NewGitNotes creates a git notes cache layer for the repository at dir
+func NewGitNotes(dir string) *GitNotes {
+	return &GitNotes{dir: dir}
+}
+
+// Find looks up an analysis for the content hash in the note on a commit
+func (n *GitNotes) Find(commit, contentHash string) (*AnalysisResult, bool) {
+	records, err := n.Read(commit)
+	if err != nil {
+		return nil, false
+	}
+	for _, record := range records {
+		if record.ContentHash == contentHash {
+			return record.Analysis, true
+		}
+	}
+	return nil, false
+}
+
+// Read returns the analyses in the note on a commit, newest first per content hash
+func (n *GitNotes) Read(commit string) ([]NoteRecord, error) {
+	output, err := n.git(nil, "notes", "--ref="+NotesRef, "show", commit)
+	if err != nil {
+		// No note on this commit
+		return nil, nil
+	}
+	return parseNoteRecords(output), nil
+}
+
+// Write adds analyses to the note on a commit, keeping existing records for
+// other content hashes
+func (n *GitNotes) Write(commit string, records []NoteRecord) error {
+	existing, err := n.Read(commit)
+	if err != nil {
+		return err
+	}
+
+	var content bytes.Buffer
+	for _, record := range dedupeNoteRecords(append(existing, records...)) {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal note record: %w", err)
+		}
+		content.Write(data)
+		content.WriteByte('\n')
+	}
+
+	if _, err := n.git(&content, "notes", "--ref="+NotesRef, "add", "-f", "-F", "-", commit); err != nil {
+		return fmt.Errorf("failed to write git note on %s: %w", commit, err)
+	}
+	return nil
+}
+
+// Export writes cache entries to notes on their HEAD commit. Entries recorded
+// before HEAD commits were tracked are skipped.
+func (n *GitNotes) Export(entries []CacheEntry) (exported, skipped int, err error) {
+	byCommit := make(map[string][]NoteRecord)
+	for _, entry := range entries {
+		if entry.Analysis == nil || entry.Analysis.HeadCommit == "" {
+			skipped++
+			continue
+		}
+		commit := entry.Analysis.HeadCommit
+		byCommit[commit] = append(byCommit[commit], NoteRecord{ContentHash: entry.ContentHash, Analysis: entry.Analysis})
+	}
+
+	for commit, records := range byCommit {
+		if err := n.Write(commit, records); err != nil {
+			return exported, skipped, err
+		}
+		exported += len(records)
+	}
+	return exported, skipped, nil
+}
+
+// All returns every analysis stored in notes
+func (n *GitNotes) All() ([]NoteRecord, error) {
+	output, err := n.git(nil, "notes", "--ref="+NotesRef, "list")
+	if err != nil {
+		// The notes ref does not exist yet
+		return nil, nil
+	}
+
+	var noteObjects []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 {
+			noteObjects = append(noteObjects, fields[0])
+		}
+	}
+
+	contents, err := n.readObjects(noteObjects)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []NoteRecord
+	for _, content := range contents {
+		records = append(records, parseNoteRecords(content)...)
+	}
+	return dedupeNoteRecords(records), nil
+}
+
+// Push sends the notes ref to a remote
+func (n *GitNotes) Push(remote string) error {
+	if _, err := n.git(nil, "rev-parse", "--verify", "--quiet", NotesRef); err != nil {
+		return fmt.Errorf("no analyses have been exported to %s yet", NotesRef)
+	}
+	if _, err := n.git(nil, "push", remote, NotesRef+":"+NotesRef); err != nil {
+		return fmt.Errorf("failed to push %s to %s (run 'drduck cache pull' first if the remote has newer notes): %w", NotesRef, remote, err)
+	}
+	return nil
+}
+
+// Pull fetches the notes ref from a remote and merges it into the local one
+func (n *GitNotes) Pull(remote string) error {
+	if _, err := n.git(nil, "fetch", remote, "+"+NotesRef+":"+notesRemoteRef); err != nil {
+		return fmt.Errorf("failed to fetch %s from %s: %w", NotesRef, remote, err)
+	}
+	defer n.git(nil, "update-ref", "-d", notesRemoteRef)
+
+	if _, err := n.git(nil, "rev-parse", "--verify", "--quiet", NotesRef); err != nil {
+		// No local notes yet: take the remote ones as they are
+		if _, err := n.git(nil, "update-ref", NotesRef, notesRemoteRef); err != nil {
+			return fmt.Errorf("failed to create %s: %w", NotesRef, err)
+		}
+		return nil
+	}
+
+	if _, err := n.git(nil, "notes", "--ref="+NotesRef, "merge", "--quiet", "--strategy=cat_sort_uniq", notesRemoteRef); err != nil {
+		return fmt.Errorf("failed to merge remote notes: %w", err)
+	}
+	return nil
+}
+
+// readObjects reads git objects with a single "git cat-file --batch" process
+func (n *GitNotes) readObjects(objects []string) ([][]byte, error) {
+	if len(objects) == 0 {
+		return nil, nil
+	}
+
+	var input bytes.Buffer
+	for _, object := range objects {
+		input.WriteString(object + "\n")
+	}
+
+	output, err := n.git(&input, "cat-file", "--batch")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git notes: %w", err)
+	}
+
+	var contents [][]byte
+	reader := bufio.NewReader(bytes.NewReader(output))
+	for range objects {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("failed to read git object header: %w", err)
+		}
+		fields := strings.Fields(header)
+		if len(fields) == 2 && fields[1] == "missing" {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected git object header: %q", header)
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid git object size: %w", err)
+		}
+
+		content := make([]byte, size+1) // Content is followed by a newline
+		if _, err := io.ReadFull(reader, content); err != nil {
+			return nil, fmt.Errorf("failed to read git note: %w", err)
+		}
+		contents = append(contents, content[:size])
+	}
+	return contents, nil
+}
+
+// git runs a git command in the repository and returns its output
+func (n *GitNotes) git(stdin io.Reader, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = n.dir
+	cmd.Stdin = stdin
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("%w: %s", err, message)
+		}
+		return nil, err
+	}
+	return output, nil
+}
+
+// parseNoteRecords parses a note, skipping lines that are not analysis records
+func parseNoteRecords(content []byte) []NoteRecord {
+	var records []NoteRecord
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		var record NoteRecord
+		if err := json.Unmarshal(line, &record); err != nil || record.ContentHash == "" || record.Analysis == nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	return dedupeNoteRecords(records)
+}
+
+// dedupeNoteRecords keeps the newest analysis per content hash, sorted by hash.
+// A resolved analysis wins over an unresolved one of the same content.
+func dedupeNoteRecords(records []NoteRecord) []NoteRecord {
+	byHash := make(map[string]NoteRecord)
+	for _, record := range records {
+		current, exists := byHash[record.ContentHash]
+		if !exists || newerAnalysis(record.Analysis, current.Analysis) {
+			byHash[record.ContentHash] = record
+		}
+	}
+
+	deduped := make([]NoteRecord, 0, len(byHash))
+	for _, record := range byHash {
+		deduped = append(deduped, record)
+	}
+	sort.Slice(deduped, func(i, j int) bool {
+		return deduped[i].ContentHash < deduped[j].ContentHash
+	})
+	return deduped
+}
+
+// newerAnalysis reports whether candidate should replace current when two
+// copies of the analysis of the same changes are merged
+func newerAnalysis(candidate, current *AnalysisResult) bool {
+	if candidate.Resolved != current.Resolved {
+		return candidate.Resolved
+	}
+	return candidate.Timestamp.After(current.Timestamp)
+}
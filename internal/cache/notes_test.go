@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// git runs a git command in dir and returns its trimmed output
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// isolateGit keeps the user's and system git configuration out of the test
+func isolateGit(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Dev")
+	t.Setenv("GIT_AUTHOR_EMAIL", "dev@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Dev")
+	t.Setenv("GIT_COMMITTER_EMAIL", "dev@example.com")
+}
+
+func notesConfig() CacheConfig {
+	config := DefaultCacheConfig()
+	config.GitNotes = true
+	return config
+}
+
+// newNotesManager creates a cache manager for a clone. The fingerprinter runs
+// git in the working directory, so the test changes into the clone.
+func newNotesManager(t *testing.T, dir string) *Manager {
+	t.Chdir(dir)
+	manager := NewManager(dir, notesConfig())
+	manager.SetAnalysisConfig(AnalysisConfig{Provider: "claude-code", Persona: "drduck", PromptHash: "v1"})
+	return manager
+}
+
+func TestGitNotesRoundTripThroughBareRemote(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	isolateGit(t)
+
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote.git")
+	dev := filepath.Join(root, "dev")
+	git(t, root, "init", "--quiet", "--bare", "--initial-branch=main", remote)
+	git(t, root, "clone", "--quiet", remote, dev)
+
+	// The developer pushes a base, then commits a change on a feature branch.
+	// The teammate's review branch starts from the same base.
+	os.WriteFile(filepath.Join(dev, "main.go"), []byte("package main\n"), 0644)
+	git(t, dev, "add", ".")
+	git(t, dev, "commit", "--quiet", "-m", "base")
+	git(t, dev, "push", "--quiet", "origin", "HEAD:main", "HEAD:feature", "HEAD:review")
+	git(t, dev, "checkout", "--quiet", "-b", "feature", "--track", "origin/feature")
+	os.WriteFile(filepath.Join(dev, "store.go"), []byte("package main\n\nvar store = map[string]string{}\n"), 0644)
+	git(t, dev, "add", ".")
+	git(t, dev, "commit", "--quiet", "-m", "add store")
+
+	// Pre-push: analyze the unpushed commit and share the analysis
+	devManager := newNotesManager(t, dev)
+	if err := devManager.StoreAnalysis("yes", "Introduces a store", "introduce-store"); err != nil {
+		t.Fatalf("storing analysis: %v", err)
+	}
+	git(t, dev, "push", "--quiet", "origin", "feature")
+	if _, _, err := devManager.PushNotes("origin"); err != nil {
+		t.Fatalf("pushing notes: %v", err)
+	}
+	head := git(t, dev, "rev-parse", "HEAD")
+
+	t.Run("CI on a detached HEAD", func(t *testing.T) {
+		ci := filepath.Join(root, "ci")
+		git(t, root, "clone", "--quiet", remote, ci)
+		git(t, ci, "checkout", "--quiet", "--detach", head)
+
+		ciManager := newNotesManager(t, ci)
+		if _, err := ciManager.PullNotes("origin"); err != nil {
+			t.Fatalf("pulling notes: %v", err)
+		}
+		assertSharedHit(t, ciManager)
+	})
+
+	t.Run("teammate on another branch", func(t *testing.T) {
+		teammate := filepath.Join(root, "teammate")
+		git(t, root, "clone", "--quiet", remote, teammate)
+		git(t, teammate, "checkout", "--quiet", "-b", "review", head)
+		git(t, teammate, "fetch", "--quiet", "origin", NotesRef+":"+NotesRef)
+
+		// Looked up in the note on HEAD, without importing every note first
+		assertSharedHit(t, newNotesManager(t, teammate))
+	})
+}
+
+// assertSharedHit checks that the manager finds the developer's analysis
+func assertSharedHit(t *testing.T, manager *Manager) {
+	t.Helper()
+
+	analysis, found, err := manager.GetAnalysis()
+	if err != nil {
+		t.Fatalf("looking up analysis: %v", err)
+	}
+	if !found {
+		t.Fatal("the shared analysis was not found")
+	}
+	if analysis.Title != "introduce-store" || analysis.Branch != "feature" {
+		t.Errorf("got analysis %q from branch %q, want introduce-store from feature", analysis.Title, analysis.Branch)
+	}
+}
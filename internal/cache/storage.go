@@ -2,9 +2,11 @@ package cache
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
 )
 
@@ -14,7 +16,11 @@ const (
 	DefaultCacheDir  = ".drduck/cache"
 )
 
-// Storage handles reading and writing cache data to disk
+// errUnchanged tells update that the cache was not modified and need not be written
+var errUnchanged = errors.New("cache unchanged")
+
+// Storage is the JSON file cache backend. The whole cache is read on every
+// lookup and rewritten on every change, so it suits small caches.
 type Storage struct {
 	cacheDir  string
 	cacheFile string
@@ -33,40 +39,93 @@ func NewStorage(workingDir string, config CacheConfig) *Storage {
 	}
 }
 
-// Load reads the cache from disk, creating empty cache if file doesn't exist
+// Load reads the cache from disk, creating empty cache if file doesn't exist.
+// Writes replace the file atomically, so Load does not need the lock. A
+// corrupted file reads as an empty cache; the next write moves it aside.
 func (s *Storage) Load() (*Cache, error) {
+	cache, _, err := s.read()
+	return cache, err
+}
+
+// read loads the cache like Load and also returns the parse error of a
+// corrupted cache file
+func (s *Storage) read() (*Cache, error, error) {
 	// Ensure cache directory exists
 	if err := os.MkdirAll(s.cacheDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+		return nil, nil, fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
 	// If cache file doesn't exist, return empty cache
 	if _, err := os.Stat(s.cacheFile); os.IsNotExist(err) {
-		return s.createEmptyCache(), nil
+		return s.createEmptyCache(), nil, nil
 	}
 
 	// Read and parse cache file
 	data, err := os.ReadFile(s.cacheFile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read cache file: %w", err)
+		return nil, nil, fmt.Errorf("failed to read cache file: %w", err)
 	}
 
 	var cache Cache
 	if err := json.Unmarshal(data, &cache); err != nil {
-		// If cache is corrupted, start fresh
-		return s.createEmptyCache(), nil
+		return s.createEmptyCache(), err, nil
 	}
 
 	// Migrate cache version if needed
 	if cache.Version != CacheVersion {
-		return s.migrateCache(&cache)
+		migrated, err := s.migrateCache(&cache)
+		return migrated, nil, err
 	}
 
-	return &cache, nil
+	if cache.Entries == nil {
+		cache.Entries = make(map[string]CacheEntry)
+	}
+
+	return &cache, nil, nil
 }
 
-// Save writes the cache to disk
+// Save writes the cache to disk while holding the cache lock
 func (s *Storage) Save(cache *Cache) error {
+	lock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer lock.unlock()
+
+	return s.write(cache)
+}
+
+// update runs a read-modify-write cycle on the cache while holding the cache
+// lock, so concurrent DrDuck processes cannot lose each other's changes.
+// Returning errUnchanged from modify skips the write.
+func (s *Storage) update(modify func(cache *Cache) error) error {
+	lock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer lock.unlock()
+
+	cache, parseErr, err := s.read()
+	if err != nil {
+		return err
+	}
+	if parseErr != nil {
+		// Only moved aside under the lock, so no writer can replace it meanwhile
+		s.backupCorruptFile(parseErr)
+	}
+
+	if err := modify(cache); err != nil {
+		if errors.Is(err, errUnchanged) {
+			return nil
+		}
+		return err
+	}
+
+	return s.write(cache)
+}
+
+// write atomically replaces the cache file
+func (s *Storage) write(cache *Cache) error {
 	// Update metadata
 	cache.Metadata.TotalSize = len(cache.Entries)
 	
@@ -81,14 +140,48 @@ func (s *Storage) Save(cache *Cache) error {
 		return fmt.Errorf("failed to marshal cache: %w", err)
 	}
 
-	// Write to file
-	if err := os.WriteFile(s.cacheFile, data, 0644); err != nil {
+	return writeFileAtomic(s.cacheDir, s.cacheFile, data)
+}
+
+// writeFileAtomic replaces path with data: the data goes to a temporary file
+// in dir, which is then renamed over path, so readers never see a partial file
+func writeFileAtomic(dir, path string, data []byte) error {
+	tmpFile, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary cache file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath) // No-op once the rename succeeded
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
 		return fmt.Errorf("failed to write cache file: %w", err)
 	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to sync cache file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close cache file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace cache file: %w", err)
+	}
 
 	return nil
 }
 
+// backupCorruptFile moves an unreadable cache file aside and warns about it
+func (s *Storage) backupCorruptFile(parseErr error) {
+	backupPath := fmt.Sprintf("%s.corrupt-%s", s.cacheFile, time.Now().Format("20060102-150405"))
+	if err := os.Rename(s.cacheFile, backupPath); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Analysis cache is corrupted (%v) and could not be backed up: %v\n", parseErr, err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "⚠️  Analysis cache was corrupted (%v); moved it to %s and started a fresh cache\n", parseErr, backupPath)
+}
+
 // Get retrieves an analysis result by content hash
 func (s *Storage) Get(contentHash string) (*AnalysisResult, bool) {
 	cache, err := s.Load()
@@ -103,9 +196,14 @@ func (s *Storage) Get(contentHash string) (*AnalysisResult, bool) {
 
 	// Check if entry is expired
 	if s.isExpired(entry.Analysis) {
-		// Remove expired entry
-		delete(cache.Entries, contentHash)
-		s.Save(cache) // Best effort save
+		// Remove expired entry, best effort. Unresolved "ADR needed" decisions
+		// are kept as decision debt, they are just no longer a cache hit.
+		if !entry.Analysis.IsDebt() {
+			s.update(func(cache *Cache) error {
+				delete(cache.Entries, contentHash)
+				return nil
+			})
+		}
 		return nil, false
 	}
 
@@ -114,89 +212,162 @@ func (s *Storage) Get(contentHash string) (*AnalysisResult, bool) {
 
 // Put stores an analysis result with the given content hash
 func (s *Storage) Put(contentHash string, analysis *AnalysisResult) error {
+	return s.update(func(cache *Cache) error {
+		// Create cache entry
+		entry := CacheEntry{
+			ContentHash: contentHash,
+			Analysis:    analysis,
+		}
+
+		cache.Entries[contentHash] = entry
+
+		// Clean up old entries if needed
+		s.cleanupOldEntries(cache)
+		return nil
+	})
+}
+
+// GetSummaries retrieves the unexpired summaries for the given diff hashes
+func (s *Storage) GetSummaries(hashes []string) map[string]string {
+	summaries := make(map[string]string)
 	cache, err := s.Load()
 	if err != nil {
-		return err
+		return summaries
 	}
 
-	// Create cache entry
-	entry := CacheEntry{
-		ContentHash: contentHash,
-		Analysis:    analysis,
+	for _, hash := range hashes {
+		summary, exists := cache.Summaries[hash]
+		if exists && !s.isExpiredAt(summary.Timestamp) {
+			summaries[hash] = summary.Summary
+		}
 	}
 
-	cache.Entries[contentHash] = entry
+	return summaries
+}
 
-	// Clean up old entries if needed
-	if len(cache.Entries) > s.config.MaxEntries {
-		s.cleanupOldEntries(cache)
+// PutSummaries stores chunk summaries keyed by chunk hash
+func (s *Storage) PutSummaries(summaries map[string]string) error {
+	return s.update(func(cache *Cache) error {
+		if cache.Summaries == nil {
+			cache.Summaries = make(map[string]ChunkSummary)
+		}
+		now := time.Now()
+		for chunkHash, summary := range summaries {
+			cache.Summaries[chunkHash] = ChunkSummary{Summary: summary, Timestamp: now}
+		}
+		return nil
+	})
+}
+
+// RecordFileLookups adds per-file summary cache hits and misses to the metadata
+func (s *Storage) RecordFileLookups(hits, misses int) error {
+	if hits == 0 && misses == 0 {
+		return nil
 	}
 
-	return s.Save(cache)
+	return s.update(func(cache *Cache) error {
+		cache.Metadata.FileHits += hits
+		cache.Metadata.FileMisses += misses
+		return nil
+	})
 }
 
 // MarkResolved marks an analysis as resolved with the given ADR ID
 func (s *Storage) MarkResolved(contentHash string, adrID int) error {
-	cache, err := s.Load()
-	if err != nil {
-		return err
-	}
-
-	entry, exists := cache.Entries[contentHash]
-	if !exists {
-		return fmt.Errorf("cache entry not found for hash: %s", contentHash)
-	}
-
-	entry.Analysis.Resolved = true
-	entry.Analysis.ResolvedADRID = adrID
-	cache.Entries[contentHash] = entry
+	return s.update(func(cache *Cache) error {
+		entry, exists := cache.Entries[contentHash]
+		if !exists {
+			return fmt.Errorf("cache entry not found for hash: %s", contentHash)
+		}
 
-	return s.Save(cache)
+		entry.Analysis.Resolved = true
+		entry.Analysis.ResolvedADRID = adrID
+		cache.Entries[contentHash] = entry
+		return nil
+	})
 }
 
-// Cleanup removes expired and resolved entries from cache
+// Cleanup removes resolved entries and expired entries that are not decision debt
 func (s *Storage) Cleanup() error {
-	cache, err := s.Load()
-	if err != nil {
-		return err
-	}
-
-	originalSize := len(cache.Entries)
-	now := time.Now()
+	return s.update(func(cache *Cache) error {
+		originalSize := len(cache.Entries) + len(cache.Summaries)
+		now := time.Now()
+
+		// Remove expired or resolved entries
+		for hash, entry := range cache.Entries {
+			// Unresolved "ADR needed" decisions are kept as decision debt
+			if (s.isExpired(entry.Analysis) && !entry.Analysis.IsDebt()) || entry.Analysis.Resolved {
+				delete(cache.Entries, hash)
+			}
+		}
 
-	// Remove expired or resolved entries
-	for hash, entry := range cache.Entries {
-		if s.isExpired(entry.Analysis) || entry.Analysis.Resolved {
-			delete(cache.Entries, hash)
+		// Remove expired chunk summaries
+		for hash, summary := range cache.Summaries {
+			if s.isExpiredAt(summary.Timestamp) {
+				delete(cache.Summaries, hash)
+			}
 		}
-	}
 
-	// Update cleanup timestamp
-	cache.Metadata.LastCleanup = now
+		// Update cleanup timestamp
+		cache.Metadata.LastCleanup = now
 
-	// Only save if something was cleaned up
-	if len(cache.Entries) < originalSize {
-		return s.Save(cache)
+		// Only save if something was cleaned up
+		if len(cache.Entries)+len(cache.Summaries) == originalSize {
+			return errUnchanged
+		}
+		return nil
+	})
+}
+
+// Delete removes the entries with the given content hashes
+func (s *Storage) Delete(contentHashes []string) error {
+	if len(contentHashes) == 0 {
+		return nil
 	}
 
-	return nil
+	return s.update(func(cache *Cache) error {
+		for _, hash := range contentHashes {
+			delete(cache.Entries, hash)
+		}
+		return nil
+	})
 }
 
-// GetUnresolvedAnalyses returns all unresolved analyses
-func (s *Storage) GetUnresolvedAnalyses() (map[string]*AnalysisResult, error) {
+// Query returns the unexpired entries matching the query, newest first
+func (s *Storage) Query(query Query) ([]CacheEntry, error) {
 	cache, err := s.Load()
 	if err != nil {
 		return nil, err
 	}
 
-	unresolved := make(map[string]*AnalysisResult)
-	for hash, entry := range cache.Entries {
-		if !entry.Analysis.Resolved && !s.isExpired(entry.Analysis) {
-			unresolved[hash] = entry.Analysis
+	var entries []CacheEntry
+	for _, entry := range cache.Entries {
+		if query.Matches(entry) && (query.IncludeExpired || !s.isExpired(entry.Analysis)) {
+			entries = append(entries, entry)
 		}
 	}
+	sortNewestFirst(entries)
 
-	return unresolved, nil
+	return entries, nil
+}
+
+// Stats returns entry counts and metadata of the JSON cache
+func (s *Storage) Stats() (*Stats, error) {
+	cache, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, unresolved := countResolved(cache.Entries)
+	return &Stats{
+		Backend:    BackendJSON,
+		Version:    cache.Version,
+		Entries:    len(cache.Entries),
+		Resolved:   resolved,
+		Unresolved: unresolved,
+		Summaries:  len(cache.Summaries),
+		Metadata:   cache.Metadata,
+	}, nil
 }
 
 // createEmptyCache creates a new empty cache with default metadata
@@ -221,16 +392,18 @@ func (s *Storage) migrateCache(cache *Cache) (*Cache, error) {
 
 // isExpired checks if a cache entry is expired based on config
 func (s *Storage) isExpired(analysis *AnalysisResult) bool {
+	return s.isExpiredAt(analysis.Timestamp)
+}
+
+// isExpiredAt checks if something created at the given time is past the max age
+func (s *Storage) isExpiredAt(timestamp time.Time) bool {
 	maxAge := time.Duration(s.config.MaxAge) * 24 * time.Hour
-	return time.Since(analysis.Timestamp) > maxAge
+	return time.Since(timestamp) > maxAge
 }
 
-// cleanupOldEntries removes oldest entries to stay within MaxEntries limit
+// cleanupOldEntries removes oldest entries to stay within MaxEntries limit.
+// Decision debt is neither counted nor removed.
 func (s *Storage) cleanupOldEntries(cache *Cache) {
-	if len(cache.Entries) <= s.config.MaxEntries {
-		return
-	}
-
 	// Convert to slice for sorting by timestamp
 	type entryWithHash struct {
 		hash  string
@@ -239,23 +412,23 @@ func (s *Storage) cleanupOldEntries(cache *Cache) {
 
 	entries := make([]entryWithHash, 0, len(cache.Entries))
 	for hash, entry := range cache.Entries {
-		entries = append(entries, entryWithHash{hash: hash, entry: entry})
+		if !entry.Analysis.IsDebt() {
+			entries = append(entries, entryWithHash{hash: hash, entry: entry})
+		}
+	}
+	excess := len(entries) - max(s.config.MaxEntries, 0)
+	if excess <= 0 {
+		return
 	}
 
 	// Sort by timestamp (oldest first)
-	// Simple bubble sort for now
-	for i := 0; i < len(entries)-1; i++ {
-		for j := 0; j < len(entries)-i-1; j++ {
-			if entries[j].entry.Analysis.Timestamp.After(entries[j+1].entry.Analysis.Timestamp) {
-				entries[j], entries[j+1] = entries[j+1], entries[j]
-			}
-		}
-	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].entry.Analysis.Timestamp.Before(entries[j].entry.Analysis.Timestamp)
+	})
 
 	// Remove oldest entries until we're within the limit
-	for len(cache.Entries) > s.config.MaxEntries && len(entries) > 0 {
-		delete(cache.Entries, entries[0].hash)
-		entries = entries[1:]
+	for _, old := range entries[:excess] {
+		delete(cache.Entries, old.hash)
 	}
 }
 
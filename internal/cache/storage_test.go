@@ -0,0 +1,200 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	concurrentWriters = 8
+	putsPerWriter     = 10
+)
+
+func testConfig() CacheConfig {
+	config := DefaultCacheConfig()
+	config.MaxEntries = concurrentWriters * putsPerWriter
+	return config
+}
+
+// putAndGet stores putsPerWriter analyses under keys unique to the writer,
+// reading each one back as the concurrent hooks would
+func putAndGet(storage Backend, writer int) error {
+	for i := 0; i < putsPerWriter; i++ {
+		key := fmt.Sprintf("writer-%d-entry-%d", writer, i)
+		analysis := &AnalysisResult{Decision: "no", Title: key, Timestamp: time.Now()}
+		if err := storage.Put(key, analysis); err != nil {
+			return fmt.Errorf("put %s: %w", key, err)
+		}
+		if _, found := storage.Get(key); !found {
+			return fmt.Errorf("get %s: entry missing right after put", key)
+		}
+	}
+	return nil
+}
+
+// assertAllEntries checks that the cache file is valid JSON holding every writer's entries
+func assertAllEntries(t *testing.T, dir string) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, DefaultCacheDir, CacheFileName))
+	if err != nil {
+		t.Fatalf("reading cache file: %v", err)
+	}
+	var cache Cache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		t.Fatalf("cache file is not valid JSON: %v", err)
+	}
+
+	for writer := 0; writer < concurrentWriters; writer++ {
+		for i := 0; i < putsPerWriter; i++ {
+			key := fmt.Sprintf("writer-%d-entry-%d", writer, i)
+			if _, exists := cache.Entries[key]; !exists {
+				t.Errorf("entry %s was lost", key)
+			}
+		}
+	}
+
+	corrupt, _ := filepath.Glob(filepath.Join(dir, DefaultCacheDir, CacheFileName+".corrupt-*"))
+	if len(corrupt) > 0 {
+		t.Errorf("cache was reported corrupt: %v", corrupt)
+	}
+}
+
+func TestStorageConcurrentGoroutines(t *testing.T) {
+	dir := t.TempDir()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrentWriters)
+	for writer := 0; writer < concurrentWriters; writer++ {
+		wg.Add(1)
+		go func(writer int) {
+			defer wg.Done()
+			// Separate Storage values, as separate hook processes would have
+			if err := putAndGet(NewStorage(dir, testConfig()), writer); err != nil {
+				errs <- err
+			}
+		}(writer)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+	assertAllEntries(t, dir)
+}
+
+func TestStorageConcurrentProcesses(t *testing.T) {
+	dir := t.TempDir()
+
+	cmds := make([]*exec.Cmd, concurrentWriters)
+	for writer := range cmds {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestStorageWriterProcess$")
+		cmd.Env = append(os.Environ(),
+			"DRDUCK_CACHE_TEST_DIR="+dir,
+			"DRDUCK_CACHE_TEST_WRITER="+strconv.Itoa(writer),
+		)
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("starting writer %d: %v", writer, err)
+		}
+		cmds[writer] = cmd
+	}
+
+	for writer, cmd := range cmds {
+		if err := cmd.Wait(); err != nil {
+			t.Errorf("writer %d failed: %v", writer, err)
+		}
+	}
+	assertAllEntries(t, dir)
+}
+
+// TestStorageWriterProcess is the body of one writer process started by
+// TestStorageConcurrentProcesses; it does nothing when run directly
+func TestStorageWriterProcess(t *testing.T) {
+	dir := os.Getenv("DRDUCK_CACHE_TEST_DIR")
+	if dir == "" {
+		t.Skip("only runs as a subprocess of TestStorageConcurrentProcesses")
+	}
+
+	writer, err := strconv.Atoi(os.Getenv("DRDUCK_CACHE_TEST_WRITER"))
+	if err != nil {
+		t.Fatalf("invalid writer number: %v", err)
+	}
+	if err := putAndGet(NewStorage(dir, testConfig()), writer); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStorageKeepsDecisionDebt(t *testing.T) {
+	config := testConfig()
+	config.MaxEntries = 2
+	storage := NewStorage(t.TempDir(), config)
+	expired := time.Now().AddDate(0, 0, -config.MaxAge-1)
+
+	storage.Put("expired-debt", &AnalysisResult{Decision: "yes", Timestamp: expired})
+	if _, found := storage.Get("expired-debt"); found {
+		t.Error("expired debt must not be returned as a cache hit")
+	}
+
+	// Debt is older than every other entry but must not be evicted by MaxEntries
+	storage.Put("old-debt", &AnalysisResult{Decision: "yes", Timestamp: time.Now().Add(-time.Hour)})
+	for i := 0; i < 4; i++ {
+		key := fmt.Sprintf("no-adr-%d", i)
+		storage.Put(key, &AnalysisResult{Decision: "no", Timestamp: time.Now().Add(time.Duration(i) * time.Minute)})
+	}
+
+	entries, err := storage.Query(Query{IncludeExpired: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept := make(map[string]bool)
+	for _, entry := range entries {
+		kept[entry.ContentHash] = true
+	}
+	for _, key := range []string{"expired-debt", "old-debt", "no-adr-2", "no-adr-3"} {
+		if !kept[key] {
+			t.Errorf("%s was removed", key)
+		}
+	}
+	if len(kept) != 4 {
+		t.Errorf("kept %v, want the debt and the two newest other entries", kept)
+	}
+}
+
+func TestStorageBacksUpCorruptFileOnWrite(t *testing.T) {
+	dir := t.TempDir()
+	storage := NewStorage(dir, testConfig())
+	cacheFile := filepath.Join(dir, DefaultCacheDir, CacheFileName)
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cacheFile, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Readers don't hold the lock, so they must leave the file alone
+	if _, found := storage.Get("key"); found {
+		t.Error("corrupted cache returned an entry")
+	}
+	if data, err := os.ReadFile(cacheFile); err != nil || string(data) != "{not json" {
+		t.Fatalf("read moved or changed the corrupted cache: %q, %v", data, err)
+	}
+
+	if err := storage.Put("key", &AnalysisResult{Decision: "no", Timestamp: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	corrupt, _ := filepath.Glob(cacheFile + ".corrupt-*")
+	if len(corrupt) != 1 {
+		t.Errorf("backups = %v, want the corrupted file moved aside once", corrupt)
+	}
+	if _, found := storage.Get("key"); !found {
+		t.Error("entry missing after writing over the corrupted cache")
+	}
+}
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -12,10 +13,27 @@ type AnalysisResult struct {
 	Timestamp       time.Time         `json:"timestamp"`        // When analysis was performed
 	ChangesAnalyzed []string          `json:"changes_analyzed"` // File paths that were analyzed
 	CommitRange     string            `json:"commit_range"`     // Git commit range analyzed
+	Branch          string            `json:"branch,omitempty"` // Branch the analysis ran on
+	HeadCommit      string            `json:"head_commit,omitempty"` // Commit that was HEAD when the analysis ran
+	Config          AnalysisConfig    `json:"analysis_config"`  // Provider, model, persona and prompt that produced it
+	Commits         []CoveredCommit   `json:"commits,omitempty"` // Commits in the analyzed range
 	Resolved        bool              `json:"resolved"`         // Whether an ADR was created for this
 	ResolvedADRID   int               `json:"resolved_adr_id"`  // ID of ADR that resolved this
 }
 
+// CoveredCommit is a commit included in an analyzed commit range
+type CoveredCommit struct {
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author"`
+	Date    time.Time `json:"date"`
+	Subject string    `json:"subject"`
+}
+
+// IsDebt reports whether the analysis asked for an ADR that was never written
+func (a *AnalysisResult) IsDebt() bool {
+	return a.Decision == "yes" && !a.Resolved
+}
+
 // CacheEntry represents a single cache entry keyed by content fingerprint
 type CacheEntry struct {
 	ContentHash string          `json:"content_hash"` // Hash of analyzed code changes (excluding ADRs)
@@ -24,9 +42,16 @@ type CacheEntry struct {
 
 // Cache represents the entire cache structure
 type Cache struct {
-	Version  string                `json:"version"`  // Cache format version
-	Entries  map[string]CacheEntry `json:"entries"`  // Content hash -> analysis result
-	Metadata CacheMetadata         `json:"metadata"` // Cache metadata
+	Version   string                  `json:"version"`             // Cache format version
+	Entries   map[string]CacheEntry   `json:"entries"`             // Content hash -> analysis result
+	Summaries map[string]ChunkSummary `json:"summaries,omitempty"` // File or chunk diff hash -> summary
+	Metadata  CacheMetadata           `json:"metadata"`            // Cache metadata
+}
+
+// ChunkSummary is a cached AI summary of one file or one chunk of a large change set
+type ChunkSummary struct {
+	Summary   string    `json:"summary"`   // AI summary of the chunk
+	Timestamp time.Time `json:"timestamp"` // When the summary was generated
 }
 
 // CacheMetadata contains cache management information
@@ -34,6 +59,35 @@ type CacheMetadata struct {
 	LastCleanup time.Time `json:"last_cleanup"` // When cache was last cleaned up
 	TotalSize   int       `json:"total_size"`   // Number of entries
 	MaxAge      int       `json:"max_age_days"` // Maximum age in days before cleanup
+	FileHits    int       `json:"file_hits"`    // Files whose cached summary was reused
+	FileMisses  int       `json:"file_misses"`  // Files that had to be sent to the AI provider
+}
+
+// AnalysisConfig identifies what produced an analysis. It is part of the
+// fingerprint, so switching provider or model, or editing the persona or
+// prompts, does not return answers produced under the old configuration.
+type AnalysisConfig struct {
+	Provider   string `json:"provider"`              // AI provider, e.g. claude-code, cursor or rules
+	Model      string `json:"model,omitempty"`       // Model, empty for the provider default
+	Persona    string `json:"persona"`               // Configured persona name
+	PromptHash string `json:"prompt_hash,omitempty"` // Hash of the prompt texts (and rules for the rules provider)
+}
+
+// String describes the configuration for display
+func (c AnalysisConfig) String() string {
+	if c.Provider == "" {
+		return "unknown (analyzed before configurations were tracked)"
+	}
+
+	model := c.Model
+	if model == "" {
+		model = "default"
+	}
+	promptHash := c.PromptHash
+	if len(promptHash) > 8 {
+		promptHash = promptHash[:8]
+	}
+	return fmt.Sprintf("provider=%s model=%s persona=%s prompt=%s", c.Provider, model, c.Persona, promptHash)
 }
 
 // ChangeFingerprint represents the components used to generate a content hash
@@ -41,6 +95,8 @@ type ChangeFingerprint struct {
 	Files       map[string]string `json:"files"`        // filepath -> file content hash
 	CommitRange string            `json:"commit_range"` // git commit range
 	Branch      string            `json:"branch"`       // current branch
+	HeadCommit  string            `json:"head_commit"`  // commit at HEAD, not part of the hash
+	Analysis    AnalysisConfig    `json:"analysis"`     // analysis configuration
 	Timestamp   time.Time         `json:"timestamp"`    // when fingerprint was created
 }
 
@@ -52,6 +108,8 @@ type CacheConfig struct {
 	IncludeFiles  []string `json:"include_files"`   // File patterns to include (empty = all)
 	ADRDirs       []string `json:"adr_dirs"`        // ADR directories to exclude from analysis
 	CleanupAfter  int      `json:"cleanup_after"`   // Days between automatic cleanup
+	Backend       string   `json:"backend"`         // Storage backend: "json" or "embedded"
+	GitNotes      bool     `json:"git_notes"`       // Share analyses through git notes
 }
 
 // DefaultCacheConfig returns sensible defaults for cache configuration
@@ -77,5 +135,6 @@ func DefaultCacheConfig() CacheConfig {
 			"adr",
 		},
 		CleanupAfter: 1, // Clean up daily
+		Backend:      BackendJSON,
 	}
 }
\ No newline at end of file
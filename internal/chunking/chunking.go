@@ -0,0 +1,288 @@
+package chunking
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/SilverFlin/DrDuck/internal/ai"
+	"github.com/SilverFlin/DrDuck/internal/diff"
+	"github.com/SilverFlin/DrDuck/internal/prompts/templates"
+)
+
+// Chunk is a group of files from one or more directories that is summarized in a single prompt
+type Chunk struct {
+	Name  string
+	Files []*diff.File
+	Diff  string
+}
+
+// ChunkResult is the summary of one chunk
+type ChunkResult struct {
+	Chunk   Chunk
+	Summary string
+	Cached  bool
+	Err     error
+}
+
+// SummaryCache stores chunk summaries between runs
+type SummaryCache interface {
+	GetChunkSummary(chunkHash string) (string, bool)
+	StoreChunkSummaries(summaries map[string]string) error
+}
+
+// Analyzer runs a map-reduce analysis: every chunk is summarized on its own,
+// then the ADR decision is made from the summaries
+type Analyzer struct {
+	aiManager    *ai.Manager
+	cache        SummaryCache
+	maxChunkSize int
+	concurrency  int
+}
+
+// NewAnalyzer creates a chunked analyzer. cache may be nil to disable summary caching.
+func NewAnalyzer(aiManager *ai.Manager, cache SummaryCache, maxChunkSize, concurrency int) *Analyzer {
+	if maxChunkSize <= 0 {
+		maxChunkSize = 20000
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &Analyzer{
+		aiManager:    aiManager,
+		cache:        cache,
+		maxChunkSize: maxChunkSize,
+		concurrency:  concurrency,
+	}
+}
+
+// NeedsChunking reports whether the diff is too large to analyze in one prompt
+func (a *Analyzer) NeedsChunking(changes string) bool {
+	return len(changes) > a.maxChunkSize
+}
+
+// Analyze summarizes every chunk of the diff, then asks for the ADR decision based on the summaries
+func (a *Analyzer) Analyze(files []*diff.File, recentCommits string, sections ...templates.AnalysisSection) (ai.AnalyzeResult, error) {
+	chunks := BuildChunks(files, a.maxChunkSize)
+	if len(chunks) == 0 {
+		return ai.AnalyzeResult{}, fmt.Errorf("no changes to analyze")
+	}
+
+	results, usage := a.Summarize(chunks)
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if failed == len(results) {
+		return ai.AnalyzeResult{}, fmt.Errorf("failed to summarize any chunk: %w", results[0].Err)
+	}
+
+	prompt := templates.ChunkedChangeAnalysisPrompt("", RenderSummaries(results), recentCommits, sections...)
+	final, err := a.aiManager.AnalyzeChangesWithTokens(prompt)
+	if err != nil {
+		return ai.AnalyzeResult{}, err
+	}
+
+	usage.InputTokens += final.TokenUsage.InputTokens
+	usage.OutputTokens += final.TokenUsage.OutputTokens
+	usage.TotalTokens += final.TokenUsage.TotalTokens
+	return ai.AnalyzeResult{Response: final.Response, TokenUsage: usage}, nil
+}
+
+// Summarize summarizes chunks in parallel, up to the concurrency limit, reusing
+// cached summaries. Results are returned in chunk order.
+func (a *Analyzer) Summarize(chunks []Chunk) ([]ChunkResult, ai.TokenUsage) {
+	results := make([]ChunkResult, len(chunks))
+	usages := make([]ai.TokenUsage, len(chunks))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, a.concurrency)
+
+	for i, chunk := range chunks {
+		results[i].Chunk = chunk
+
+		if a.cache != nil {
+			if summary, found := a.cache.GetChunkSummary(a.summaryKey(chunk)); found {
+				results[i].Summary = summary
+				results[i].Cached = true
+				continue
+			}
+		}
+
+		wg.Add(1)
+		go func(i int, chunk Chunk) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			result, err := a.aiManager.AnalyzeChangesWithTokens(templates.ChunkSummaryPrompt(chunk.Name, chunk.Diff))
+			if err != nil {
+				results[i].Err = fmt.Errorf("failed to summarize %s: %w", chunk.Name, err)
+				return
+			}
+			results[i].Summary = strings.TrimSpace(result.Response)
+			usages[i] = result.TokenUsage
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	// Store new summaries in one write, after all workers are done
+	var total ai.TokenUsage
+	fresh := make(map[string]string)
+	for i, result := range results {
+		total.InputTokens += usages[i].InputTokens
+		total.OutputTokens += usages[i].OutputTokens
+		total.TotalTokens += usages[i].TotalTokens
+		if !result.Cached && result.Err == nil {
+			fresh[a.summaryKey(result.Chunk)] = result.Summary
+		}
+	}
+	if a.cache != nil {
+		_ = a.cache.StoreChunkSummaries(fresh) // Caching is best effort
+	}
+
+	return results, total
+}
+
+// summaryKey identifies a chunk summary in the cache. Besides the chunk's diff
+// it covers the chunk size, which decides how files are grouped, and the prompt
+// version, so changing either does not reuse stale summaries.
+func (a *Analyzer) summaryKey(chunk Chunk) string {
+	hasher := sha256.New()
+	fmt.Fprintf(hasher, "%d\n%s\n", a.maxChunkSize, templates.PromptVersion())
+	hasher.Write([]byte(chunk.Diff))
+	return fmt.Sprintf("%x", hasher.Sum(nil))
+}
+
+// RenderSummaries formats chunk summaries as markdown sections. Chunks that
+// could not be summarized are listed with their file statistics instead.
+func RenderSummaries(results []ChunkResult) string {
+	var builder strings.Builder
+	for _, result := range results {
+		builder.WriteString(fmt.Sprintf("### %s\n", result.Chunk.Name))
+		if result.Err != nil {
+			builder.WriteString("(summary unavailable, files changed:)\n")
+			for _, file := range result.Chunk.Files {
+				added, removed := file.Stats()
+				builder.WriteString(fmt.Sprintf("- %s (%s, +%d -%d)\n", file.Path(), file.Status, added, removed))
+			}
+		} else {
+			builder.WriteString(result.Summary)
+			builder.WriteString("\n")
+		}
+		builder.WriteString("\n")
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+// BuildChunks groups files by directory and packs the groups into chunks of at
+// most maxSize characters. Small directories share a chunk, large ones are
+// split by file, and a single file larger than maxSize is truncated.
+func BuildChunks(files []*diff.File, maxSize int) []Chunk {
+	groups := make(map[string][]*diff.File)
+	var dirs []string
+	for _, file := range files {
+		dir := path.Dir(file.Path())
+		if _, exists := groups[dir]; !exists {
+			dirs = append(dirs, dir)
+		}
+		groups[dir] = append(groups[dir], file)
+	}
+	// Sorted so the same change set always yields the same chunks (and cache hits)
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		group := groups[dir]
+		sort.SliceStable(group, func(i, j int) bool { return group[i].Path() < group[j].Path() })
+	}
+
+	var chunks []Chunk
+	var current chunkBuilder
+
+	flush := func() {
+		if len(current.files) > 0 {
+			chunks = append(chunks, current.build())
+		}
+		current = chunkBuilder{}
+	}
+
+	for _, dir := range dirs {
+		groupSize := 0
+		for _, file := range groups[dir] {
+			groupSize += len(file.Raw) + 1
+		}
+
+		// Whole directory fits into the current chunk
+		if current.size+groupSize <= maxSize {
+			current.add(dir, groups[dir]...)
+			continue
+		}
+
+		flush()
+		if groupSize <= maxSize {
+			current.add(dir, groups[dir]...)
+			continue
+		}
+
+		// Directory too large for one chunk: split by file
+		part := 1
+		for _, file := range groups[dir] {
+			if current.size > 0 && current.size+len(file.Raw)+1 > maxSize {
+				current.name = fmt.Sprintf("%s (part %d)", dir, part)
+				flush()
+				part++
+			}
+			current.add(dir, truncateFile(file, maxSize))
+		}
+		if part > 1 {
+			current.name = fmt.Sprintf("%s (part %d)", dir, part)
+		}
+		flush()
+	}
+	flush()
+
+	return chunks
+}
+
+// chunkBuilder accumulates files for one chunk
+type chunkBuilder struct {
+	name  string
+	dirs  []string
+	files []*diff.File
+	size  int
+}
+
+// add appends files from a directory to the chunk
+func (b *chunkBuilder) add(dir string, files ...*diff.File) {
+	if len(b.dirs) == 0 || b.dirs[len(b.dirs)-1] != dir {
+		b.dirs = append(b.dirs, dir)
+	}
+	for _, file := range files {
+		b.files = append(b.files, file)
+		b.size += len(file.Raw) + 1
+	}
+}
+
+// build turns the accumulated files into a chunk
+func (b *chunkBuilder) build() Chunk {
+	name := b.name
+	if name == "" {
+		name = strings.Join(b.dirs, ", ")
+	}
+	return Chunk{Name: name, Files: b.files, Diff: diff.Render(b.files)}
+}
+
+// truncateFile shortens a single file diff that does not fit into any chunk
+func truncateFile(file *diff.File, maxSize int) *diff.File {
+	if len(file.Raw) <= maxSize {
+		return file
+	}
+	truncated := *file
+	truncated.Raw = file.Raw[:maxSize] + "\n[... file diff truncated ...]"
+	return &truncated
+}
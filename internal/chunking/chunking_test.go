@@ -0,0 +1,187 @@
+package chunking
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/SilverFlin/DrDuck/internal/diff"
+)
+
+// fileDiff returns the diff of a modified file that adds the given number of lines
+func fileDiff(filePath string, lines int) *diff.File {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n@@ -1,0 +1,%d @@\n", filePath, filePath, filePath, filePath, lines)
+	for i := 0; i < lines; i++ {
+		fmt.Fprintf(&builder, "+line %d of %s\n", i, filePath)
+	}
+	return diff.Parse(builder.String())[0]
+}
+
+func chunkNames(chunks []Chunk) []string {
+	var names []string
+	for _, chunk := range chunks {
+		names = append(names, chunk.Name)
+	}
+	return names
+}
+
+func TestBuildChunks(t *testing.T) {
+	small := []*diff.File{
+		fileDiff("cmd/root.go", 2),
+		fileDiff("internal/adr/adr.go", 2),
+		fileDiff("cmd/serve.go", 2),
+	}
+	chunks := BuildChunks(small, 10000)
+	if got := chunkNames(chunks); !reflect.DeepEqual(got, []string{"cmd, internal/adr"}) {
+		t.Fatalf("chunks = %v, want one chunk with both directories", got)
+	}
+	if len(chunks[0].Files) != 3 || !strings.Contains(chunks[0].Diff, "cmd/serve.go") {
+		t.Errorf("chunk is missing files: %v", diff.Paths(chunks[0].Files))
+	}
+
+	size := len(fileDiff("pkg/b/one.go", 5).Raw) + 1
+	files := []*diff.File{
+		fileDiff("pkg/a/a.go", 5),
+		fileDiff("pkg/b/one.go", 5),
+		fileDiff("pkg/b/two.go", 5),
+		fileDiff("pkg/b/six.go", 5),
+		fileDiff("pkg/c/c.go", 5),
+	}
+	chunks = BuildChunks(files, 2*size+10)
+
+	want := []string{"pkg/a", "pkg/b (part 1)", "pkg/b (part 2)", "pkg/c"}
+	if got := chunkNames(chunks); !reflect.DeepEqual(got, want) {
+		t.Fatalf("chunks = %v, want %v", got, want)
+	}
+	for _, chunk := range chunks {
+		if len(chunk.Diff) > 2*size+10 {
+			t.Errorf("chunk %s is %d characters, over the limit", chunk.Name, len(chunk.Diff))
+		}
+	}
+	// Files within a directory are in path order
+	if got := diff.Paths(chunks[1].Files); !reflect.DeepEqual(got, []string{"pkg/b/one.go", "pkg/b/six.go"}) {
+		t.Errorf("first part = %v", got)
+	}
+}
+
+func TestBuildChunksTruncatesOversizedFile(t *testing.T) {
+	huge := fileDiff("pkg/big/generated.go", 500)
+	files := []*diff.File{fileDiff("pkg/big/small.go", 2), huge}
+
+	chunks := BuildChunks(files, 1000)
+	if got := chunkNames(chunks); !reflect.DeepEqual(got, []string{"pkg/big (part 1)", "pkg/big (part 2)"}) {
+		t.Fatalf("chunks = %v", got)
+	}
+
+	truncated := chunks[0].Files[0]
+	if !strings.HasSuffix(truncated.Raw, "[... file diff truncated ...]") || len(truncated.Raw) > 1000+len("\n[... file diff truncated ...]") {
+		t.Errorf("oversized file was not truncated: %d characters", len(truncated.Raw))
+	}
+	if len(huge.Raw) <= 1000 || truncated == huge {
+		t.Error("the original file diff was modified")
+	}
+
+	// A single oversized file still gets its own chunk
+	if chunks := BuildChunks([]*diff.File{huge}, 1000); len(chunks) != 1 || chunks[0].Name != "pkg/big" {
+		t.Errorf("chunks = %v, want one pkg/big chunk", chunkNames(chunks))
+	}
+}
+
+func TestBuildChunksIsStable(t *testing.T) {
+	files := []*diff.File{
+		fileDiff("web/app.js", 20),
+		fileDiff("cmd/root.go", 20),
+		fileDiff("internal/adr/adr.go", 20),
+		fileDiff("cmd/serve.go", 20),
+	}
+	reordered := []*diff.File{files[2], files[3], files[0], files[1]}
+
+	analyzer := NewAnalyzer(nil, nil, 1500, 1)
+	first, second := BuildChunks(files, 1500), BuildChunks(reordered, 1500)
+	if !reflect.DeepEqual(chunkNames(first), chunkNames(second)) {
+		t.Fatalf("chunk names depend on file order: %v vs %v", chunkNames(first), chunkNames(second))
+	}
+	for i := range first {
+		if analyzer.summaryKey(first[i]) != analyzer.summaryKey(second[i]) {
+			t.Errorf("hash of %s depends on file order", first[i].Name)
+		}
+	}
+
+	// The key covers the chunk size, and the diff itself
+	if NewAnalyzer(nil, nil, 3000, 1).summaryKey(first[0]) == analyzer.summaryKey(first[0]) {
+		t.Error("chunk size is not part of the summary key")
+	}
+	if analyzer.summaryKey(first[0]) == analyzer.summaryKey(first[1]) {
+		t.Error("different chunks share a summary key")
+	}
+}
+
+// memoryCache is a SummaryCache backed by a map
+type memoryCache struct {
+	summaries map[string]string
+	stored    int
+}
+
+func (c *memoryCache) GetChunkSummary(chunkHash string) (string, bool) {
+	summary, found := c.summaries[chunkHash]
+	return summary, found
+}
+
+func (c *memoryCache) StoreChunkSummaries(summaries map[string]string) error {
+	for key, summary := range summaries {
+		c.summaries[key] = summary
+	}
+	c.stored += len(summaries)
+	return nil
+}
+
+func TestSummarizeUsesCachedSummaries(t *testing.T) {
+	limit := len(fileDiff("cmd/root.go", 3).Raw) + 10
+	chunks := BuildChunks([]*diff.File{fileDiff("cmd/root.go", 3), fileDiff("pkg/a/a.go", 3)}, limit)
+	if len(chunks) != 2 {
+		t.Fatalf("chunks = %v, want 2", chunkNames(chunks))
+	}
+
+	cache := &memoryCache{summaries: make(map[string]string)}
+	analyzer := NewAnalyzer(nil, cache, limit, 2)
+	for _, chunk := range chunks {
+		cache.summaries[analyzer.summaryKey(chunk)] = "summary of " + chunk.Name
+	}
+
+	// Every chunk is cached, so the AI provider is never called
+	results, usage := analyzer.Summarize(chunks)
+	for i, result := range results {
+		if !result.Cached || result.Summary != "summary of "+chunks[i].Name {
+			t.Errorf("result %d = %+v", i, result)
+		}
+	}
+	if usage.TotalTokens != 0 || cache.stored != 0 {
+		t.Errorf("usage %+v, stored %d, want nothing", usage, cache.stored)
+	}
+}
+
+func TestRenderSummaries(t *testing.T) {
+	failed := Chunk{Name: "pkg/a", Files: []*diff.File{fileDiff("pkg/a/a.go", 3)}}
+	results := []ChunkResult{
+		{Chunk: Chunk{Name: "cmd"}, Summary: "Adds a serve command."},
+		{Chunk: failed, Err: errors.New("timeout")},
+	}
+
+	want := "### cmd\nAdds a serve command.\n\n### pkg/a\n(summary unavailable, files changed:)\n- pkg/a/a.go (modified, +3 -0)"
+	if got := RenderSummaries(results); got != want {
+		t.Errorf("RenderSummaries() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestNeedsChunking(t *testing.T) {
+	analyzer := NewAnalyzer(nil, nil, 0, 0)
+	if analyzer.maxChunkSize != 20000 || analyzer.concurrency != 1 {
+		t.Errorf("defaults = %d, %d", analyzer.maxChunkSize, analyzer.concurrency)
+	}
+	if analyzer.NeedsChunking(strings.Repeat("x", 20000)) || !analyzer.NeedsChunking(strings.Repeat("x", 20001)) {
+		t.Error("NeedsChunking does not compare against the chunk size")
+	}
+}
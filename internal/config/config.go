@@ -17,19 +17,33 @@ type Config struct {
 	SeparateRepoURL string       `yaml:"separate_repo_url,omitempty"`
 	AISettings      AISettings   `yaml:"ai_settings"`
 	Cache           CacheConfig  `yaml:"cache"`
+	Rules           []RuleConfig `yaml:"rules,omitempty"`
+	Refs            RefsConfig   `yaml:"refs"`
+	Similarity      SimilarityConfig `yaml:"similarity"`
 }
 
 type HooksConfig struct {
 	PreCommit bool `yaml:"pre_commit"`
 	PrePush   bool `yaml:"pre_push"`
+	CommitMsg bool `yaml:"commit_msg"` // Suggest and validate "ADR: 0007" commit trailers
 }
 
 type AISettings struct {
 	Persona           string   `yaml:"persona"`
+	Model             string   `yaml:"model,omitempty"` // Provider model, empty for the provider default
 	Sensitivity       string   `yaml:"sensitivity"`
 	IgnorePatterns    []string `yaml:"ignore_patterns,omitempty"`
 	RequireADRFor     []string `yaml:"require_adr_for,omitempty"`
 	NeverRequireADRFor []string `yaml:"never_require_adr_for,omitempty"`
+	Chunking          ChunkingSettings `yaml:"chunking"`
+	CheckDrift        bool     `yaml:"check_drift"` // Ask the AI whether changes to governed paths contradict the ADR
+}
+
+// ChunkingSettings controls map-reduce analysis of change sets too large for a single prompt
+type ChunkingSettings struct {
+	Enabled        bool `yaml:"enabled"`
+	MaxChunkSize   int  `yaml:"max_chunk_size"`  // Characters of diff summarized per chunk
+	MaxConcurrency int  `yaml:"max_concurrency"` // Chunks summarized in parallel
 }
 
 type CacheConfig struct {
@@ -39,6 +53,29 @@ type CacheConfig struct {
 	IncludeFiles []string `yaml:"include_files"`   // File patterns to include (empty = all)
 	ADRDirs      []string `yaml:"adr_dirs"`        // ADR directories to exclude from analysis
 	CleanupAfter int      `yaml:"cleanup_after"`   // Days between automatic cleanup
+	Backend      string   `yaml:"backend"`         // Storage backend: "json" or "embedded"
+	GitNotes     bool     `yaml:"git_notes"`       // Also read and write analyses as git notes (refs/notes/drduck)
+}
+
+// RefsConfig controls the scan for ADR references in code comments
+type RefsConfig struct {
+	Exclude []string `yaml:"exclude,omitempty"` // Glob patterns of files not scanned
+}
+
+// SimilarityConfig controls the search for existing ADRs similar to a new one
+type SimilarityConfig struct {
+	Method     string  `yaml:"method"`      // "bm25", the local index; the only method so far
+	Threshold  float64 `yaml:"threshold"`   // Minimum score between 0 and 1 for a match to be offered
+	MaxResults int     `yaml:"max_results"` // Matches offered before a new ADR is written
+}
+
+// RuleConfig declares a deterministic ADR trigger evaluated by the rules engine
+type RuleConfig struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description,omitempty"`
+	When        string   `yaml:"when"`              // Trigger type, e.g. dependency_added, file_added
+	Paths       []string `yaml:"paths,omitempty"`   // Glob patterns the trigger is limited to
+	Pattern     string   `yaml:"pattern,omitempty"` // Regular expression for content_match triggers
 }
 
 const (
@@ -85,6 +122,11 @@ func DefaultConfig() *Config {
 				"log message",
 				"debug",
 			},
+			Chunking: ChunkingSettings{
+				Enabled:        true,
+				MaxChunkSize:   20000,
+				MaxConcurrency: 4,
+			},
 		},
 		Cache: CacheConfig{
 			MaxAge:     7,  // Keep cache entries for 7 days
@@ -107,6 +149,58 @@ func DefaultConfig() *Config {
 				"adr",
 			},
 			CleanupAfter: 1, // Clean up daily
+			Backend:      "json",
+		},
+		Rules: DefaultRules(),
+		Similarity: SimilarityConfig{
+			Method:     "bm25",
+			Threshold:  0.35,
+			MaxResults: 3,
+		},
+		Refs: RefsConfig{
+			Exclude: []string{
+				"*.md",
+				"*.txt",
+			},
+		},
+	}
+}
+
+// DefaultRules returns the built-in ADR triggers used by the rules engine
+func DefaultRules() []RuleConfig {
+	return []RuleConfig{
+		{
+			Name:        "new-dependency",
+			Description: "A new dependency was added to a package manifest",
+			When:        "dependency_added",
+		},
+		{
+			Name:        "new-migration",
+			Description: "A new database migration was added",
+			When:        "file_added",
+			Paths:       []string{"**/db/migrations/**", "**/migrations/**", "**/db/migrate/**"},
+		},
+		{
+			Name:        "destructive-migration",
+			Description: "A migration drops, truncates, renames or retypes existing data",
+			When:        "destructive_migration",
+		},
+		{
+			Name:        "public-api-change",
+			Description: "An exported Go API signature changed",
+			When:        "public_api_changed",
+			Paths:       []string{"pkg/**"},
+		},
+		{
+			Name:        "new-infrastructure",
+			Description: "A new Dockerfile or Terraform module was added",
+			When:        "file_added",
+			Paths:       []string{"**/Dockerfile", "**/Dockerfile.*", "**/*.tf"},
+		},
+		{
+			Name:        "codeowners-path",
+			Description: "A path with designated CODEOWNERS was touched",
+			When:        "codeowners_path",
 		},
 	}
 }
@@ -151,9 +245,25 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", configPath, err)
+	}
+
 	return config, nil
 }
 
+// validate rejects settings that are not supported
+func (c *Config) validate() error {
+	switch c.Similarity.Method {
+	case "", "bm25":
+	case "embeddings":
+		return fmt.Errorf("similarity.method %q is not supported: no AI provider offers embeddings, use \"bm25\"", c.Similarity.Method)
+	default:
+		return fmt.Errorf("unknown similarity.method %q, use \"bm25\"", c.Similarity.Method)
+	}
+	return nil
+}
+
 // Save saves the configuration to the config file
 func (c *Config) Save() error {
 	configDir, err := GetConfigDir()
@@ -0,0 +1,429 @@
+package deps
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/SilverFlin/DrDuck/internal/diff"
+)
+
+// ChangeKind classifies how a dependency changed
+type ChangeKind string
+
+const (
+	KindAdded     ChangeKind = "added"
+	KindRemoved   ChangeKind = "removed"
+	KindMajorBump ChangeKind = "major bump"
+	KindUpdated   ChangeKind = "updated"
+)
+
+// Change describes a single dependency change in a manifest
+type Change struct {
+	Manifest   string     `json:"manifest"`
+	Ecosystem  string     `json:"ecosystem"`
+	Name       string     `json:"name"`
+	Kind       ChangeKind `json:"kind"`
+	OldVersion string     `json:"old_version,omitempty"`
+	NewVersion string     `json:"new_version,omitempty"`
+}
+
+// manifestParser extracts name -> version pairs from manifest text
+type manifestParser struct {
+	ecosystem string
+	parse     func(text string) map[string]string
+}
+
+// parsers maps manifest file names to their parsers
+var parsers = map[string]manifestParser{
+	"go.mod":           {ecosystem: "go", parse: parseGoMod},
+	"package.json":     {ecosystem: "npm", parse: parsePackageJSON},
+	"requirements.txt": {ecosystem: "pip", parse: parseRequirements},
+	"pyproject.toml":   {ecosystem: "pip", parse: parseTOML},
+	"Cargo.toml":       {ecosystem: "cargo", parse: parseTOML},
+	"pom.xml":          {ecosystem: "maven", parse: parsePom},
+}
+
+// IsManifest reports whether the path is a dependency manifest DrDuck understands
+func IsManifest(filePath string) bool {
+	_, ok := parserFor(filePath)
+	return ok
+}
+
+func parserFor(filePath string) (manifestParser, bool) {
+	base := path.Base(filePath)
+	if parser, ok := parsers[base]; ok {
+		return parser, true
+	}
+	// requirements-dev.txt, requirements/prod.txt and friends
+	if strings.HasPrefix(base, "requirements") && strings.HasSuffix(base, ".txt") {
+		return parsers["requirements.txt"], true
+	}
+	return manifestParser{}, false
+}
+
+// Compare reads every manifest in the diff in full at baseRef and headRef and
+// classifies each dependency change. An empty ref reads the working tree.
+func Compare(baseRef, headRef string, files []*diff.File) ([]Change, error) {
+	for _, ref := range []string{baseRef, headRef} {
+		if ref != "" && exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}").Run() != nil {
+			return nil, fmt.Errorf("unknown git ref %q", ref)
+		}
+	}
+
+	var changes []Change
+	for _, file := range files {
+		if !IsManifest(file.Path()) || file.Binary {
+			continue
+		}
+
+		var before, after string
+		var err error
+		if file.Status != diff.StatusAdded {
+			if before, err = readManifest(baseRef, file.OldPath); err != nil {
+				return nil, err
+			}
+		}
+		if file.Status != diff.StatusDeleted {
+			if after, err = readManifest(headRef, file.NewPath); err != nil {
+				return nil, err
+			}
+		}
+		changes = append(changes, Diff(file.Path(), before, after)...)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Manifest != changes[j].Manifest {
+			return changes[i].Manifest < changes[j].Manifest
+		}
+		return changes[i].Name < changes[j].Name
+	})
+
+	return changes, nil
+}
+
+// readManifest reads a file at a ref, or from disk for an empty ref. A file
+// that does not exist there reads as empty.
+func readManifest(ref, filePath string) (string, error) {
+	if ref == "" {
+		content, err := os.ReadFile(filePath)
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", filePath, err)
+		}
+		return string(content), nil
+	}
+
+	object := ref + ":" + filePath
+	if exec.Command("git", "cat-file", "-e", object).Run() != nil {
+		return "", nil
+	}
+	output, err := exec.Command("git", "cat-file", "blob", object).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s at %s: %w", filePath, ref, err)
+	}
+	return string(output), nil
+}
+
+// Diff classifies the dependency changes between two versions of a manifest.
+// Either side may be empty, for a manifest that was added or deleted.
+func Diff(manifest, before, after string) []Change {
+	parser, ok := parserFor(manifest)
+	if !ok {
+		return nil
+	}
+	beforeDeps := parser.parse(before)
+	afterDeps := parser.parse(after)
+
+	var changes []Change
+	for name, newVersion := range afterDeps {
+		oldVersion, existed := beforeDeps[name]
+		change := Change{
+			Manifest:   manifest,
+			Ecosystem:  parser.ecosystem,
+			Name:       name,
+			OldVersion: oldVersion,
+			NewVersion: newVersion,
+		}
+		switch {
+		case !existed:
+			change.Kind = KindAdded
+		case oldVersion == newVersion:
+			continue
+		case majorVersion(oldVersion) != majorVersion(newVersion):
+			change.Kind = KindMajorBump
+		default:
+			change.Kind = KindUpdated
+		}
+		changes = append(changes, change)
+	}
+
+	for name, oldVersion := range beforeDeps {
+		if _, exists := afterDeps[name]; !exists {
+			changes = append(changes, Change{
+				Manifest:   manifest,
+				Ecosystem:  parser.ecosystem,
+				Name:       name,
+				Kind:       KindRemoved,
+				OldVersion: oldVersion,
+			})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+// Significant filters out minor and patch updates, keeping the changes that
+// usually represent a decision: additions, removals and major bumps
+func Significant(changes []Change) []Change {
+	var significant []Change
+	for _, change := range changes {
+		if change.Kind != KindUpdated {
+			significant = append(significant, change)
+		}
+	}
+	return significant
+}
+
+// OfKind returns the changes of the given kind
+func OfKind(changes []Change, kind ChangeKind) []Change {
+	var filtered []Change
+	for _, change := range changes {
+		if change.Kind == kind {
+			filtered = append(filtered, change)
+		}
+	}
+	return filtered
+}
+
+// String renders the change as a single human-readable line
+func (c Change) String() string {
+	switch c.Kind {
+	case KindAdded:
+		return fmt.Sprintf("%s: added %s %s", c.Manifest, c.Name, c.NewVersion)
+	case KindRemoved:
+		return fmt.Sprintf("%s: removed %s %s", c.Manifest, c.Name, c.OldVersion)
+	default:
+		return fmt.Sprintf("%s: %s %s %s -> %s", c.Manifest, c.Kind, c.Name, c.OldVersion, c.NewVersion)
+	}
+}
+
+// Summarize renders changes as a markdown bullet list
+func Summarize(changes []Change) string {
+	var builder strings.Builder
+	for _, change := range changes {
+		builder.WriteString("- ")
+		builder.WriteString(change.String())
+		builder.WriteString("\n")
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+var leadingDigits = regexp.MustCompile(`\d+`)
+
+// majorVersion extracts the major component of a version or version range
+func majorVersion(version string) string {
+	return leadingDigits.FindString(version)
+}
+
+// goModulePattern matches "module/path v1.2.3" inside or outside a require block
+var goModulePattern = regexp.MustCompile(`^\s*(?:require\s+)?([\w.\-~]+(?:/[\w.\-~]+)+)\s+(v[\w.\-+]+)`)
+
+// goMajorSuffix matches the /vN suffix of a Go module path
+var goMajorSuffix = regexp.MustCompile(`/v\d+$`)
+
+func parseGoMod(text string) map[string]string {
+	result := make(map[string]string)
+	for _, line := range strings.Split(text, "\n") {
+		if strings.Contains(line, "=>") {
+			continue // replace directives are not requirements
+		}
+		if m := goModulePattern.FindStringSubmatch(line); m != nil {
+			// Key by path without /vN so a major upgrade shows as a bump, not add+remove
+			result[goMajorSuffix.ReplaceAllString(m[1], "")] = m[2]
+		}
+	}
+	return result
+}
+
+// packageJSONPattern matches a "name": "version-range" pair
+var packageJSONPattern = regexp.MustCompile(`^\s*"(@?[\w.\-/]+)"\s*:\s*"([\^~<>=v]*\d[^"]*|latest|\*|workspace:[^"]*|npm:[^"]*)"`)
+
+// packageJSONIgnoredKeys are top-level fields that look like dependencies but are not
+var packageJSONIgnoredKeys = map[string]bool{
+	"version": true, "node": true, "npm": true, "yarn": true, "pnpm": true,
+}
+
+func parsePackageJSON(text string) map[string]string {
+	result := make(map[string]string)
+	for _, line := range strings.Split(text, "\n") {
+		if m := packageJSONPattern.FindStringSubmatch(line); m != nil && !packageJSONIgnoredKeys[m[1]] {
+			result[m[1]] = m[2]
+		}
+	}
+	return result
+}
+
+// requirementPattern matches "name[extras] <op> version" in requirements files and PEP 508 strings
+var requirementPattern = regexp.MustCompile(`^\s*([A-Za-z0-9][\w.\-]*)(?:\[[^\]]*\])?\s*((?:===|==|>=|<=|~=|!=|>|<)\s*[^\s;,#"]+(?:\s*,\s*(?:===|==|>=|<=|~=|!=|>|<)\s*[^\s;,#"]+)*)?`)
+
+func parseRequirements(text string) map[string]string {
+	result := make(map[string]string)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue // comments and pip options such as -r or -e
+		}
+		if m := requirementPattern.FindStringSubmatch(line); m != nil {
+			result[normalizePythonName(m[1])] = strings.ReplaceAll(m[2], " ", "")
+		}
+	}
+	return result
+}
+
+// tomlInlineVersion matches name = "1.2" and name = { version = "1.2", ... }
+var tomlInlineVersion = regexp.MustCompile(`^\s*([A-Za-z0-9][\w.\-]*)\s*=\s*(?:"([^"]*)"|\{[^}]*version\s*=\s*"([^"]*)"[^}]*\}|\{[^}]*\})`)
+
+// tomlArrayStart matches the opening of an array value, e.g. dependencies = [
+var tomlArrayStart = regexp.MustCompile(`^\s*([\w\-]+)\s*=\s*\[(.*)$`)
+
+// tomlQuoted matches a quoted string inside an array
+var tomlQuoted = regexp.MustCompile(`"([^"]+)"`)
+
+// tomlDependencySection matches section headers that hold dependencies
+var tomlDependencySection = regexp.MustCompile(`^\s*\[(?:.*\.)?(?:dependencies|dev-dependencies|build-dependencies|group\.[\w\-]+\.dependencies)\]\s*$`)
+
+// tomlOptionalSection matches PEP 621 optional dependency groups, whose keys are arrays of requirements
+var tomlOptionalSection = regexp.MustCompile(`^\s*\[(?:.*\.)?optional-dependencies\]\s*$`)
+
+// tomlIgnoredKeys are package metadata keys that share the name = "x" shape
+var tomlIgnoredKeys = map[string]bool{
+	"version": true, "python": true, "requires-python": true, "edition": true,
+	"rust-version": true, "name": true, "description": true, "license": true,
+	"readme": true, "authors": true, "homepage": true, "repository": true,
+}
+
+// versionLike reports whether a value looks like a version or version range
+var versionLike = regexp.MustCompile(`^[\^~<>=!*]*\s*\d|^\*$`)
+
+func parseTOML(text string) map[string]string {
+	result := make(map[string]string)
+
+	addRequirements := func(fragment string) {
+		for _, quoted := range tomlQuoted.FindAllStringSubmatch(fragment, -1) {
+			if r := requirementPattern.FindStringSubmatch(quoted[1]); r != nil {
+				result[normalizePythonName(r[1])] = strings.ReplaceAll(r[2], " ", "")
+			}
+		}
+	}
+
+	// Outside any section header, only version-like values are trusted
+	section := ""
+	inArray := false    // inside a multi-line array
+	inDepArray := false // ... whose entries are PEP 508 requirements
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if inArray {
+			if inDepArray {
+				addRequirements(line)
+			}
+			if strings.Contains(trimmed, "]") {
+				inArray, inDepArray = false, false
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			switch {
+			case tomlDependencySection.MatchString(trimmed):
+				section = "deps"
+			case tomlOptionalSection.MatchString(trimmed):
+				section = "optional"
+			default:
+				section = "other"
+			}
+			continue
+		}
+
+		if m := tomlArrayStart.FindStringSubmatch(line); m != nil {
+			isDeps := m[1] == "dependencies" || section == "optional"
+			if isDeps {
+				addRequirements(m[2])
+			}
+			if !strings.Contains(m[2], "]") {
+				inArray, inDepArray = true, isDeps
+			}
+			continue
+		}
+
+		// A stray array entry outside any section: only trust it if it parses as a requirement with a version
+		if strings.HasPrefix(trimmed, "\"") && section == "" {
+			if quoted := tomlQuoted.FindStringSubmatch(trimmed); quoted != nil {
+				if r := requirementPattern.FindStringSubmatch(quoted[1]); r != nil && r[2] != "" {
+					result[normalizePythonName(r[1])] = strings.ReplaceAll(r[2], " ", "")
+				}
+			}
+			continue
+		}
+
+		m := tomlInlineVersion.FindStringSubmatch(line)
+		if m == nil || tomlIgnoredKeys[m[1]] || section == "other" || section == "optional" {
+			continue
+		}
+		version := m[2]
+		if version == "" {
+			version = m[3]
+		}
+		if section == "deps" || versionLike.MatchString(version) {
+			result[m[1]] = version
+		}
+	}
+
+	return result
+}
+
+var (
+	pomDependencyBlock = regexp.MustCompile(`(?s)<dependency>(.*?)</dependency>`)
+	pomGroupID         = regexp.MustCompile(`<groupId>\s*([^<]+?)\s*</groupId>`)
+	pomArtifactID      = regexp.MustCompile(`<artifactId>\s*([^<]+?)\s*</artifactId>`)
+	pomVersion         = regexp.MustCompile(`<version>\s*([^<]+?)\s*</version>`)
+)
+
+func parsePom(text string) map[string]string {
+	result := make(map[string]string)
+	for _, block := range pomDependencyBlock.FindAllStringSubmatch(text, -1) {
+		artifact := pomArtifactID.FindStringSubmatch(block[1])
+		if artifact == nil {
+			continue
+		}
+		name := artifact[1]
+		if group := pomGroupID.FindStringSubmatch(block[1]); group != nil {
+			name = group[1] + ":" + name
+		}
+		version := ""
+		if v := pomVersion.FindStringSubmatch(block[1]); v != nil {
+			version = v[1]
+		}
+		result[name] = version
+	}
+	return result
+}
+
+// normalizePythonName applies PEP 503 normalization so "Foo_Bar" and "foo-bar" compare equal
+func normalizePythonName(name string) string {
+	name = strings.ToLower(name)
+	return strings.NewReplacer("_", "-", ".", "-").Replace(name)
+}
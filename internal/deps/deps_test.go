@@ -0,0 +1,333 @@
+package deps
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/SilverFlin/DrDuck/internal/diff"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		before   string
+		after    string
+		want     []string
+	}{
+		{
+			name:     "go.mod added, removed and bumped",
+			manifest: "go.mod",
+			before: `module example.com/app
+
+go 1.22
+
+require (
+	github.com/spf13/cobra v1.7.0
+	github.com/pkg/errors v0.9.1
+	github.com/redis/go-redis/v8 v8.11.5
+)
+
+replace github.com/pkg/errors => ../errors
+`,
+			after: `module example.com/app
+
+go 1.22
+
+require (
+	github.com/spf13/cobra v1.8.0
+	github.com/redis/go-redis/v9 v9.5.1
+	go.etcd.io/bbolt v1.4.3
+)
+`,
+			want: []string{
+				"go.mod: removed github.com/pkg/errors v0.9.1",
+				"go.mod: major bump github.com/redis/go-redis v8.11.5 -> v9.5.1",
+				"go.mod: updated github.com/spf13/cobra v1.7.0 -> v1.8.0",
+				"go.mod: added go.etcd.io/bbolt v1.4.3",
+			},
+		},
+		{
+			name:     "package.json",
+			manifest: "web/package.json",
+			before: `{
+  "name": "web",
+  "version": "1.0.0",
+  "engines": { "node": ">=18" },
+  "dependencies": {
+    "react": "^17.0.2",
+    "moment": "^2.29.4"
+  }
+}
+`,
+			after: `{
+  "name": "web",
+  "version": "1.1.0",
+  "engines": { "node": ">=20" },
+  "dependencies": {
+    "react": "^18.2.0",
+    "date-fns": "^3.6.0"
+  }
+}
+`,
+			want: []string{
+				"web/package.json: added date-fns ^3.6.0",
+				"web/package.json: removed moment ^2.29.4",
+				"web/package.json: major bump react ^17.0.2 -> ^18.2.0",
+			},
+		},
+		{
+			name:     "requirements.txt",
+			manifest: "requirements-dev.txt",
+			before:   "-r requirements.txt\nDjango==4.2.1\nrequests[socks]>=2.28\n",
+			after:    "-r requirements.txt\ndjango==5.0.1\nhttpx>=0.27 ; python_version >= '3.8'\n",
+			want: []string{
+				"requirements-dev.txt: major bump django ==4.2.1 -> ==5.0.1",
+				"requirements-dev.txt: added httpx >=0.27",
+				"requirements-dev.txt: removed requests >=2.28",
+			},
+		},
+		{
+			name:     "pyproject.toml",
+			manifest: "pyproject.toml",
+			before: `[project]
+name = "app"
+version = "1.0.0"
+requires-python = ">=3.10"
+dependencies = [
+    "fastapi>=0.100",
+    "pydantic>=1.10",
+]
+
+[tool.black]
+line-length = 100
+`,
+			after: `[project]
+name = "app"
+version = "1.1.0"
+requires-python = ">=3.11"
+dependencies = [
+    "fastapi>=0.110",
+    "pydantic>=2.6",
+    "sqlalchemy>=2.0",
+]
+
+[tool.black]
+line-length = 120
+`,
+			want: []string{
+				"pyproject.toml: updated fastapi >=0.100 -> >=0.110",
+				"pyproject.toml: major bump pydantic >=1.10 -> >=2.6",
+				"pyproject.toml: added sqlalchemy >=2.0",
+			},
+		},
+		{
+			name:     "Cargo.toml",
+			manifest: "Cargo.toml",
+			before: `[package]
+name = "app"
+version = "0.1.0"
+edition = "2021"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+tokio = "1.36"
+
+[dev-dependencies]
+mockall = "0.11"
+`,
+			after: `[package]
+name = "app"
+version = "0.2.0"
+edition = "2021"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+axum = "0.7"
+
+[dev-dependencies]
+mockall = "0.12"
+`,
+			want: []string{
+				"Cargo.toml: added axum 0.7",
+				"Cargo.toml: updated mockall 0.11 -> 0.12",
+				"Cargo.toml: removed tokio 1.36",
+			},
+		},
+		{
+			name:     "pom.xml",
+			manifest: "pom.xml",
+			before: `<project>
+  <version>1.0.0</version>
+  <dependencies>
+    <dependency>
+      <groupId>org.springframework.boot</groupId>
+      <artifactId>spring-boot-starter-web</artifactId>
+      <version>2.7.18</version>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.13.2</version>
+    </dependency>
+  </dependencies>
+</project>
+`,
+			after: `<project>
+  <version>1.1.0</version>
+  <dependencies>
+    <dependency>
+      <groupId>org.springframework.boot</groupId>
+      <artifactId>spring-boot-starter-web</artifactId>
+      <version>3.2.4</version>
+    </dependency>
+    <dependency>
+      <groupId>org.junit.jupiter</groupId>
+      <artifactId>junit-jupiter</artifactId>
+      <version>5.10.2</version>
+    </dependency>
+  </dependencies>
+</project>
+`,
+			want: []string{
+				"pom.xml: removed junit:junit 4.13.2",
+				"pom.xml: added org.junit.jupiter:junit-jupiter 5.10.2",
+				"pom.xml: major bump org.springframework.boot:spring-boot-starter-web 2.7.18 -> 3.2.4",
+			},
+		},
+		{
+			name:     "new manifest",
+			manifest: "go.mod",
+			after:    "module example.com/app\n\nrequire github.com/spf13/cobra v1.8.0\n",
+			want:     []string{"go.mod: added github.com/spf13/cobra v1.8.0"},
+		},
+		{
+			name:     "deleted manifest",
+			manifest: "requirements.txt",
+			before:   "flask==3.0.0\n",
+			want:     []string{"requirements.txt: removed flask ==3.0.0"},
+		},
+		{
+			name:     "not a manifest",
+			manifest: "README.md",
+			before:   "flask==3.0.0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, change := range Diff(tt.manifest, tt.before, tt.after) {
+				got = append(got, change.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestSignificantAndOfKind(t *testing.T) {
+	changes := []Change{
+		{Name: "a", Kind: KindAdded},
+		{Name: "b", Kind: KindUpdated},
+		{Name: "c", Kind: KindMajorBump},
+		{Name: "d", Kind: KindRemoved},
+	}
+
+	var significant []string
+	for _, change := range Significant(changes) {
+		significant = append(significant, change.Name)
+	}
+	if !reflect.DeepEqual(significant, []string{"a", "c", "d"}) {
+		t.Errorf("Significant = %v, want [a c d]", significant)
+	}
+	if added := OfKind(changes, KindAdded); len(added) != 1 || added[0].Name != "a" {
+		t.Errorf("OfKind(added) = %v", added)
+	}
+}
+
+// git runs a git command in the current directory
+func git(t *testing.T, args ...string) string {
+	t.Helper()
+
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Dev")
+	t.Setenv("GIT_AUTHOR_EMAIL", "dev@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Dev")
+	t.Setenv("GIT_COMMITTER_EMAIL", "dev@example.com")
+	git(t, "init", "-q")
+
+	// The dependency section header is far outside the hunk context
+	padding := strings.Repeat("# keep\n", 10)
+	writeFile(t, "Cargo.toml", "[dependencies]\n"+padding+"serde = \"1.0\"\n")
+	unchanged := "gunicorn==21.2.0\nredis==5.0.1\nrich==13.7.0\ncelery==5.3.6\n"
+	writeFile(t, "old/requirements.txt", unchanged+"flask==2.3.0\n")
+	writeFile(t, "legacy/package.json", "{\n  \"dependencies\": {\n    \"jquery\": \"^3.7.1\"\n  }\n}\n")
+	git(t, "add", "-A")
+	git(t, "commit", "-q", "-m", "base")
+	base := git(t, "rev-parse", "HEAD")
+
+	writeFile(t, "Cargo.toml", "[dependencies]\n"+padding+"serde = \"2.0\"\ntokio = { version = \"1.36\" }\n")
+	git(t, "mv", "old/requirements.txt", "requirements.txt")
+	writeFile(t, "requirements.txt", unchanged+"flask==3.0.0\n")
+	git(t, "rm", "-q", "legacy/package.json")
+	writeFile(t, "go.mod", "module example.com/app\n\nrequire github.com/spf13/cobra v1.8.0\n")
+	git(t, "add", "-A")
+
+	files := diff.Parse(git(t, "diff", "--cached", "-M", base) + "\n")
+	want := []string{
+		"Cargo.toml: major bump serde 1.0 -> 2.0",
+		"Cargo.toml: added tokio 1.36",
+		"go.mod: added github.com/spf13/cobra v1.8.0",
+		"legacy/package.json: removed jquery ^3.7.1",
+		"requirements.txt: major bump flask ==2.3.0 -> ==3.0.0",
+	}
+
+	// The staged changes on disk, then the same changes committed
+	for _, headRef := range []string{"", "HEAD"} {
+		if headRef == "HEAD" {
+			git(t, "commit", "-q", "-m", "head")
+		}
+		changes, err := Compare(base, headRef, files)
+		if err != nil {
+			t.Fatalf("Compare(%q): %v", headRef, err)
+		}
+		var got []string
+		for _, change := range changes {
+			got = append(got, change.String())
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Compare(%q) =\n%s\nwant\n%s", headRef, strings.Join(got, "\n"), strings.Join(want, "\n"))
+		}
+	}
+
+	if _, err := Compare("no-such-ref", "HEAD", files); err == nil {
+		t.Error("expected an error for an unknown base ref")
+	}
+}
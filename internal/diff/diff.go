@@ -0,0 +1,265 @@
+package diff
+
+import (
+	"strings"
+)
+
+// FileStatus describes how a file changed in a diff
+type FileStatus string
+
+const (
+	StatusAdded    FileStatus = "added"
+	StatusDeleted  FileStatus = "deleted"
+	StatusModified FileStatus = "modified"
+	StatusRenamed  FileStatus = "renamed"
+)
+
+// LineKind identifies a line inside a hunk
+type LineKind byte
+
+const (
+	LineContext LineKind = ' '
+	LineAdded   LineKind = '+'
+	LineRemoved LineKind = '-'
+)
+
+// Line is a single line inside a hunk
+type Line struct {
+	Kind LineKind
+	Text string
+}
+
+// Hunk is a contiguous block of changes within a file
+type Hunk struct {
+	Header string
+	Lines  []Line
+}
+
+// File holds the parsed changes for a single file in a unified diff
+type File struct {
+	OldPath string
+	NewPath string
+	Status  FileStatus
+	Binary  bool
+	Hunks   []Hunk
+	Raw     string // The raw diff text for this file, headers included
+}
+
+// Path returns the most relevant path for the file (the new path unless deleted)
+func (f *File) Path() string {
+	if f.Status == StatusDeleted || f.NewPath == "" {
+		return f.OldPath
+	}
+	return f.NewPath
+}
+
+// AddedLines returns the text of all added lines
+func (f *File) AddedLines() []string {
+	return f.linesOfKind(LineAdded)
+}
+
+// RemovedLines returns the text of all removed lines
+func (f *File) RemovedLines() []string {
+	return f.linesOfKind(LineRemoved)
+}
+
+// Before reconstructs the visible "before" side of every hunk (context + removed lines)
+func (f *File) Before() string {
+	return f.side(LineRemoved)
+}
+
+// After reconstructs the visible "after" side of every hunk (context + added lines)
+func (f *File) After() string {
+	return f.side(LineAdded)
+}
+
+// Header returns the raw diff header lines of the file, without any hunks
+func (f *File) Header() string {
+	if idx := strings.Index(f.Raw, "\n@@"); idx != -1 {
+		return f.Raw[:idx]
+	}
+	return f.Raw
+}
+
+// Stats returns the number of added and removed lines
+func (f *File) Stats() (added, removed int) {
+	for _, hunk := range f.Hunks {
+		for _, line := range hunk.Lines {
+			switch line.Kind {
+			case LineAdded:
+				added++
+			case LineRemoved:
+				removed++
+			}
+		}
+	}
+	return added, removed
+}
+
+func (f *File) linesOfKind(kind LineKind) []string {
+	var lines []string
+	for _, hunk := range f.Hunks {
+		for _, line := range hunk.Lines {
+			if line.Kind == kind {
+				lines = append(lines, line.Text)
+			}
+		}
+	}
+	return lines
+}
+
+func (f *File) side(kind LineKind) string {
+	var builder strings.Builder
+	for _, hunk := range f.Hunks {
+		for _, line := range hunk.Lines {
+			if line.Kind == LineContext || line.Kind == kind {
+				builder.WriteString(line.Text)
+				builder.WriteString("\n")
+			}
+		}
+	}
+	return builder.String()
+}
+
+// Parse splits a unified git diff into per-file changes
+func Parse(raw string) []*File {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+
+	var files []*File
+	var current *File
+	var rawLines []string
+	var hunk *Hunk
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		if hunk != nil {
+			current.Hunks = append(current.Hunks, *hunk)
+			hunk = nil
+		}
+		current.Raw = strings.Join(rawLines, "\n")
+		files = append(files, current)
+		current = nil
+		rawLines = nil
+	}
+
+	for _, line := range strings.Split(raw, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+			current = &File{Status: StatusModified}
+			current.OldPath, current.NewPath = parseGitHeader(line)
+			rawLines = append(rawLines, line)
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+		rawLines = append(rawLines, line)
+
+		// Hunk body
+		if hunk != nil {
+			switch {
+			case strings.HasPrefix(line, "@@"):
+				current.Hunks = append(current.Hunks, *hunk)
+				hunk = &Hunk{Header: line}
+			case strings.HasPrefix(line, "+"):
+				hunk.Lines = append(hunk.Lines, Line{Kind: LineAdded, Text: line[1:]})
+			case strings.HasPrefix(line, "-"):
+				hunk.Lines = append(hunk.Lines, Line{Kind: LineRemoved, Text: line[1:]})
+			case strings.HasPrefix(line, " "):
+				hunk.Lines = append(hunk.Lines, Line{Kind: LineContext, Text: line[1:]})
+			case line == "":
+				hunk.Lines = append(hunk.Lines, Line{Kind: LineContext, Text: ""})
+			}
+			continue
+		}
+
+		// Extended header lines
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			hunk = &Hunk{Header: line}
+		case strings.HasPrefix(line, "new file mode"):
+			current.Status = StatusAdded
+		case strings.HasPrefix(line, "deleted file mode"):
+			current.Status = StatusDeleted
+		case strings.HasPrefix(line, "rename from "):
+			current.Status = StatusRenamed
+			current.OldPath = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			current.Status = StatusRenamed
+			current.NewPath = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "Binary files "):
+			current.Binary = true
+		case strings.HasPrefix(line, "--- "):
+			if path := stripPrefix(strings.TrimPrefix(line, "--- ")); path != "" {
+				current.OldPath = path
+			}
+		case strings.HasPrefix(line, "+++ "):
+			if path := stripPrefix(strings.TrimPrefix(line, "+++ ")); path != "" {
+				current.NewPath = path
+			}
+		}
+	}
+	flush()
+
+	// Trailing empty context lines come from the final newline of the diff output
+	for _, file := range files {
+		if n := len(file.Hunks); n > 0 {
+			lines := file.Hunks[n-1].Lines
+			for len(lines) > 0 && lines[len(lines)-1].Kind == LineContext && lines[len(lines)-1].Text == "" {
+				lines = lines[:len(lines)-1]
+			}
+			file.Hunks[n-1].Lines = lines
+		}
+		file.Raw = strings.TrimRight(file.Raw, "\n")
+	}
+
+	return files
+}
+
+// Render joins the raw diff text of the given files back into a single diff
+func Render(files []*File) string {
+	parts := make([]string, 0, len(files))
+	for _, file := range files {
+		parts = append(parts, file.Raw)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// Paths returns the paths of all files in the diff
+func Paths(files []*File) []string {
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, file.Path())
+	}
+	return paths
+}
+
+// parseGitHeader extracts the a/ and b/ paths from a "diff --git a/x b/y" line
+func parseGitHeader(line string) (string, string) {
+	rest := strings.TrimPrefix(line, "diff --git ")
+	if idx := strings.Index(rest, " b/"); idx != -1 {
+		return strings.TrimPrefix(rest[:idx], "a/"), rest[idx+3:]
+	}
+	parts := strings.Fields(rest)
+	if len(parts) == 2 {
+		return strings.TrimPrefix(parts[0], "a/"), strings.TrimPrefix(parts[1], "b/")
+	}
+	return "", ""
+}
+
+// stripPrefix removes the a/ or b/ prefix from ---/+++ paths, returning "" for /dev/null
+func stripPrefix(path string) string {
+	path = strings.TrimSpace(path)
+	if path == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(path, "a/") || strings.HasPrefix(path, "b/") {
+		return path[2:]
+	}
+	return path
+}
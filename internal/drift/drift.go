@@ -0,0 +1,40 @@
+package drift
+
+import (
+	"strings"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/rules"
+)
+
+// Finding is an accepted ADR whose governed paths were changed
+type Finding struct {
+	ADR   *adr.ADR
+	Files []string // Changed files matching the ADR's governs globs
+
+	// Set when the AI was asked whether the change contradicts the decision
+	Checked     bool
+	Contradicts bool
+	Reasoning   string
+}
+
+// Detect returns the accepted ADRs that govern at least one of the changed paths
+func Detect(adrs []*adr.ADR, changedPaths []string) []Finding {
+	var findings []Finding
+	for _, a := range adrs {
+		if len(a.Governs) == 0 || !strings.EqualFold(string(a.Status), string(adr.StatusAccepted)) {
+			continue
+		}
+
+		var files []string
+		for _, path := range changedPaths {
+			if rules.MatchAny(a.Governs, path) {
+				files = append(files, path)
+			}
+		}
+		if len(files) > 0 {
+			findings = append(findings, Finding{ADR: a, Files: files})
+		}
+	}
+	return findings
+}
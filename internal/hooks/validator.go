@@ -1,27 +1,38 @@
 package hooks
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
 
 	"github.com/SilverFlin/DrDuck/internal/adr"
 	"github.com/SilverFlin/DrDuck/internal/ai"
+	"github.com/SilverFlin/DrDuck/internal/apidiff"
 	"github.com/SilverFlin/DrDuck/internal/cache"
+	"github.com/SilverFlin/DrDuck/internal/chunking"
 	"github.com/SilverFlin/DrDuck/internal/config"
+	"github.com/SilverFlin/DrDuck/internal/diff"
+	"github.com/SilverFlin/DrDuck/internal/drift"
 	"github.com/SilverFlin/DrDuck/internal/prompts/templates"
+	"github.com/SilverFlin/DrDuck/internal/refs"
+	"github.com/SilverFlin/DrDuck/internal/signals"
 	"github.com/charmbracelet/huh"
 )
 
 // ValidationResult represents the result of hook validation
 type ValidationResult struct {
-	ShouldBlock     bool
-	DraftADRs       []*adr.ADR
-	NeedsADR        bool
-	SuggestedTitle  string
-	Message         string
-	AIResponse      string
+	ShouldBlock      bool
+	DraftADRs        []*adr.ADR
+	NeedsADR         bool
+	SuggestedTitle   string
+	SuggestedTrailer string          // ADR trailer suggested for a commit message
+	Drift            []drift.Finding // Accepted ADRs governing changed paths
+	Message          string
+	AIResponse       string
 }
 
 // Validator handles git hook validation logic
@@ -30,18 +41,30 @@ type Validator struct {
 	adrManager   *adr.Manager
 	aiManager    *ai.Manager
 	cacheManager *cache.Manager
+	interactive  bool // Whether the user may be prompted
 }
 
 // NewValidator creates a new hook validator
 func NewValidator(cfg *config.Config) *Validator {
+	aiManager := ai.NewManager(cfg)
+	cacheManager := cache.NewManagerFromMainConfig(cfg.Cache)
+	cacheManager.SetAnalysisConfig(aiManager.AnalysisConfig())
+
 	return &Validator{
 		config:       cfg,
 		adrManager:   adr.NewManager(cfg),
-		aiManager:    ai.NewManager(cfg),
-		cacheManager: cache.NewManagerFromMainConfig(cfg.Cache),
+		aiManager:    aiManager,
+		cacheManager: cacheManager,
+		interactive:  true,
 	}
 }
 
+// SetInteractive controls whether validation may prompt the user, e.g. to
+// create a missing ADR. Callers without a terminal, like the HTTP API, turn it off.
+func (v *Validator) SetInteractive(interactive bool) {
+	v.interactive = interactive
+}
+
 // ValidatePreCommit performs pre-commit validation (warns but never blocks)
 func (v *Validator) ValidatePreCommit() *ValidationResult {
 	result := &ValidationResult{
@@ -57,8 +80,13 @@ func (v *Validator) ValidatePreCommit() *ValidationResult {
 
 	result.DraftADRs = drafts
 
+	// Staged changes to code governed by accepted ADRs
+	if staged, err := exec.Command("git", "diff", "--cached").Output(); err == nil {
+		result.Drift = v.checkDrift(string(staged))
+	}
+
 	if len(drafts) == 0 {
-		result.Message = "🦆 DrDuck: All ADRs are up to date! ✨"
+		result.Message = withDrift("🦆 DrDuck: All ADRs are up to date! ✨", result.Drift)
 		return result
 	}
 
@@ -86,7 +114,7 @@ func (v *Validator) ValidatePreCommit() *ValidationResult {
 	}
 
 	messageBuilder.WriteString("\n✨ Commit proceeding as normal...")
-	result.Message = messageBuilder.String()
+	result.Message = withDrift(messageBuilder.String(), result.Drift)
 
 	return result
 }
@@ -132,8 +160,15 @@ func (v *Validator) ValidatePrePush() *ValidationResult {
 		return result
 	}
 
+	// The pushed changes are read once, for the ADR analysis and the drift check
+	baseRef, changes, err := v.getPushedChanges()
+	if err != nil {
+		result.Message = fmt.Sprintf("⚠️  Could not analyze changes: %v\n✅ Push proceeding...", err)
+		return result
+	}
+
 	// If no drafts, check if changes need a new ADR using AI
-	needsADR, aiResponse, suggestedTitle, err := v.analyzeChangesForADR()
+	needsADR, aiResponse, suggestedTitle, err := v.analyzeChangesForADR(baseRef, changes)
 	if err != nil {
 		// Don't block on AI errors, just warn
 		result.Message = fmt.Sprintf("⚠️  Could not analyze changes with AI: %v\n✅ Push proceeding...", err)
@@ -144,9 +179,15 @@ func (v *Validator) ValidatePrePush() *ValidationResult {
 	result.AIResponse = aiResponse
 	result.SuggestedTitle = suggestedTitle
 
+	// Pushed changes to code governed by accepted ADRs
+	result.Drift = v.checkDrift(changes)
+
 	if needsADR {
 		// Ask user if they want to create ADR automatically
-		shouldCreate, err := v.askUserToCreateADR(suggestedTitle, aiResponse)
+		shouldCreate := false
+		if v.interactive {
+			shouldCreate, err = v.askUserToCreateADR(suggestedTitle, aiResponse)
+		}
 		if err == nil && shouldCreate {
 			// Run complete-adr --create automatically
 			createResult := v.runCompleteADRCreate()
@@ -178,7 +219,7 @@ func (v *Validator) ValidatePrePush() *ValidationResult {
 		messageBuilder.WriteString("   3. Or use emergency bypass: git push --no-verify\n\n")
 		messageBuilder.WriteString("💡 Recommended: Use 'complete-adr --create' for AI-assisted ADR generation")
 
-		result.Message = messageBuilder.String()
+		result.Message = withDrift(messageBuilder.String(), result.Drift)
 		return result
 	}
 
@@ -192,10 +233,210 @@ func (v *Validator) ValidatePrePush() *ValidationResult {
 	}
 	messageBuilder.WriteString("✅ Push proceeding...")
 	
+	result.Message = withDrift(messageBuilder.String(), result.Drift)
+	return result
+}
+
+// checkDrift finds accepted ADRs whose governed paths are touched by the changes
+// and, when enabled, asks the AI whether the changes contradict each decision
+func (v *Validator) checkDrift(changes string) []drift.Finding {
+	if strings.TrimSpace(changes) == "" {
+		return nil
+	}
+
+	adrs, err := v.adrManager.List()
+	if err != nil {
+		return nil
+	}
+
+	files := diff.Parse(changes)
+	findings := drift.Detect(adrs, diff.Paths(files))
+	if !v.config.AISettings.CheckDrift || !v.aiManager.CanGenerate() {
+		return findings
+	}
+
+	for i := range findings {
+		finding := &findings[i]
+		if finding.ADR.Decision == "" {
+			continue
+		}
+
+		var governed []*diff.File
+		for _, file := range files {
+			for _, path := range finding.Files {
+				if file.Path() == path {
+					governed = append(governed, file)
+					break
+				}
+			}
+		}
+
+		// Verdicts are cached with the chunk summaries so pre-commit and pre-push share them
+		prompt := templates.DriftCheckPrompt(finding.ADR.Title, finding.ADR.Decision, diff.Render(governed))
+		hash := sha256.Sum256([]byte(prompt))
+		key := "drift-" + hex.EncodeToString(hash[:])
+
+		response, found := v.cacheManager.GetChunkSummary(key)
+		if !found {
+			response, err = v.aiManager.AnalyzeChanges(prompt)
+			if err != nil {
+				continue
+			}
+			_ = v.cacheManager.StoreChunkSummaries(map[string]string{key: response})
+		}
+
+		finding.Checked = true
+		finding.Contradicts, finding.Reasoning = templates.ParseDriftVerdict(response)
+	}
+	return findings
+}
+
+// withDrift appends drift warnings to a validation message
+func withDrift(message string, findings []drift.Finding) string {
+	if len(findings) == 0 {
+		return message
+	}
+
+	var messageBuilder strings.Builder
+	messageBuilder.WriteString(message)
+	messageBuilder.WriteString("\n\n🧭 Changes touch code governed by accepted ADRs:\n")
+	for _, finding := range findings {
+		messageBuilder.WriteString(fmt.Sprintf("   📐 ADR-%04d: %s (%s)\n", finding.ADR.ID, finding.ADR.Title, finding.ADR.Status))
+		messageBuilder.WriteString(fmt.Sprintf("      %s\n", strings.Join(finding.Files, ", ")))
+		if finding.Checked {
+			if finding.Contradicts {
+				messageBuilder.WriteString(fmt.Sprintf("      ⚠️  May contradict this decision: %s\n", finding.Reasoning))
+			} else {
+				messageBuilder.WriteString("      ✅ Consistent with this decision\n")
+			}
+		}
+	}
+	messageBuilder.WriteString("💡 If the decision no longer holds, supersede it with a new ADR")
+	return messageBuilder.String()
+}
+
+// ValidateCommitMsg checks the ADR trailers of a commit message (blocks only on
+// trailers referencing ADRs that do not exist). Without a trailer, one is
+// suggested when the analyzer flagged changes on the current branch.
+func (v *Validator) ValidateCommitMsg(messageFile string) *ValidationResult {
+	result := &ValidationResult{}
+
+	content, err := os.ReadFile(messageFile)
+	if err != nil {
+		result.Message = fmt.Sprintf("⚠️  Could not read commit message: %v", err)
+		return result
+	}
+
+	ids := adr.ParseTrailerIDs(string(content))
+	if len(ids) > 0 {
+		var linked, unknown []string
+		for _, id := range ids {
+			if _, err := v.adrManager.GetADRByID(id); err != nil {
+				unknown = append(unknown, fmt.Sprintf("%04d", id))
+			} else {
+				linked = append(linked, fmt.Sprintf("%04d", id))
+			}
+		}
+
+		if len(unknown) > 0 {
+			result.ShouldBlock = true
+			result.Message = fmt.Sprintf("🚫 DrDuck: The %s trailer references unknown ADR(s): %s\n\n💡 See existing ADRs with: drduck list",
+				adr.TrailerKey, strings.Join(unknown, ", "))
+			return result
+		}
+
+		result.Message = fmt.Sprintf("🦆 DrDuck: Commit linked to ADR %s ✨", strings.Join(linked, ", "))
+		return result
+	}
+
+	flagged := v.flaggedAnalysis()
+	if flagged == nil {
+		return result
+	}
+
+	var messageBuilder strings.Builder
+	messageBuilder.WriteString("🦆 DrDuck: Changes on this branch were flagged as needing an ADR")
+	if flagged.Title != "" {
+		messageBuilder.WriteString(fmt.Sprintf(" (%s)", flagged.Title))
+	}
+	messageBuilder.WriteString(".\n")
+
+	switch {
+	case flagged.Resolved:
+		result.SuggestedTrailer = adr.FormatTrailer(flagged.ResolvedADRID)
+		messageBuilder.WriteString(fmt.Sprintf("💡 They are documented in ADR %04d. Link this commit by ending its message with:\n   %s",
+			flagged.ResolvedADRID, result.SuggestedTrailer))
+	default:
+		drafts, _ := v.getDraftADRs()
+		if len(drafts) > 0 {
+			draft := drafts[len(drafts)-1]
+			result.SuggestedTrailer = adr.FormatTrailer(draft.ID)
+			messageBuilder.WriteString(fmt.Sprintf("💡 If draft ADR %04d (%s) covers them, end this commit message with:\n   %s",
+				draft.ID, draft.Title, result.SuggestedTrailer))
+		} else {
+			messageBuilder.WriteString("💡 Create one with 'drduck complete-adr --create', then link commits with a trailer like:\n   ")
+			messageBuilder.WriteString(adr.FormatTrailer(1))
+		}
+	}
+
+	result.NeedsADR = true
+	result.Message = messageBuilder.String()
+	return result
+}
+
+// ValidateRefs checks ADR references in code comments (warns but never blocks):
+// references to missing, rejected or superseded ADRs, and accepted ADRs no code references
+func (v *Validator) ValidateRefs() *ValidationResult {
+	result := &ValidationResult{}
+
+	report, err := refs.CheckProject(v.config)
+	if err != nil {
+		result.Message = fmt.Sprintf("⚠️  Could not scan ADR references: %v", err)
+		return result
+	}
+
+	if !report.HasProblems() {
+		result.Message = fmt.Sprintf("🦆 DrDuck: %d ADR(s) referenced from code, no stale references ✨", len(report.ByADR))
+		return result
+	}
+
+	var messageBuilder strings.Builder
+	messageBuilder.WriteString("🦆 DrDuck: Found ADR reference issues:\n")
+	for _, ref := range report.Missing {
+		messageBuilder.WriteString(fmt.Sprintf("   ❓ %s references ADR-%04d, which does not exist\n", ref.Location(), ref.ADRID))
+	}
+	for _, ref := range report.Stale {
+		messageBuilder.WriteString(fmt.Sprintf("   ⏭️  %s references ADR-%04d, which is %s\n", ref.Location(), ref.ADRID, strings.ToLower(string(ref.Status))))
+	}
+	for _, a := range report.Unreferenced {
+		messageBuilder.WriteString(fmt.Sprintf("   🔍 ADR-%04d: %s is accepted but no code references it\n", a.ID, a.Title))
+	}
+	messageBuilder.WriteString("\n💡 Run 'drduck refs' for the full code ↔ ADR index")
+
 	result.Message = messageBuilder.String()
 	return result
 }
 
+// flaggedAnalysis returns the newest cached analysis of the current branch that
+// asked for an ADR, or nil when there is none
+func (v *Validator) flaggedAnalysis() *cache.AnalysisResult {
+	branchOutput, err := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return nil
+	}
+
+	entries, err := v.cacheManager.Query(cache.Query{Branch: strings.TrimSpace(string(branchOutput))})
+	if err != nil {
+		return nil
+	}
+	for _, entry := range entries {
+		if entry.Analysis.Decision == "yes" {
+			return entry.Analysis
+		}
+	}
+	return nil
+}
+
 // getDraftADRs returns all ADRs currently in draft status
 func (v *Validator) getDraftADRs() ([]*adr.ADR, error) {
 	allADRs, err := v.adrManager.List()
@@ -213,8 +454,8 @@ func (v *Validator) getDraftADRs() ([]*adr.ADR, error) {
 	return drafts, nil
 }
 
-// analyzeChangesForADR uses AI to determine if the current changes require an ADR
-func (v *Validator) analyzeChangesForADR() (needsADR bool, aiResponse string, suggestedTitle string, err error) {
+// analyzeChangesForADR uses AI to determine if the changes since baseRef require an ADR
+func (v *Validator) analyzeChangesForADR(baseRef, changes string) (needsADR bool, aiResponse string, suggestedTitle string, err error) {
 	// First, check if we have a cached analysis for these changes
 	cachedAnalysis, found, cacheErr := v.cacheManager.GetAnalysis()
 	if cacheErr == nil && found && cachedAnalysis != nil {
@@ -233,12 +474,6 @@ func (v *Validator) analyzeChangesForADR() (needsADR bool, aiResponse string, su
 		return false, "", "", fmt.Errorf("AI provider (%s) not available", v.aiManager.GetProviderName())
 	}
 
-	// Get git changes since last push
-	changes, err := v.getGitChangesSinceLastPush()
-	if err != nil {
-		return false, "", "", fmt.Errorf("failed to get git changes: %w", err)
-	}
-
 	if strings.TrimSpace(changes) == "" {
 		return false, "No changes to analyze", "", nil
 	}
@@ -250,13 +485,56 @@ func (v *Validator) analyzeChangesForADR() (needsADR bool, aiResponse string, su
 		recentCommits = ""
 	}
 
-	// Generate analysis prompt
-	prompt := templates.ChangeAnalysisPrompt("", changes, recentCommits)
+	// Extract structured signals (e.g. dependency changes) so the AI does not have to infer them
+	files := diff.Parse(changes)
+	changeSignals := signals.Collect(files)
+	// Comparing with the base is best effort: a shallow clone may lack the base commit
+	_ = changeSignals.CompareRefs(baseRef, files)
+
+	// AI providers get the schema summary instead of raw migration SQL; the
+	// rules engine parses the diff itself and needs it unchanged
+	promptChanges := changes
+	if v.aiManager.CanGenerate() {
+		promptChanges = changeSignals.Condense(changes)
+	}
 
-	// Use AI to analyze changes
-	response, err := v.analyzeWithAI(prompt)
-	if err != nil {
-		return false, "", "", err
+	// Files whose diff was already summarized are sent as their cached summary only
+	sections := changeSignals.Sections()
+	var plan *cache.IncrementalPlan
+	if v.aiManager.CanGenerate() {
+		plan = v.cacheManager.PlanIncremental(diff.Parse(promptChanges))
+		if plan.HasCachedFiles() {
+			promptChanges = diff.Render(plan.Changed)
+			sections = append(sections, templates.AnalysisSection{Title: "Previously Analyzed Files", Content: plan.CachedSummaries()})
+		}
+	}
+
+	var response string
+	chunkingSettings := v.config.AISettings.Chunking
+	analyzer := chunking.NewAnalyzer(v.aiManager, v.cacheManager, chunkingSettings.MaxChunkSize, chunkingSettings.MaxConcurrency)
+	if chunkingSettings.Enabled && v.aiManager.CanGenerate() && analyzer.NeedsChunking(promptChanges) {
+		// Too large for one prompt: summarize per directory, then decide on the summaries
+		result, err := analyzer.Analyze(diff.Parse(promptChanges), recentCommits, sections...)
+		if err != nil {
+			return false, "", "", err
+		}
+		response = result.Response
+	} else {
+		// Generate analysis prompt
+		prompt := templates.ChangeAnalysisPrompt("", promptChanges, recentCommits, sections...)
+
+		// Use AI to analyze changes
+		response, err = v.analyzeWithAI(prompt)
+		if err != nil {
+			return false, "", "", err
+		}
+
+		// Keep the per-file summaries for the next run and hide them from the user
+		var fileSummaries map[string]string
+		response, fileSummaries = templates.ParseFileSummaries(response)
+		if plan != nil {
+			_ = v.cacheManager.StoreFileSummaries(plan, fileSummaries)
+		}
 	}
 
 	// Parse AI response to determine if ADR is needed
@@ -283,6 +561,28 @@ func (v *Validator) analyzeChangesForADR() (needsADR bool, aiResponse string, su
 		}
 	}
 
+	// Destructive schema changes always deserve an ADR, whatever the AI concluded
+	if destructive := changeSignals.DestructiveMigrations(); !needsADR && len(destructive) > 0 {
+		needsADR = true
+		var paths []string
+		for _, migration := range destructive {
+			paths = append(paths, migration.Path)
+		}
+		response += fmt.Sprintf("\n\n⚠️  Destructive schema changes detected in: %s", strings.Join(paths, ", "))
+		if suggestedTitle == "" {
+			suggestedTitle = "document-destructive-schema-change"
+		}
+	}
+
+	// Breaking changes to exported Go APIs are a strong signal as well
+	if breaking := changeSignals.BreakingAPIChanges(); !needsADR && len(breaking) > 0 {
+		needsADR = true
+		response += fmt.Sprintf("\n\n⚠️  Breaking public API changes detected:\n%s", apidiff.Summarize(breaking))
+		if suggestedTitle == "" {
+			suggestedTitle = "document-breaking-api-change"
+		}
+	}
+
 	// Cache the analysis result
 	decision := "no"
 	if needsADR {
@@ -297,40 +597,19 @@ func (v *Validator) analyzeChangesForADR() (needsADR bool, aiResponse string, su
 	return needsADR, response, suggestedTitle, nil
 }
 
-// getGitChangesSinceLastPush gets the diff since the last push to current branch
-func (v *Validator) getGitChangesSinceLastPush() (string, error) {
-	// Get current branch
-	branchCmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-	branchOutput, err := branchCmd.Output()
+// getPushedChanges returns the commit the push is compared against and the
+// diff from there to HEAD. It fails when the branch has no upstream.
+func (v *Validator) getPushedChanges() (string, string, error) {
+	baseRef, err := apidiff.BaseRef()
 	if err != nil {
-		return "", fmt.Errorf("failed to get current branch: %w", err)
+		return "", "", err
 	}
-	branch := strings.TrimSpace(string(branchOutput))
 
-	// Get diff since last push (using origin/branch or fallback to last few commits)
-	var diffCmd *exec.Cmd
-	
-	// Try to get diff against origin
-	remoteBranch := fmt.Sprintf("origin/%s", branch)
-	diffCmd = exec.Command("git", "diff", remoteBranch+"..HEAD")
-	output, err := diffCmd.Output()
-	
-	// If no remote, try to get last 3 commits
+	output, err := exec.Command("git", "diff", baseRef+"..HEAD").Output()
 	if err != nil {
-		diffCmd = exec.Command("git", "diff", "HEAD~3..HEAD")
-		output, err = diffCmd.Output()
-		
-		// If still no luck, get all staged and unstaged changes
-		if err != nil {
-			diffCmd = exec.Command("git", "diff", "HEAD")
-			output, err = diffCmd.Output()
-			if err != nil {
-				return "", fmt.Errorf("failed to get git changes: %w", err)
-			}
-		}
+		return "", "", fmt.Errorf("failed to get git changes: %w", err)
 	}
-
-	return string(output), nil
+	return baseRef, string(output), nil
 }
 
 // getRecentCommits gets recent commit messages for context
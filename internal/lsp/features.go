@@ -0,0 +1,270 @@
+package lsp
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"gopkg.in/yaml.v3"
+)
+
+// Statuses in the order they are offered
+var allStatuses = []adr.Status{
+	adr.StatusDraft,
+	adr.StatusInProgress,
+	adr.StatusAccepted,
+	adr.StatusRejected,
+	adr.StatusSuperseded,
+}
+
+var (
+	// refPattern finds ADR references such as ADR-0007
+	refPattern = regexp.MustCompile(`(?i)\bADR-(\d+)\b`)
+	// partialRefPattern finds an ADR reference being typed before the cursor
+	partialRefPattern = regexp.MustCompile(`(?i)\bADR-\d*$`)
+)
+
+// hoverExcerptLength caps the decision text shown on hover
+const hoverExcerptLength = 500
+
+// diagnose returns the problems in a document: front matter and acceptance
+// checks for ADR files, and references to ADRs that do not exist
+func (s *Server) diagnose(uri, text string) []Diagnostic {
+	lines := splitLines(text)
+	diagnostics := []Diagnostic{}
+
+	if s.isADRFile(uri) {
+		diagnostics = append(diagnostics, frontMatterDiagnostics(lines)...)
+
+		for _, issue := range adr.CheckContent(text) {
+			message := "Not ready to accept: " + issue.Message
+			if len(issue.Lines) == 0 {
+				diagnostics = append(diagnostics, Diagnostic{Range: wholeLine(lines, 0), Severity: severityWarning, Source: "drduck", Message: message})
+			}
+			for _, line := range issue.Lines {
+				diagnostics = append(diagnostics, Diagnostic{Range: wholeLine(lines, line), Severity: severityWarning, Source: "drduck", Message: message})
+			}
+		}
+	}
+
+	known := s.knownIDs()
+	for lineNumber, line := range lines {
+		for _, match := range refPattern.FindAllStringSubmatchIndex(line, -1) {
+			id, err := strconv.Atoi(line[match[2]:match[3]])
+			if err != nil || known[id] {
+				continue
+			}
+			diagnostics = append(diagnostics, Diagnostic{
+				Range:    lineRange(lineNumber, line, match[0], match[1]),
+				Severity: severityError,
+				Source:   "drduck",
+				Message:  fmt.Sprintf("ADR-%04d does not exist", id),
+			})
+		}
+	}
+
+	return diagnostics
+}
+
+// frontMatterDiagnostics checks that an ADR starts with valid front matter
+// holding one of the statuses DrDuck understands
+func frontMatterDiagnostics(lines []string) []Diagnostic {
+	diagnostic := func(r Range, severity int, message string) []Diagnostic {
+		return []Diagnostic{{Range: r, Severity: severity, Source: "drduck", Message: message}}
+	}
+
+	if len(lines) == 0 || lines[0] != "---" {
+		return diagnostic(wholeLine(lines, 0), severityError, "ADR files must start with YAML front matter ('---')")
+	}
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if lines[i] == "---" {
+			end = i
+			break
+		}
+	}
+	if end == -1 {
+		return diagnostic(wholeLine(lines, 0), severityError, "ADR front matter must end with '---'")
+	}
+
+	var frontMatter adr.FrontMatter
+	if err := yaml.Unmarshal([]byte(strings.Join(lines[1:end], "\n")), &frontMatter); err != nil {
+		return diagnostic(wholeLine(lines, 0), severityError, fmt.Sprintf("Invalid front matter: %v", err))
+	}
+
+	statusLine := -1
+	for i := 1; i < end; i++ {
+		if strings.HasPrefix(lines[i], "status:") {
+			statusLine = i
+			break
+		}
+	}
+	if statusLine == -1 {
+		return diagnostic(wholeLine(lines, 0), severityError, "Missing status in front matter")
+	}
+
+	status := adr.Status(frontMatter.Status)
+	for _, valid := range allStatuses {
+		if status == valid {
+			return nil
+		}
+	}
+
+	r := statusValueRange(lines[statusLine], statusLine)
+	for _, valid := range allStatuses {
+		if strings.EqualFold(string(status), string(valid)) {
+			return diagnostic(r, severityError, fmt.Sprintf("Status %q is not recognized, use %q", status, valid))
+		}
+	}
+	return diagnostic(r, severityError, fmt.Sprintf("Unknown status %q. Valid statuses: %s", status, statusNames()))
+}
+
+// statusValueRange returns the range of the value on a "status:" line
+func statusValueRange(line string, lineNumber int) Range {
+	start := len("status:")
+	for start < len(line) && line[start] == ' ' {
+		start++
+	}
+	return lineRange(lineNumber, line, start, len(line))
+}
+
+// statusNames lists the valid statuses for messages
+func statusNames() string {
+	names := make([]string, len(allStatuses))
+	for i, status := range allStatuses {
+		names[i] = string(status)
+	}
+	return strings.Join(names, ", ")
+}
+
+// definition returns the file of the ADR referenced at a position
+func (s *Server) definition(lines []string, position Position) any {
+	target, _ := s.referenceAt(lines, position)
+	if target == nil {
+		return nil
+	}
+	return Location{URI: pathToURI(target.FilePath)}
+}
+
+// hover describes the ADR referenced at a position
+func (s *Server) hover(lines []string, position Position) any {
+	target, r := s.referenceAt(lines, position)
+	if target == nil {
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "**ADR-%04d: %s**\n\n", target.ID, target.Title)
+	fmt.Fprintf(&b, "%s · %s", target.Status, target.Date.Format("2006-01-02"))
+	if len(target.Tags) > 0 {
+		fmt.Fprintf(&b, " · %s", strings.Join(target.Tags, ", "))
+	}
+	if decision := strings.TrimSpace(target.Decision); decision != "" {
+		if runes := []rune(decision); len(runes) > hoverExcerptLength {
+			decision = strings.TrimSpace(string(runes[:hoverExcerptLength])) + "…"
+		}
+		fmt.Fprintf(&b, "\n\n%s", decision)
+	}
+
+	return map[string]any{
+		"contents": map[string]any{"kind": "markdown", "value": b.String()},
+		"range":    r,
+	}
+}
+
+// referenceAt returns the existing ADR referenced at a position and the
+// reference's range
+func (s *Server) referenceAt(lines []string, position Position) (*adr.ADR, Range) {
+	line := lines[position.Line]
+	offset := byteOffset(line, position.Character)
+
+	for _, match := range refPattern.FindAllStringSubmatchIndex(line, -1) {
+		if offset < match[0] || offset > match[1] {
+			continue
+		}
+		id, err := strconv.Atoi(line[match[2]:match[3]])
+		if err != nil {
+			return nil, Range{}
+		}
+		target, err := s.adrs.GetADRByID(id)
+		if err != nil {
+			return nil, Range{}
+		}
+		return target, lineRange(position.Line, line, match[0], match[1])
+	}
+	return nil, Range{}
+}
+
+// completion offers statuses on the front matter's status line and ADR IDs
+// after "ADR-"
+func (s *Server) completion(lines []string, position Position) any {
+	line := lines[position.Line]
+	offset := byteOffset(line, position.Character)
+	items := []map[string]any{}
+
+	if inFrontMatter(lines, position.Line) && strings.HasPrefix(line, "status:") && offset >= len("status:") {
+		r := statusValueRange(line, position.Line)
+		for i, status := range allStatuses {
+			items = append(items, map[string]any{
+				"label":    string(status),
+				"kind":     kindEnumMember,
+				"sortText": fmt.Sprintf("%d", i),
+				"textEdit": map[string]any{"range": r, "newText": string(status)},
+			})
+		}
+		return map[string]any{"isIncomplete": false, "items": items}
+	}
+
+	prefix := line[:offset]
+	loc := partialRefPattern.FindStringIndex(prefix)
+	if loc == nil {
+		return map[string]any{"isIncomplete": false, "items": items}
+	}
+
+	adrs, err := s.adrs.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to list ADRs: %v\n", err)
+	}
+	r := lineRange(position.Line, line, loc[0], offset)
+	typed := prefix[loc[0] : loc[0]+len("ADR-")]
+	for _, a := range adrs {
+		label := fmt.Sprintf("%s%04d", typed, a.ID)
+		items = append(items, map[string]any{
+			"label":      label,
+			"kind":       kindReference,
+			"detail":     fmt.Sprintf("%s (%s)", a.Title, a.Status),
+			"filterText": label,
+			"textEdit":   map[string]any{"range": r, "newText": label},
+		})
+	}
+	return map[string]any{"isIncomplete": false, "items": items}
+}
+
+// inFrontMatter reports whether a line is inside the document's front matter
+func inFrontMatter(lines []string, lineNumber int) bool {
+	if len(lines) == 0 || lines[0] != "---" || lineNumber == 0 {
+		return false
+	}
+	for i := 1; i < len(lines); i++ {
+		if lines[i] == "---" {
+			return lineNumber < i
+		}
+	}
+	return true
+}
+
+// knownIDs returns the IDs of the project's ADRs
+func (s *Server) knownIDs() map[int]bool {
+	adrs, err := s.adrs.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to list ADRs: %v\n", err)
+	}
+	known := make(map[int]bool, len(adrs))
+	for _, a := range adrs {
+		known[a.ID] = true
+	}
+	return known
+}
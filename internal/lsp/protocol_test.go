@@ -0,0 +1,106 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMessageFramingRoundTrip(t *testing.T) {
+	messages := []message{
+		{ID: json.RawMessage("1"), Method: "initialize", Params: json.RawMessage(`{}`)},
+		{Method: "textDocument/didOpen", Params: json.RawMessage(`{"text":"Größe 🚀\r\nzweite Zeile"}`)},
+		{ID: json.RawMessage(`"abc"`), Result: json.RawMessage("null")},
+	}
+
+	var buf bytes.Buffer
+	for _, msg := range messages {
+		if err := writeMessage(&buf, msg); err != nil {
+			t.Fatalf("writeMessage: %v", err)
+		}
+	}
+	if !strings.HasPrefix(buf.String(), "Content-Length: ") {
+		t.Fatalf("message is not framed: %q", buf.String())
+	}
+
+	reader := bufio.NewReader(&buf)
+	for _, want := range messages {
+		body, err := readMessage(reader)
+		if err != nil {
+			t.Fatalf("readMessage: %v", err)
+		}
+		var got message
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("invalid body %q: %v", body, err)
+		}
+		if string(got.ID) != string(want.ID) || got.Method != want.Method || string(got.Params) != string(want.Params) || string(got.Result) != string(want.Result) {
+			t.Errorf("read %+v, want %+v", got, want)
+		}
+		if got.JSONRPC != "2.0" {
+			t.Errorf("jsonrpc = %q", got.JSONRPC)
+		}
+	}
+	if _, err := readMessage(reader); err == nil {
+		t.Error("expected EOF after the last message")
+	}
+}
+
+func TestReadMessage(t *testing.T) {
+	// Content-Length counts bytes, not characters
+	body := `{"text":"ü"}`
+	input := "content-length: 13\r\nContent-Type: application/vscode-jsonrpc; charset=utf-8\r\n\r\n" + body
+	got, err := readMessage(bufio.NewReader(strings.NewReader(input)))
+	if err != nil || string(got) != body {
+		t.Errorf("readMessage = %q, %v, want %q", got, err, body)
+	}
+
+	for name, input := range map[string]string{
+		"missing length":   "Content-Type: application/json\r\n\r\n{}",
+		"invalid length":   "Content-Length: twelve\r\n\r\n{}",
+		"truncated body":   "Content-Length: 10\r\n\r\n{}",
+		"truncated header": "Content-Length: 2\r\n",
+	} {
+		if _, err := readMessage(bufio.NewReader(strings.NewReader(input))); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestUTF16Positions(t *testing.T) {
+	line := "Größe 🚀 folgt ADR-0001."
+
+	if got := utf16Len(line); got != 24 {
+		t.Errorf("utf16Len = %d, want 24", got)
+	}
+
+	// The emoji is two UTF-16 code units but four bytes
+	tests := []struct {
+		character int
+		byteOff   int
+	}{
+		{0, 0},
+		{2, 2},  // ö is one unit, two bytes
+		{6, 8},  // Before the emoji
+		{8, 12}, // After it
+		{15, 19},
+		{100, len(line)},
+	}
+	for _, tt := range tests {
+		if got := byteOffset(line, tt.character); got != tt.byteOff {
+			t.Errorf("byteOffset(%d) = %d, want %d", tt.character, got, tt.byteOff)
+		}
+	}
+
+	start := strings.Index(line, "ADR-0001")
+	want := Range{Start: Position{Line: 3, Character: 15}, End: Position{Line: 3, Character: 23}}
+	if got := lineRange(3, line, start, start+len("ADR-0001")); got != want {
+		t.Errorf("lineRange = %+v, want %+v", got, want)
+	}
+
+	// Invalid bytes count as one U+FFFD each, like in the client
+	if got := utf16Len(validUTF8("a\xffb")); got != 3 {
+		t.Errorf("utf16Len of invalid UTF-8 = %d, want 3", got)
+	}
+}
@@ -0,0 +1,287 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/config"
+)
+
+// Server is a Language Server Protocol server for ADR markdown files. It
+// reports the problems 'drduck accept' would find while the file is edited
+// and resolves ADR-NNNN references.
+type Server struct {
+	adrs    *adr.Manager
+	version string
+	adrDir  string // Absolute path of the ADR directory
+
+	documents   map[string]string // Open documents by URI
+	initialized bool
+	shutdown    bool
+	out         io.Writer
+}
+
+// NewServer creates a language server for the project
+func NewServer(cfg *config.Config, version string) *Server {
+	adrs := adr.NewManager(cfg)
+	adrDir, err := filepath.Abs(adrs.Dir())
+	if err != nil {
+		adrDir = adrs.Dir()
+	}
+
+	return &Server{
+		adrs:      adrs,
+		version:   version,
+		adrDir:    adrDir,
+		documents: make(map[string]string),
+	}
+}
+
+// Serve reads messages from in and writes responses and diagnostics to out
+// until the client sends exit, in is closed or the context is cancelled
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	s.out = out
+	reader := bufio.NewReader(in)
+
+	for ctx.Err() == nil {
+		body, err := readMessage(reader)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read LSP message: %w", err)
+		}
+
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			s.respond(json.RawMessage("null"), nil, &rpcError{Code: codeParseError, Message: fmt.Sprintf("invalid JSON: %v", err)})
+			continue
+		}
+		if msg.Method == "" {
+			continue // A response to a request we never send
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return fmt.Errorf("client exited without shutting down the server")
+			}
+			return nil
+		}
+
+		result, rpcErr := s.dispatch(msg)
+		if len(msg.ID) > 0 {
+			s.respond(msg.ID, result, rpcErr)
+		}
+	}
+	return nil
+}
+
+// dispatch runs the method named in a message
+func (s *Server) dispatch(msg message) (any, *rpcError) {
+	if !s.initialized && msg.Method != "initialize" {
+		return nil, &rpcError{Code: codeServerNotStarted, Message: "server not initialized"}
+	}
+	if s.shutdown {
+		return nil, &rpcError{Code: codeInvalidRequest, Message: "server is shutting down"}
+	}
+
+	switch msg.Method {
+	case "initialize":
+		s.initialized = true
+		return s.initialize(), nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var p struct {
+			TextDocument struct {
+				URI  string `json:"uri"`
+				Text string `json:"text"`
+			} `json:"textDocument"`
+		}
+		if err := json.Unmarshal(msg.Params, &p); err != nil {
+			return nil, invalidParams(err)
+		}
+		s.documents[p.TextDocument.URI] = validUTF8(p.TextDocument.Text)
+		s.publishDiagnostics(p.TextDocument.URI)
+		return nil, nil
+
+	case "textDocument/didChange":
+		var p struct {
+			TextDocument struct {
+				URI string `json:"uri"`
+			} `json:"textDocument"`
+			ContentChanges []struct {
+				Text string `json:"text"`
+			} `json:"contentChanges"`
+		}
+		if err := json.Unmarshal(msg.Params, &p); err != nil {
+			return nil, invalidParams(err)
+		}
+		// The server asks for full document sync, so the last change is the whole text
+		if len(p.ContentChanges) > 0 {
+			s.documents[p.TextDocument.URI] = validUTF8(p.ContentChanges[len(p.ContentChanges)-1].Text)
+		}
+		s.publishDiagnostics(p.TextDocument.URI)
+		return nil, nil
+
+	case "textDocument/didSave":
+		// A saved ADR can fix or break references in every open document
+		for uri := range s.documents {
+			s.publishDiagnostics(uri)
+		}
+		return nil, nil
+
+	case "textDocument/didClose":
+		var p textDocumentPosition
+		if err := json.Unmarshal(msg.Params, &p); err != nil {
+			return nil, invalidParams(err)
+		}
+		delete(s.documents, p.TextDocument.URI)
+		s.notify("textDocument/publishDiagnostics", map[string]any{
+			"uri":         p.TextDocument.URI,
+			"diagnostics": []Diagnostic{},
+		})
+		return nil, nil
+
+	case "textDocument/definition", "textDocument/hover", "textDocument/completion":
+		var p textDocumentPosition
+		if err := json.Unmarshal(msg.Params, &p); err != nil {
+			return nil, invalidParams(err)
+		}
+		text, ok := s.documents[p.TextDocument.URI]
+		if !ok {
+			return nil, nil
+		}
+		lines := splitLines(text)
+		if p.Position.Line < 0 || p.Position.Line >= len(lines) {
+			return nil, nil
+		}
+
+		switch msg.Method {
+		case "textDocument/definition":
+			return s.definition(lines, p.Position), nil
+		case "textDocument/hover":
+			return s.hover(lines, p.Position), nil
+		default:
+			return s.completion(lines, p.Position), nil
+		}
+
+	default:
+		if strings.HasPrefix(msg.Method, "$/") || len(msg.ID) == 0 {
+			return nil, nil // Optional notifications can be ignored
+		}
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %s", msg.Method)}
+	}
+}
+
+// initialize announces the server's capabilities
+func (s *Server) initialize() map[string]any {
+	return map[string]any{
+		"capabilities": map[string]any{
+			"textDocumentSync": map[string]any{
+				"openClose": true,
+				"change":    1, // Full
+				"save":      map[string]any{"includeText": false},
+			},
+			"definitionProvider": true,
+			"hoverProvider":      true,
+			"completionProvider": map[string]any{
+				"triggerCharacters": []string{"-", ":", " "},
+			},
+		},
+		"serverInfo": map[string]any{
+			"name":    "drduck",
+			"version": s.version,
+		},
+	}
+}
+
+// publishDiagnostics sends the problems found in an open document
+func (s *Server) publishDiagnostics(uri string) {
+	text, ok := s.documents[uri]
+	if !ok {
+		return
+	}
+	s.notify("textDocument/publishDiagnostics", map[string]any{
+		"uri":         uri,
+		"diagnostics": s.diagnose(uri, text),
+	})
+}
+
+// respond sends the response to a request
+func (s *Server) respond(id json.RawMessage, result any, rpcErr *rpcError) {
+	msg := message{ID: id, Error: rpcErr}
+	if rpcErr == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			msg.Error = &rpcError{Code: codeInvalidRequest, Message: fmt.Sprintf("failed to encode result: %v", err)}
+		} else {
+			msg.Result = data
+		}
+	}
+	s.write(msg)
+}
+
+// notify sends a notification to the client
+func (s *Server) notify(method string, params any) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to encode %s: %v\n", method, err)
+		return
+	}
+	s.write(message{Method: method, Params: data})
+}
+
+// write sends a message, logging failures as there is nobody to return them to
+func (s *Server) write(msg message) {
+	if err := writeMessage(s.out, msg); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to write LSP message: %v\n", err)
+	}
+}
+
+// isADRFile reports whether a document is one of the project's ADRs
+func (s *Server) isADRFile(uri string) bool {
+	path, err := uriToPath(uri)
+	if err != nil || filepath.Dir(path) != s.adrDir {
+		return false
+	}
+
+	// Same naming rule as adr.Manager.List
+	name := filepath.Base(path)
+	if !strings.HasSuffix(name, ".md") || name == "README.md" {
+		return false
+	}
+	prefix, _, ok := strings.Cut(name, "-")
+	if !ok {
+		return false
+	}
+	_, err = strconv.Atoi(prefix)
+	return err == nil
+}
+
+// invalidParams returns the error for malformed request parameters
+func invalidParams(err error) *rpcError {
+	return &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("invalid params: %v", err)}
+}
+
+// splitLines splits a document into lines without their line endings
+func splitLines(text string) []string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
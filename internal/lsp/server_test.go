@@ -0,0 +1,264 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/config"
+)
+
+// brokenADR has a miscased status, an empty Decision section and a reference
+// to a missing ADR after non-ASCII text
+const brokenADR = `---
+id: 2
+title: Größere Caches
+status: accepted
+date: 2025-01-01
+---
+# Größere Caches
+
+## Context
+
+Wir brauchen 🚀 schnellere Antworten für Größe, siehe ADR-0042.
+
+## Decision
+
+TBD
+
+## Rationale
+
+Weil die Latenz für Nutzer zählt und Speicher günstig ist.
+`
+
+// session frames requests and notifications for a server and decodes what it sends back
+type session struct {
+	t      *testing.T
+	input  bytes.Buffer
+	nextID int
+}
+
+func (s *session) request(method string, params any) {
+	s.nextID++
+	s.send(json.RawMessage(strconv.Itoa(s.nextID)), method, params)
+}
+
+func (s *session) notify(method string, params any) {
+	s.send(nil, method, params)
+}
+
+func (s *session) send(id json.RawMessage, method string, params any) {
+	s.t.Helper()
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	if err := writeMessage(&s.input, message{ID: id, Method: method, Params: data}); err != nil {
+		s.t.Fatal(err)
+	}
+}
+
+// run serves the queued messages and returns the responses by ID and the
+// notifications in order
+func (s *session) run(server *Server) (map[string]message, []message, error) {
+	s.t.Helper()
+
+	var out bytes.Buffer
+	err := server.Serve(context.Background(), &s.input, &out)
+
+	responses := make(map[string]message)
+	var notifications []message
+	reader := bufio.NewReader(&out)
+	for {
+		body, readErr := readMessage(reader)
+		if readErr != nil {
+			break
+		}
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			s.t.Fatalf("invalid message %q: %v", body, err)
+		}
+		if msg.Method != "" {
+			notifications = append(notifications, msg)
+		} else {
+			responses[string(msg.ID)] = msg
+		}
+	}
+	return responses, notifications, err
+}
+
+// newProject creates a project with one ADR, returning a server for it
+func newProject(t *testing.T) *Server {
+	t.Helper()
+
+	t.Chdir(t.TempDir())
+	cfg := config.DefaultConfig()
+	manager := adr.NewManager(cfg)
+	created, err := manager.Create("Use Redis for caching")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.FillSections(created, map[string]string{"Decision": "Cache sessions in Redis with a one hour TTL."}); err != nil {
+		t.Fatal(err)
+	}
+	return NewServer(cfg, "dev")
+}
+
+func TestDiagnosticsOfBrokenADR(t *testing.T) {
+	server := newProject(t)
+	uri := pathToURI(filepath.Join(server.adrDir, "0002-groessere-caches.md"))
+
+	s := &session{t: t}
+	s.request("initialize", map[string]any{})
+	s.notify("initialized", map[string]any{})
+	s.notify("textDocument/didOpen", map[string]any{"textDocument": map[string]any{"uri": uri, "text": brokenADR}})
+	s.request("shutdown", nil)
+	s.notify("exit", nil)
+
+	responses, notifications, err := s.run(server)
+	if err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+	if len(responses) != 2 || responses["1"].Error != nil || responses["2"].Error != nil {
+		t.Fatalf("responses = %+v", responses)
+	}
+	if len(notifications) != 1 || notifications[0].Method != "textDocument/publishDiagnostics" {
+		t.Fatalf("notifications = %+v", notifications)
+	}
+
+	var published struct {
+		URI         string       `json:"uri"`
+		Diagnostics []Diagnostic `json:"diagnostics"`
+	}
+	if err := json.Unmarshal(notifications[0].Params, &published); err != nil {
+		t.Fatal(err)
+	}
+	if published.URI != uri {
+		t.Errorf("diagnostics for %s, want %s", published.URI, uri)
+	}
+
+	want := []Diagnostic{
+		{
+			Range:    Range{Start: Position{Line: 3, Character: 8}, End: Position{Line: 3, Character: 16}},
+			Severity: severityError,
+			Source:   "drduck",
+			Message:  `Status "accepted" is not recognized, use "Accepted"`,
+		},
+		{
+			Range:    Range{Start: Position{Line: 12, Character: 0}, End: Position{Line: 12, Character: 11}},
+			Severity: severityWarning,
+			Source:   "drduck",
+			Message:  "Not ready to accept: Decision section needs more content",
+		},
+		{
+			// The emoji counts as two UTF-16 units and each umlaut as one
+			Range:    Range{Start: Position{Line: 10, Character: 54}, End: Position{Line: 10, Character: 62}},
+			Severity: severityError,
+			Source:   "drduck",
+			Message:  "ADR-0042 does not exist",
+		},
+	}
+	if len(published.Diagnostics) != len(want) {
+		t.Fatalf("diagnostics = %+v, want %+v", published.Diagnostics, want)
+	}
+	for i := range want {
+		if published.Diagnostics[i] != want[i] {
+			t.Errorf("diagnostic %d = %+v, want %+v", i, published.Diagnostics[i], want[i])
+		}
+	}
+}
+
+func TestReferencesAfterNonASCIIText(t *testing.T) {
+	server := newProject(t)
+	uri := pathToURI(filepath.Join(t.TempDir(), "notes.md"))
+	text := "Notizen\nGröße 🚀 folgt ADR-0001 und ADR-"
+	reference := Position{Line: 1, Character: 17} // Inside "ADR-0001", which starts at 15
+
+	s := &session{t: t}
+	s.request("hover", nil) // Before initialize
+	s.request("initialize", map[string]any{})
+	s.notify("textDocument/didOpen", map[string]any{"textDocument": map[string]any{"uri": uri, "text": text}})
+	document := map[string]any{"uri": uri}
+	s.request("textDocument/hover", map[string]any{"textDocument": document, "position": reference})
+	s.request("textDocument/definition", map[string]any{"textDocument": document, "position": reference})
+	s.request("textDocument/completion", map[string]any{"textDocument": document, "position": Position{Line: 1, Character: 32}})
+	s.request("textDocument/hover", map[string]any{"textDocument": document, "position": Position{Line: 1, Character: 3}})
+	s.request("workspace/symbol", map[string]any{})
+
+	responses, notifications, err := s.run(server)
+	if err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+
+	if resp := responses["1"]; resp.Error == nil || resp.Error.Code != codeServerNotStarted {
+		t.Errorf("request before initialize = %+v", resp)
+	}
+
+	// Notes are not ADRs, so only references are checked, and they exist
+	var published struct {
+		Diagnostics []Diagnostic `json:"diagnostics"`
+	}
+	if len(notifications) != 1 || json.Unmarshal(notifications[0].Params, &published) != nil || len(published.Diagnostics) != 0 {
+		t.Errorf("notifications = %+v", notifications)
+	}
+
+	var hover struct {
+		Contents struct {
+			Value string `json:"value"`
+		} `json:"contents"`
+		Range Range `json:"range"`
+	}
+	if err := json.Unmarshal(responses["3"].Result, &hover); err != nil {
+		t.Fatalf("hover = %s: %v", responses["3"].Result, err)
+	}
+	wantRange := Range{Start: Position{Line: 1, Character: 15}, End: Position{Line: 1, Character: 23}}
+	if hover.Range != wantRange || !strings.Contains(hover.Contents.Value, "ADR-0001: Use Redis for caching") || !strings.Contains(hover.Contents.Value, "one hour TTL") {
+		t.Errorf("hover = %+v", hover)
+	}
+
+	var location Location
+	if err := json.Unmarshal(responses["4"].Result, &location); err != nil || !strings.HasSuffix(location.URI, "/0001-use-redis-for-caching.md") {
+		t.Errorf("definition = %s", responses["4"].Result)
+	}
+
+	var completion struct {
+		Items []struct {
+			Label    string `json:"label"`
+			TextEdit struct {
+				Range Range `json:"range"`
+			} `json:"textEdit"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(responses["5"].Result, &completion); err != nil || len(completion.Items) != 1 {
+		t.Fatalf("completion = %s", responses["5"].Result)
+	}
+	wantEdit := Range{Start: Position{Line: 1, Character: 28}, End: Position{Line: 1, Character: 32}}
+	if completion.Items[0].Label != "ADR-0001" || completion.Items[0].TextEdit.Range != wantEdit {
+		t.Errorf("completion item = %+v", completion.Items[0])
+	}
+
+	if string(responses["6"].Result) != "null" {
+		t.Errorf("hover outside a reference = %s, want null", responses["6"].Result)
+	}
+	if resp := responses["7"]; resp.Error == nil || resp.Error.Code != codeMethodNotFound {
+		t.Errorf("unknown method = %+v", resp)
+	}
+}
+
+func TestExitWithoutShutdown(t *testing.T) {
+	server := newProject(t)
+
+	s := &session{t: t}
+	s.request("initialize", map[string]any{})
+	s.notify("exit", nil)
+	if _, _, err := s.run(server); err == nil {
+		t.Error("expected an error when the client exits without shutdown")
+	}
+}
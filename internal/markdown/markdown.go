@@ -0,0 +1,124 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+// BlockKind identifies a block of a Markdown document
+type BlockKind int
+
+const (
+	Paragraph BlockKind = iota
+	Heading
+	List
+	Quote
+	Code
+	Rule
+)
+
+// Block is a block of the subset of Markdown used in ADRs
+type Block struct {
+	Kind    BlockKind
+	Level   int      // Heading level, 1 to 6
+	Text    string   // Paragraph and heading text, without Markdown block syntax
+	Ordered bool     // Whether a list is numbered
+	Items   []Item   // List items
+	Lines   []string // Code block lines
+	Blocks  []Block  // Quoted blocks
+}
+
+// Item is a list item
+type Item struct {
+	Indent int // Leading spaces, for nested lists
+	Text   string
+}
+
+var (
+	commentPattern   = regexp.MustCompile(`(?s)<!--.*?-->`)
+	headingPattern   = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	unorderedPattern = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	orderedPattern   = regexp.MustCompile(`^(\s*)\d+[.)]\s+(.*)$`)
+	rulePattern      = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+)
+
+// Parse splits Markdown into blocks: headings, paragraphs, lists, block
+// quotes, code blocks and rules. HTML comments, such as the template
+// placeholders, are dropped. Inline syntax is left in the text.
+func Parse(source string) []Block {
+	source = commentPattern.ReplaceAllString(source, "")
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+
+	var blocks []Block
+	var paragraph []string
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, Block{Kind: Paragraph, Text: strings.Join(paragraph, " ")})
+			paragraph = nil
+		}
+	}
+
+	// A list goes on while the next line is an item of the same kind
+	inList := false
+	addItem := func(ordered bool, match []string) {
+		flushParagraph()
+		item := Item{Indent: len(match[1]), Text: match[2]}
+		if last := len(blocks) - 1; inList && blocks[last].Ordered == ordered {
+			blocks[last].Items = append(blocks[last].Items, item)
+		} else {
+			blocks = append(blocks, Block{Kind: List, Ordered: ordered, Items: []Item{item}})
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		itemLine := false
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flushParagraph()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			blocks = append(blocks, Block{Kind: Code, Lines: code})
+
+		case trimmed == "":
+			flushParagraph()
+
+		case headingPattern.MatchString(trimmed):
+			flushParagraph()
+			match := headingPattern.FindStringSubmatch(trimmed)
+			blocks = append(blocks, Block{Kind: Heading, Level: len(match[1]), Text: match[2]})
+
+		case rulePattern.MatchString(trimmed):
+			flushParagraph()
+			blocks = append(blocks, Block{Kind: Rule})
+
+		case strings.HasPrefix(trimmed, ">"):
+			flushParagraph()
+			var quote []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				quote = append(quote, strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">"), " "))
+			}
+			i--
+			blocks = append(blocks, Block{Kind: Quote, Blocks: Parse(strings.Join(quote, "\n"))})
+
+		case unorderedPattern.MatchString(line):
+			addItem(false, unorderedPattern.FindStringSubmatch(line))
+			itemLine = true
+
+		case orderedPattern.MatchString(line):
+			addItem(true, orderedPattern.FindStringSubmatch(line))
+			itemLine = true
+
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+		inList = itemLine
+	}
+	flushParagraph()
+
+	return blocks
+}
@@ -0,0 +1,62 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []Block
+	}{
+		{
+			name:   "headings and paragraphs",
+			source: "# Use Redis\n\n## Context\nWe need\na cache.\n",
+			want: []Block{
+				{Kind: Heading, Level: 1, Text: "Use Redis"},
+				{Kind: Heading, Level: 2, Text: "Context"},
+				{Kind: Paragraph, Text: "We need a cache."},
+			},
+		},
+		{
+			name:   "placeholders dropped",
+			source: "## Decision\n<!-- What is the change\nthat we propose? -->\n",
+			want:   []Block{{Kind: Heading, Level: 2, Text: "Decision"}},
+		},
+		{
+			name:   "lists of each kind",
+			source: "- one\n  - nested\n1. first\n2. second\n\n- again\n",
+			want: []Block{
+				{Kind: List, Items: []Item{{Text: "one"}, {Indent: 2, Text: "nested"}}},
+				{Kind: List, Ordered: true, Items: []Item{{Text: "first"}, {Text: "second"}}},
+				{Kind: List, Items: []Item{{Text: "again"}}},
+			},
+		},
+		{
+			name:   "code is kept verbatim",
+			source: "```go\n# not a heading\n- not an item\n```\n",
+			want:   []Block{{Kind: Code, Lines: []string{"# not a heading", "- not an item"}}},
+		},
+		{
+			name:   "quotes and rules",
+			source: "> **Note**\n> - quoted item\n\n---\n",
+			want: []Block{
+				{Kind: Quote, Blocks: []Block{
+					{Kind: Paragraph, Text: "**Note**"},
+					{Kind: List, Items: []Item{{Text: "quoted item"}}},
+				}},
+				{Kind: Rule},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.source); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
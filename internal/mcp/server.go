@@ -0,0 +1,186 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/config"
+)
+
+// Protocol versions this server speaks, newest first
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// request is a JSON-RPC request, or a notification when ID is empty
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// response is a JSON-RPC response
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC error
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Server is a Model Context Protocol server that lets AI coding assistants
+// read and write the project's ADRs through tools
+type Server struct {
+	config  *config.Config
+	adrs    *adr.Manager
+	version string
+	tools   []tool
+
+	mu  sync.Mutex // Serializes writes to out
+	out io.Writer
+}
+
+// NewServer creates an MCP server for the project
+func NewServer(cfg *config.Config, version string) *Server {
+	s := &Server{
+		config:  cfg,
+		adrs:    adr.NewManager(cfg),
+		version: version,
+	}
+	s.tools = s.buildTools()
+	return s
+}
+
+// Serve reads newline-delimited JSON-RPC messages from in and writes the
+// responses to out until in is closed or the context is cancelled
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	s.out = out
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return nil
+		}
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		s.handle(ctx, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read MCP messages: %w", err)
+	}
+	return nil
+}
+
+// handle answers one message. Notifications get no response.
+func (s *Server) handle(ctx context.Context, message []byte) {
+	var req request
+	if err := json.Unmarshal(message, &req); err != nil {
+		s.write(response{ID: json.RawMessage("null"), Error: &rpcError{Code: codeParseError, Message: fmt.Sprintf("invalid JSON: %v", err)}})
+		return
+	}
+	isNotification := len(req.ID) == 0
+
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		if !isNotification {
+			s.write(response{ID: req.ID, Error: &rpcError{Code: codeInvalidRequest, Message: "expected a JSON-RPC 2.0 request"}})
+		}
+		return
+	}
+
+	result, rpcErr := s.dispatch(ctx, req)
+	if isNotification {
+		return
+	}
+	if rpcErr != nil {
+		s.write(response{ID: req.ID, Error: rpcErr})
+		return
+	}
+	s.write(response{ID: req.ID, Result: result})
+}
+
+// dispatch runs the method named in a request
+func (s *Server) dispatch(ctx context.Context, req request) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return s.initialize(req.Params)
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		return map[string]any{"tools": s.tools}, nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	case "notifications/initialized", "notifications/cancelled":
+		return nil, nil
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+	}
+}
+
+// initialize negotiates the protocol version and announces the tools capability
+func (s *Server) initialize(params json.RawMessage) (any, *rpcError) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("invalid initialize params: %v", err)}
+		}
+	}
+
+	// Answer with the client's version if we speak it, our newest otherwise
+	version := protocolVersions[0]
+	for _, supported := range protocolVersions {
+		if p.ProtocolVersion == supported {
+			version = supported
+		}
+	}
+
+	return map[string]any{
+		"protocolVersion": version,
+		"capabilities": map[string]any{
+			"tools": map[string]any{"listChanged": false},
+		},
+		"serverInfo": map[string]any{
+			"name":    "drduck",
+			"version": s.version,
+		},
+		"instructions": "DrDuck manages this project's Architecture Decision Records (ADRs). " +
+			"Before making an architectural change, search existing ADRs and respect accepted ones. " +
+			"When a change introduces a significant decision, check whether it needs an ADR and write a draft.",
+	}, nil
+}
+
+// write sends a response as one line of JSON
+func (s *Server) write(resp response) {
+	resp.JSONRPC = "2.0"
+	data, err := json.Marshal(resp)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to encode MCP response: %v\n", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.out.Write(append(data, '\n'))
+}
@@ -0,0 +1,315 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/config"
+)
+
+// rpcResponse is a decoded JSON-RPC response
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result"`
+	Error   *rpcError       `json:"error"`
+}
+
+// exchange sends newline-delimited messages to the server and decodes its responses
+func exchange(t *testing.T, server *Server, messages ...string) []rpcResponse {
+	t.Helper()
+
+	var out bytes.Buffer
+	if err := server.Serve(context.Background(), strings.NewReader(strings.Join(messages, "\n")+"\n"), &out); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+
+	var responses []rpcResponse
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var resp rpcResponse
+		if err := decoder.Decode(&resp); err != nil {
+			t.Fatalf("invalid response: %v\n%s", err, out.String())
+		}
+		if resp.JSONRPC != "2.0" {
+			t.Errorf("response is not JSON-RPC 2.0: %+v", resp)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func TestDispatch(t *testing.T) {
+	server := NewServer(config.DefaultConfig(), "1.2.3")
+
+	responses := exchange(t, server,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"ping"}`,
+		`{"jsonrpc":"2.0","id":"four","method":"resources/list"}`,
+		`{"jsonrpc":"2.0","method":"resources/list"}`,
+		`{"id":5,"method":"ping"}`,
+		`{not json`,
+		`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"delete_everything"}}`,
+		`{"jsonrpc":"2.0","id":7,"method":"initialize","params":"2025-06-18"}`,
+	)
+
+	// Notifications, including unknown ones, get no response
+	wantIDs := []string{"1", "2", "3", `"four"`, "5", "null", "6", "7"}
+	if len(responses) != len(wantIDs) {
+		t.Fatalf("got %d responses, want %d: %+v", len(responses), len(wantIDs), responses)
+	}
+	for i, resp := range responses {
+		if string(resp.ID) != wantIDs[i] {
+			t.Errorf("response %d has ID %s, want %s", i, resp.ID, wantIDs[i])
+		}
+	}
+
+	var initialized struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		Capabilities    struct {
+			Tools map[string]any `json:"tools"`
+		} `json:"capabilities"`
+		ServerInfo struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"serverInfo"`
+	}
+	if err := json.Unmarshal(responses[0].Result, &initialized); err != nil {
+		t.Fatal(err)
+	}
+	if initialized.ProtocolVersion != "2024-11-05" || initialized.Capabilities.Tools == nil || initialized.ServerInfo.Version != "1.2.3" {
+		t.Errorf("initialize = %s", responses[0].Result)
+	}
+	if err := json.Unmarshal(responses[1].Result, &initialized); err != nil || initialized.ProtocolVersion != protocolVersions[0] {
+		t.Errorf("unsupported version negotiated %s, want %s", initialized.ProtocolVersion, protocolVersions[0])
+	}
+	if string(responses[2].Result) != "{}" {
+		t.Errorf("ping = %s, want {}", responses[2].Result)
+	}
+
+	wantCodes := map[int]int{3: codeMethodNotFound, 4: codeInvalidRequest, 5: codeParseError, 6: codeInvalidParams, 7: codeInvalidParams}
+	for i, code := range wantCodes {
+		if responses[i].Error == nil || responses[i].Error.Code != code {
+			t.Errorf("response %d error = %+v, want code %d", i, responses[i].Error, code)
+		}
+	}
+}
+
+func TestToolsList(t *testing.T) {
+	server := NewServer(config.DefaultConfig(), "dev")
+	responses := exchange(t, server, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+
+	var result struct {
+		Tools []struct {
+			Name        string         `json:"name"`
+			Description string         `json:"description"`
+			InputSchema map[string]any `json:"inputSchema"`
+			Annotations map[string]any `json:"annotations"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(responses[0].Result, &result); err != nil {
+		t.Fatal(err)
+	}
+
+	readOnly := map[string]bool{
+		"list_adrs":              true,
+		"get_adr":                true,
+		"search_adrs":            true,
+		"create_adr_draft":       false,
+		"check_changes_need_adr": false,
+	}
+	if len(result.Tools) != len(readOnly) {
+		t.Errorf("got %d tools, want %d", len(result.Tools), len(readOnly))
+	}
+	for _, tool := range result.Tools {
+		want, known := readOnly[tool.Name]
+		if !known {
+			t.Errorf("unexpected tool %s", tool.Name)
+			continue
+		}
+		if tool.Description == "" || tool.InputSchema["type"] != "object" {
+			t.Errorf("%s has no description or object schema", tool.Name)
+		}
+		if got := tool.Annotations["readOnlyHint"] == true; got != want {
+			t.Errorf("%s readOnlyHint = %v, want %v", tool.Name, got, want)
+		}
+	}
+}
+
+// toolCall is the decoded result of a tools/call request
+type toolCall struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	IsError bool `json:"isError"`
+}
+
+// callTool calls a tool and returns its text and whether it reported an error
+func callTool(t *testing.T, server *Server, name string, args any) (string, bool) {
+	t.Helper()
+
+	params, err := json.Marshal(map[string]any{"name": name, "arguments": args})
+	if err != nil {
+		t.Fatal(err)
+	}
+	responses := exchange(t, server, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":`+string(params)+`}`)
+	if len(responses) != 1 || responses[0].Error != nil {
+		t.Fatalf("tools/call %s = %+v", name, responses)
+	}
+
+	var result toolCall
+	if err := json.Unmarshal(responses[0].Result, &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Content) != 1 || result.Content[0].Type != "text" {
+		t.Fatalf("tools/call %s content = %+v", name, result.Content)
+	}
+	return result.Content[0].Text, result.IsError
+}
+
+// newProject creates a project with an accepted ADR and a draft, returning its server
+func newProject(t *testing.T) *Server {
+	t.Helper()
+
+	t.Chdir(t.TempDir())
+	cfg := config.DefaultConfig()
+	manager := adr.NewManager(cfg)
+
+	accepted, err := manager.Create("Use PostgreSQL for persistence")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.FillSections(accepted, map[string]string{"Decision": "We store orders in PostgreSQL with a connection pool."}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.ChangeStatus(accepted, adr.StatusAccepted, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.Create("Use Redis for caching"); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewServer(cfg, "dev")
+}
+
+func TestListAndGetADR(t *testing.T) {
+	server := newProject(t)
+
+	text, isError := callTool(t, server, "list_adrs", nil)
+	var all []adrSummary
+	if err := json.Unmarshal([]byte(text), &all); isError || err != nil || len(all) != 2 {
+		t.Fatalf("list_adrs = %s", text)
+	}
+
+	text, _ = callTool(t, server, "list_adrs", map[string]string{"status": "accepted"})
+	var accepted []adrSummary
+	if err := json.Unmarshal([]byte(text), &accepted); err != nil || len(accepted) != 1 || accepted[0].Title != "Use PostgreSQL for persistence" {
+		t.Errorf("list_adrs status:accepted = %s", text)
+	}
+	if text, _ := callTool(t, server, "list_adrs", map[string]string{"tag": "nothing"}); strings.TrimSpace(text) != "[]" {
+		t.Errorf("list_adrs with an unknown tag = %s, want []", text)
+	}
+
+	for _, id := range []any{1, "0001"} {
+		text, isError := callTool(t, server, "get_adr", map[string]any{"id": id})
+		if isError || !strings.Contains(text, "Use PostgreSQL for persistence") || !strings.Contains(text, "connection pool") {
+			t.Errorf("get_adr %v = %s", id, text)
+		}
+	}
+	for _, id := range []any{"abc", 42} {
+		if text, isError := callTool(t, server, "get_adr", map[string]any{"id": id}); !isError {
+			t.Errorf("get_adr %v = %s, want an error", id, text)
+		}
+	}
+}
+
+func TestSearchADRs(t *testing.T) {
+	server := newProject(t)
+
+	text, isError := callTool(t, server, "search_adrs", map[string]any{"query": "postgresql pool", "limit": 1})
+	var matches []struct {
+		ID       int      `json:"id"`
+		Excerpts []string `json:"excerpts"`
+	}
+	if err := json.Unmarshal([]byte(text), &matches); isError || err != nil || len(matches) != 1 || matches[0].ID != 1 {
+		t.Fatalf("search_adrs = %s", text)
+	}
+
+	if text, _ := callTool(t, server, "search_adrs", map[string]any{"query": "postgresql status:draft"}); strings.TrimSpace(text) != "[]" {
+		t.Errorf("search_adrs with a status filter = %s, want []", text)
+	}
+	if text, isError := callTool(t, server, "search_adrs", map[string]any{"query": "after:yesterday"}); !isError {
+		t.Errorf("search_adrs with a malformed date = %s, want an error", text)
+	}
+}
+
+func TestCreateADRDraft(t *testing.T) {
+	server := newProject(t)
+
+	text, isError := callTool(t, server, "create_adr_draft", map[string]any{
+		"title":    "Use Redis for session storage",
+		"sections": map[string]string{"Context": "Sessions are lost on every deploy."},
+	})
+	var result struct {
+		Created adrSummary   `json:"created"`
+		Similar []adrSummary `json:"similar_existing_adrs"`
+	}
+	if err := json.Unmarshal([]byte(text), &result); isError || err != nil {
+		t.Fatalf("create_adr_draft = %s", text)
+	}
+	if result.Created.ID != 3 || result.Created.Status != string(adr.StatusDraft) {
+		t.Errorf("created = %+v", result.Created)
+	}
+
+	content, err := os.ReadFile(result.Created.Path)
+	if err != nil || !strings.Contains(string(content), "Sessions are lost on every deploy.") {
+		t.Errorf("draft content = %s, %v", content, err)
+	}
+
+	if text, isError := callTool(t, server, "create_adr_draft", map[string]any{"title": "  "}); !isError {
+		t.Errorf("create_adr_draft without a title = %s, want an error", text)
+	}
+}
+
+func TestCheckChangesNeedADR(t *testing.T) {
+	server := newProject(t)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	if output, err := exec.Command("git", "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, output)
+	}
+
+	var answer struct {
+		Hook       string       `json:"hook"`
+		WouldBlock bool         `json:"would_block"`
+		DraftADRs  []adrSummary `json:"draft_adrs"`
+	}
+
+	// The draft blocks a push before any change is analyzed
+	text, isError := callTool(t, server, "check_changes_need_adr", nil)
+	if err := json.Unmarshal([]byte(text), &answer); isError || err != nil {
+		t.Fatalf("check_changes_need_adr = %s", text)
+	}
+	if answer.Hook != "pre-push" || !answer.WouldBlock || len(answer.DraftADRs) != 1 || answer.DraftADRs[0].Title != "Use Redis for caching" {
+		t.Errorf("pre-push = %s", text)
+	}
+
+	text, isError = callTool(t, server, "check_changes_need_adr", map[string]string{"hook": "pre-commit"})
+	answer.WouldBlock = true
+	if err := json.Unmarshal([]byte(text), &answer); isError || err != nil || answer.Hook != "pre-commit" || answer.WouldBlock {
+		t.Errorf("pre-commit = %s", text)
+	}
+
+	if text, isError := callTool(t, server, "check_changes_need_adr", map[string]string{"hook": "post-merge"}); !isError {
+		t.Errorf("unknown hook = %s, want an error", text)
+	}
+}
@@ -0,0 +1,409 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/config"
+	"github.com/SilverFlin/DrDuck/internal/hooks"
+	"github.com/SilverFlin/DrDuck/internal/search"
+	"github.com/SilverFlin/DrDuck/internal/similarity"
+)
+
+// tool is an MCP tool and the function answering its calls
+type tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+	Annotations map[string]any `json:"annotations,omitempty"`
+
+	call func(ctx context.Context, args json.RawMessage) (string, error)
+}
+
+// buildTools returns the tools the server offers
+func (s *Server) buildTools() []tool {
+	readOnly := map[string]any{"readOnlyHint": true}
+
+	return []tool{
+		{
+			Name:        "list_adrs",
+			Description: "List the project's Architecture Decision Records with their ID, title, status, date, tags and governed paths.",
+			InputSchema: objectSchema(map[string]any{
+				"status": stringProperty("Only ADRs with this status: draft, in-progress, accepted, rejected or superseded"),
+				"tag":    stringProperty("Only ADRs with this tag"),
+			}),
+			Annotations: readOnly,
+			call:        s.listADRs,
+		},
+		{
+			Name:        "get_adr",
+			Description: "Get the full Markdown of an ADR, including its front matter.",
+			InputSchema: objectSchema(map[string]any{
+				"id": map[string]any{"type": []string{"integer", "string"}, "description": "ADR number, e.g. 7 or \"0007\""},
+			}, "id"),
+			Annotations: readOnly,
+			call:        s.getADR,
+		},
+		{
+			Name:        "search_adrs",
+			Description: "Search ADRs by relevance. The query is free text plus optional filters: status:accepted tag:database after:2025-01-01 before:2026-01-01. Returns the best matches with excerpts.",
+			InputSchema: objectSchema(map[string]any{
+				"query": stringProperty("Search query, e.g. \"caching status:accepted\""),
+				"limit": map[string]any{"type": "integer", "minimum": 1, "description": "Maximum number of results (default 5)"},
+			}, "query"),
+			Annotations: readOnly,
+			call:        s.searchADRs,
+		},
+		{
+			Name: "create_adr_draft",
+			Description: "Create a Draft ADR from the project's template and fill in its sections. " +
+				"Search for similar ADRs first: amending or superseding an existing decision is often better than a duplicate. " +
+				"Sections of the configured template: " + strings.Join(s.templateSections(), ", ") + ".",
+			InputSchema: objectSchema(map[string]any{
+				"title": stringProperty("Short decision title, e.g. \"Use Redis for session storage\""),
+				"sections": map[string]any{
+					"type":                 "object",
+					"description":          "Markdown content by section title, e.g. {\"Context\": \"...\", \"Decision\": \"...\"}. Unknown sections are added.",
+					"additionalProperties": map[string]any{"type": "string"},
+				},
+			}, "title"),
+			call: s.createADRDraft,
+		},
+		{
+			Name: "check_changes_need_adr",
+			Description: "Run DrDuck's git hook validation on the current changes: draft ADRs that block a push, " +
+				"whether the changes need a new ADR according to the configured analysis, and accepted ADRs whose governed code was touched.",
+			InputSchema: objectSchema(map[string]any{
+				"hook": map[string]any{"type": "string", "enum": []string{"pre-push", "pre-commit"}, "description": "Which hook to run: pre-push (default) analyzes unpushed commits, pre-commit only checks drafts and staged changes"},
+			}),
+			// Not read-only: the pre-push analysis is stored in the analysis cache
+			Annotations: map[string]any{"readOnlyHint": false, "destructiveHint": false},
+			call:        s.checkChangesNeedADR,
+		},
+	}
+}
+
+// callTool runs a tools/call request. Tool failures are reported in the result
+// so the assistant can see them; unknown tools are protocol errors.
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (any, *rpcError) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("invalid tools/call params: %v", err)}
+	}
+	if len(p.Arguments) == 0 || string(p.Arguments) == "null" {
+		p.Arguments = json.RawMessage("{}")
+	}
+
+	for _, t := range s.tools {
+		if t.Name != p.Name {
+			continue
+		}
+		text, err := t.call(ctx, p.Arguments)
+		if err != nil {
+			return toolResult(err.Error(), true), nil
+		}
+		return toolResult(text, false), nil
+	}
+	return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown tool: %s", p.Name)}
+}
+
+// toolResult wraps text as the result of a tool call
+func toolResult(text string, isError bool) map[string]any {
+	return map[string]any{
+		"content": []map[string]any{{"type": "text", "text": text}},
+		"isError": isError,
+	}
+}
+
+// objectSchema returns the JSON schema of a tool's arguments
+func objectSchema(properties map[string]any, required ...string) map[string]any {
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// stringProperty returns the JSON schema of a string argument
+func stringProperty(description string) map[string]any {
+	return map[string]any{"type": "string", "description": description}
+}
+
+// decodeArgs decodes tool arguments
+func decodeArgs(args json.RawMessage, v any) error {
+	if err := json.Unmarshal(args, v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// toJSON formats a tool's answer
+func toJSON(v any) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode result: %w", err)
+	}
+	return string(data), nil
+}
+
+// adrSummary is an ADR's metadata as returned by the tools
+type adrSummary struct {
+	ID      int      `json:"id"`
+	Title   string   `json:"title"`
+	Status  string   `json:"status"`
+	Date    string   `json:"date"`
+	Tags    []string `json:"tags,omitempty"`
+	Governs []string `json:"governs,omitempty"`
+	Path    string   `json:"path"`
+}
+
+// summarize returns an ADR's metadata
+func summarize(a *adr.ADR) adrSummary {
+	return adrSummary{
+		ID:      a.ID,
+		Title:   a.Title,
+		Status:  string(a.Status),
+		Date:    a.Date.Format("2006-01-02"),
+		Tags:    a.Tags,
+		Governs: a.Governs,
+		Path:    a.FilePath,
+	}
+}
+
+func (s *Server) listADRs(ctx context.Context, args json.RawMessage) (string, error) {
+	var p struct {
+		Status string `json:"status"`
+		Tag    string `json:"tag"`
+	}
+	if err := decodeArgs(args, &p); err != nil {
+		return "", err
+	}
+
+	var query search.Query
+	if p.Status != "" {
+		query.Statuses = []string{p.Status}
+	}
+	if p.Tag != "" {
+		query.Tags = []string{p.Tag}
+	}
+
+	adrs, err := s.adrs.List()
+	if err != nil {
+		return "", fmt.Errorf("failed to list ADRs: %w", err)
+	}
+
+	summaries := []adrSummary{}
+	for _, a := range adrs {
+		if query.MatchesADR(a) {
+			summaries = append(summaries, summarize(a))
+		}
+	}
+	return toJSON(summaries)
+}
+
+func (s *Server) getADR(ctx context.Context, args json.RawMessage) (string, error) {
+	var p struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err := decodeArgs(args, &p); err != nil {
+		return "", err
+	}
+
+	idStr := strings.Trim(string(p.ID), `"`)
+	id, err := strconv.Atoi(strings.TrimLeft(idStr, "0"))
+	if err != nil {
+		return "", fmt.Errorf("invalid ADR ID: %s", idStr)
+	}
+
+	target, err := s.adrs.GetADRByID(id)
+	if err != nil {
+		return "", fmt.Errorf("ADR not found: %w", err)
+	}
+
+	content, err := os.ReadFile(target.FilePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read ADR file: %w", err)
+	}
+	return fmt.Sprintf("<!-- %s -->\n%s", target.FilePath, content), nil
+}
+
+func (s *Server) searchADRs(ctx context.Context, args json.RawMessage) (string, error) {
+	var p struct {
+		Query string `json:"query"`
+		Limit int    `json:"limit"`
+	}
+	if err := decodeArgs(args, &p); err != nil {
+		return "", err
+	}
+	if p.Limit <= 0 {
+		p.Limit = 5
+	}
+
+	query, err := search.ParseQuery(p.Query)
+	if err != nil {
+		return "", err
+	}
+
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	adrs, err := s.adrs.List()
+	if err != nil {
+		return "", fmt.Errorf("failed to list ADRs: %w", err)
+	}
+	index, err := search.Load(configDir, adrs, false)
+	if err != nil {
+		return "", err
+	}
+
+	type match struct {
+		adrSummary
+		Score    float64  `json:"score"`
+		Excerpts []string `json:"excerpts,omitempty"`
+	}
+	matches := []match{}
+	for _, result := range index.Search(query, p.Limit) {
+		entry := result.Entry
+		m := match{
+			adrSummary: adrSummary{
+				ID:     entry.ID,
+				Title:  entry.Title,
+				Status: entry.Status,
+				Date:   entry.Date.Format("2006-01-02"),
+				Tags:   entry.Tags,
+				Path:   entry.Path,
+			},
+			Score: float64(int(result.Score*100)) / 100,
+		}
+		for _, snippet := range result.Snippets {
+			m.Excerpts = append(m.Excerpts, snippet.Section+": "+snippet.Text)
+		}
+		matches = append(matches, m)
+	}
+	return toJSON(matches)
+}
+
+func (s *Server) createADRDraft(ctx context.Context, args json.RawMessage) (string, error) {
+	var p struct {
+		Title    string            `json:"title"`
+		Sections map[string]string `json:"sections"`
+	}
+	if err := decodeArgs(args, &p); err != nil {
+		return "", err
+	}
+	// Look for similar decisions before the new ADR exists and matches itself
+	existing, _ := s.adrs.List()
+	similar, _ := similarity.FindSimilar(existing, p.Title, s.config.Similarity)
+
+	created, err := s.adrs.Create(p.Title)
+	if err != nil {
+		return "", fmt.Errorf("failed to create ADR: %w", err)
+	}
+	if len(p.Sections) > 0 {
+		if err := s.adrs.FillSections(created, p.Sections); err != nil {
+			return "", err
+		}
+	}
+
+	result := map[string]any{
+		"created": summarize(created),
+		"next":    "Review the draft with the user. It stays a Draft, which blocks pushes, until it is accepted with 'drduck accept'.",
+	}
+	if len(similar) > 0 {
+		var related []adrSummary
+		for _, match := range similar {
+			related = append(related, summarize(match.ADR))
+		}
+		result["similar_existing_adrs"] = related
+	}
+	return toJSON(result)
+}
+
+func (s *Server) checkChangesNeedADR(ctx context.Context, args json.RawMessage) (string, error) {
+	var p struct {
+		Hook string `json:"hook"`
+	}
+	if err := decodeArgs(args, &p); err != nil {
+		return "", err
+	}
+
+	validator := hooks.NewValidator(s.config)
+	validator.SetInteractive(false)
+
+	var result *hooks.ValidationResult
+	switch p.Hook {
+	case "", "pre-push":
+		p.Hook = "pre-push"
+		result = validator.ValidatePrePush()
+	case "pre-commit":
+		result = validator.ValidatePreCommit()
+	default:
+		return "", fmt.Errorf("unknown hook %q, expected pre-commit or pre-push", p.Hook)
+	}
+
+	type driftSummary struct {
+		ADR         adrSummary `json:"adr"`
+		Files       []string   `json:"files"`
+		Contradicts bool       `json:"contradicts,omitempty"`
+		Reasoning   string     `json:"reasoning,omitempty"`
+	}
+	answer := struct {
+		Hook           string         `json:"hook"`
+		WouldBlock     bool           `json:"would_block"`
+		NeedsADR       bool           `json:"needs_adr"`
+		SuggestedTitle string         `json:"suggested_title,omitempty"`
+		DraftADRs      []adrSummary   `json:"draft_adrs,omitempty"`
+		Drift          []driftSummary `json:"governed_by_accepted_adrs,omitempty"`
+		Analysis       string         `json:"analysis,omitempty"`
+	}{
+		Hook:           p.Hook,
+		WouldBlock:     result.ShouldBlock,
+		NeedsADR:       result.NeedsADR,
+		SuggestedTitle: result.SuggestedTitle,
+		Analysis:       result.AIResponse,
+	}
+	for _, draft := range result.DraftADRs {
+		answer.DraftADRs = append(answer.DraftADRs, summarize(draft))
+	}
+	for _, finding := range result.Drift {
+		answer.Drift = append(answer.Drift, driftSummary{
+			ADR:         summarize(finding.ADR),
+			Files:       finding.Files,
+			Contradicts: finding.Contradicts,
+			Reasoning:   finding.Reasoning,
+		})
+	}
+	// Without an AI verdict the hook's message is the only explanation
+	if answer.Analysis == "" {
+		answer.Analysis = result.Message
+	}
+	return toJSON(answer)
+}
+
+// templateSections returns the section titles of the configured ADR template
+func (s *Server) templateSections() []string {
+	content, err := s.adrs.GenerateFromTemplate(&adr.ADR{Title: "Example", Status: adr.StatusDraft, Date: time.Now()})
+	if err != nil {
+		return nil
+	}
+
+	var titles []string
+	_, body := adr.SplitFrontMatter(content)
+	for _, section := range adr.ParseSections(body) {
+		// Status is kept in the front matter, not written by hand
+		if section.Title != "" && !strings.EqualFold(section.Title, "Status") {
+			titles = append(titles, section.Title)
+		}
+	}
+	return titles
+}
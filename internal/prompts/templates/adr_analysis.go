@@ -85,4 +85,53 @@ func ADRReviewPrompt(adrTitle, adrContent string) string {
 	promptBuilder.WriteString("for current and future developers.")
 	
 	return promptBuilder.String()
-}
\ No newline at end of file
+}
+
+// DriftCheckPrompt generates a prompt asking whether changes to code governed
+// by an accepted ADR contradict its decision
+func DriftCheckPrompt(adrTitle, decision, changes string) string {
+	var promptBuilder strings.Builder
+
+	promptBuilder.WriteString(personas.DrDuckPersona)
+	promptBuilder.WriteString("\n\n")
+
+	promptBuilder.WriteString("# DECISION DRIFT CHECK\n\n")
+
+	promptBuilder.WriteString(fmt.Sprintf("**Accepted ADR**: %s\n\n", adrTitle))
+
+	promptBuilder.WriteString("## Decision\n")
+	promptBuilder.WriteString(decision)
+	promptBuilder.WriteString("\n\n")
+
+	promptBuilder.WriteString("## Changes to Code Governed by This ADR\n")
+	promptBuilder.WriteString("```diff\n")
+	promptBuilder.WriteString(changes)
+	promptBuilder.WriteString("\n```\n\n")
+
+	promptBuilder.WriteString("## Check Required\n")
+	promptBuilder.WriteString("Do these changes contradict or quietly abandon the decision above? ")
+	promptBuilder.WriteString("Changes that implement, extend or refactor the decision do not contradict it.\n\n")
+	promptBuilder.WriteString("Answer in EXACTLY this format:\n\n")
+	promptBuilder.WriteString("**Contradicts**: Yes OR No\n")
+	promptBuilder.WriteString("**Reasoning**: One or two sentences\n\n")
+	promptBuilder.WriteString("IMPORTANT: Start your response with exactly '**Contradicts**: Yes' or '**Contradicts**: No'")
+
+	return promptBuilder.String()
+}
+
+// ParseDriftVerdict extracts the verdict and reasoning from a drift check response
+func ParseDriftVerdict(response string) (contradicts bool, reasoning string) {
+	for _, line := range strings.Split(response, "\n") {
+		lower := strings.ToLower(strings.TrimSpace(line))
+		lower = strings.ReplaceAll(lower, "*", "")
+		switch {
+		case strings.HasPrefix(lower, "contradicts:"):
+			contradicts = strings.HasPrefix(strings.TrimSpace(strings.TrimPrefix(lower, "contradicts:")), "yes")
+		case strings.HasPrefix(lower, "reasoning:"):
+			if idx := strings.Index(line, ":"); idx != -1 {
+				reasoning = strings.TrimSpace(strings.Trim(strings.TrimSpace(line[idx+1:]), "*"))
+			}
+		}
+	}
+	return contradicts, reasoning
+}
@@ -1,14 +1,22 @@
 package templates
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strings"
 
 	"github.com/SilverFlin/DrDuck/internal/prompts/personas"
 )
 
+// AnalysisSection is an extra block of structured context included in a change analysis prompt
+type AnalysisSection struct {
+	Title   string
+	Content string
+}
+
 // ChangeAnalysisPrompt generates a prompt for analyzing git changes to determine if an ADR is needed
-func ChangeAnalysisPrompt(projectName, changes, recentCommits string) string {
+func ChangeAnalysisPrompt(projectName, changes, recentCommits string, sections ...AnalysisSection) string {
 	var promptBuilder strings.Builder
 	
 	promptBuilder.WriteString(personas.DrDuckPersona)
@@ -20,7 +28,14 @@ func ChangeAnalysisPrompt(projectName, changes, recentCommits string) string {
 		promptBuilder.WriteString(fmt.Sprintf("**Project**: %s\n", projectName))
 	}
 	
-	promptBuilder.WriteString("**Task**: Analyze the following code changes and determine if they require an Architectural Decision Record (ADR).\n\n")
+	// When every file was analyzed before, only their cached summaries are sent
+	hasDiff := strings.TrimSpace(changes) != ""
+	if hasDiff {
+		promptBuilder.WriteString("**Task**: Analyze the following code changes and determine if they require an Architectural Decision Record (ADR).\n\n")
+	} else {
+		promptBuilder.WriteString("**Task**: The following code changes are described by summaries of their files, as no diff is included. ")
+		promptBuilder.WriteString("Based on these summaries, determine if they require an Architectural Decision Record (ADR).\n\n")
+	}
 	
 	if recentCommits != "" {
 		promptBuilder.WriteString("## Recent Commit Context\n")
@@ -29,11 +44,152 @@ func ChangeAnalysisPrompt(projectName, changes, recentCommits string) string {
 		promptBuilder.WriteString("\n```\n\n")
 	}
 	
-	promptBuilder.WriteString("## Code Changes to Analyze\n")
+	for _, section := range sections {
+		if strings.TrimSpace(section.Content) == "" {
+			continue
+		}
+		promptBuilder.WriteString(fmt.Sprintf("## %s\n", section.Title))
+		promptBuilder.WriteString(section.Content)
+		promptBuilder.WriteString("\n\n")
+	}
+	
+	if hasDiff {
+		promptBuilder.WriteString("## Code Changes to Analyze\n")
+		promptBuilder.WriteString("```diff\n")
+		promptBuilder.WriteString(changes)
+		promptBuilder.WriteString("\n```\n\n")
+	}
+	
+	writeDecisionInstructions(&promptBuilder)
+	
+	// Per-file summaries are cached so unchanged files need not be re-sent next time
+	if hasDiff {
+		promptBuilder.WriteString("\n\nAfter the Key Points, add a final **File Summaries** section with exactly one line per file in the diff above, ")
+		promptBuilder.WriteString("formatted as '- path/to/file: one sentence describing the change'. List only files from the Code Changes to Analyze section.")
+	}
+	
+	return promptBuilder.String()
+}
+
+// PromptVersion returns a hash of the change analysis prompt texts, including
+// the persona, so cached answers are not reused after the prompts are edited
+func PromptVersion() string {
+	hasher := sha256.New()
+	hasher.Write([]byte(ChangeAnalysisPrompt("", "-", "")))
+	hasher.Write([]byte(ChunkedChangeAnalysisPrompt("", "-", "")))
+	hasher.Write([]byte(ChunkSummaryPrompt("-", "-")))
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// ParseFileSummaries extracts the **File Summaries** section requested by
+// ChangeAnalysisPrompt. It returns the response without that section and the
+// summaries keyed by file path.
+func ParseFileSummaries(response string) (string, map[string]string) {
+	summaries := make(map[string]string)
+	lines := strings.Split(response, "\n")
+	
+	start := -1
+	for i, line := range lines {
+		if strings.Contains(strings.ToLower(line), "**file summaries**") {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return response, summaries
+	}
+	
+	end := start + 1
+	for ; end < len(lines); end++ {
+		line := strings.TrimSpace(lines[end])
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "- ") && !strings.HasPrefix(line, "* ") {
+			break
+		}
+		
+		entry := strings.TrimSpace(line[2:])
+		idx := strings.Index(entry, ": ")
+		if idx == -1 {
+			continue
+		}
+		path := strings.Trim(strings.TrimSpace(entry[:idx]), "`*")
+		summary := strings.TrimSpace(entry[idx+2:])
+		if path != "" && summary != "" {
+			summaries[path] = summary
+		}
+	}
+	
+	cleaned := append(append([]string{}, lines[:start]...), lines[end:]...)
+	return strings.TrimRight(strings.Join(cleaned, "\n"), "\n "), summaries
+}
+
+// ChunkedChangeAnalysisPrompt generates the final ADR decision prompt for a large
+// change set that was summarized chunk by chunk instead of sent as one diff
+func ChunkedChangeAnalysisPrompt(projectName, chunkSummaries, recentCommits string, sections ...AnalysisSection) string {
+	var promptBuilder strings.Builder
+	
+	promptBuilder.WriteString(personas.DrDuckPersona)
+	promptBuilder.WriteString("\n\n")
+	
+	promptBuilder.WriteString("# CHANGE ANALYSIS REQUEST\n\n")
+	
+	if projectName != "" {
+		promptBuilder.WriteString(fmt.Sprintf("**Project**: %s\n", projectName))
+	}
+	
+	promptBuilder.WriteString("**Task**: The following change set was too large to review as a single diff, so each package or directory was summarized separately. ")
+	promptBuilder.WriteString("Based on these summaries, determine if the changes as a whole require an Architectural Decision Record (ADR).\n\n")
+	
+	if recentCommits != "" {
+		promptBuilder.WriteString("## Recent Commit Context\n")
+		promptBuilder.WriteString("```\n")
+		promptBuilder.WriteString(recentCommits)
+		promptBuilder.WriteString("\n```\n\n")
+	}
+	
+	for _, section := range sections {
+		if strings.TrimSpace(section.Content) == "" {
+			continue
+		}
+		promptBuilder.WriteString(fmt.Sprintf("## %s\n", section.Title))
+		promptBuilder.WriteString(section.Content)
+		promptBuilder.WriteString("\n\n")
+	}
+	
+	promptBuilder.WriteString("## Change Summaries by Package\n")
+	promptBuilder.WriteString(chunkSummaries)
+	promptBuilder.WriteString("\n\n")
+	
+	writeDecisionInstructions(&promptBuilder)
+	
+	return promptBuilder.String()
+}
+
+// ChunkSummaryPrompt generates a prompt for summarizing one chunk of a large change set
+func ChunkSummaryPrompt(chunkName, changes string) string {
+	var promptBuilder strings.Builder
+	
+	promptBuilder.WriteString("You are summarizing one part of a large code change so that a reviewer can later decide whether the change as a whole needs an Architectural Decision Record.\n\n")
+	promptBuilder.WriteString(fmt.Sprintf("**Part**: %s\n\n", chunkName))
+	
+	promptBuilder.WriteString("## Code Changes\n")
 	promptBuilder.WriteString("```diff\n")
 	promptBuilder.WriteString(changes)
 	promptBuilder.WriteString("\n```\n\n")
 	
+	promptBuilder.WriteString("## Summary Required\n")
+	promptBuilder.WriteString("Write at most 6 short bullet points describing what changed. Prioritize architecturally relevant facts: ")
+	promptBuilder.WriteString("new or removed components, dependencies, public interfaces, data models, infrastructure and cross-cutting patterns. ")
+	promptBuilder.WriteString("Mention purely mechanical changes (renames, formatting, tests) in one bullet at most. ")
+	promptBuilder.WriteString("Do NOT decide whether an ADR is needed and do NOT add any introduction.")
+	
+	return promptBuilder.String()
+}
+
+// writeDecisionInstructions appends the required response format shared by change analysis prompts
+func writeDecisionInstructions(promptBuilder *strings.Builder) {
 	promptBuilder.WriteString("## Analysis Required\n")
 	promptBuilder.WriteString("Please analyze these changes and provide your assessment in EXACTLY this format:\n\n")
 	promptBuilder.WriteString("**Decision**: Yes OR No\n")
@@ -44,8 +200,6 @@ func ChangeAnalysisPrompt(projectName, changes, recentCommits string) string {
 	
 	promptBuilder.WriteString("Focus on architectural significance rather than implementation details. ")
 	promptBuilder.WriteString("Consider the long-term impact on the codebase, team understanding, and future maintainability.")
-	
-	return promptBuilder.String()
 }
 
 // DraftCompletionPrompt generates a prompt for suggesting how to complete draft ADRs
@@ -0,0 +1,77 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SilverFlin/DrDuck/internal/prompts/personas"
+	"github.com/SilverFlin/DrDuck/internal/transcript"
+)
+
+// TranscriptSummaryPrompt generates a prompt asking for the decision context
+// of a conversation between a developer and an AI assistant
+func TranscriptSummaryPrompt(adrTitle, conversation string) string {
+	var promptBuilder strings.Builder
+
+	promptBuilder.WriteString(personas.DrDuckPersona)
+	promptBuilder.WriteString("\n\n")
+
+	promptBuilder.WriteString("# DECISION CONTEXT FROM A CONVERSATION\n\n")
+
+	promptBuilder.WriteString(fmt.Sprintf("**ADR Being Written**: %s\n\n", adrTitle))
+
+	promptBuilder.WriteString("## Conversation\n")
+	promptBuilder.WriteString(conversation)
+	promptBuilder.WriteString("\n\n")
+
+	promptBuilder.WriteString("## Summary Required\n")
+	promptBuilder.WriteString("Summarize the architectural decision this conversation reached, for the developer to review ")
+	promptBuilder.WriteString("before it goes into the ADR. Only report what the conversation says; write N/A for anything it does not cover.\n\n")
+	promptBuilder.WriteString("Answer in EXACTLY this format:\n\n")
+	promptBuilder.WriteString("**Problem**: The problem or need that started the discussion\n")
+	promptBuilder.WriteString("**Decision**: The approach that was chosen\n")
+	promptBuilder.WriteString("**Rationale**: Why it was chosen\n")
+	promptBuilder.WriteString("**Alternatives**: The other options discussed and why they were not chosen\n")
+	promptBuilder.WriteString("**Trade-offs**: The downsides and costs that were accepted\n")
+	promptBuilder.WriteString("**Future**: Follow-up work, migration or scaling concerns that were raised\n\n")
+	promptBuilder.WriteString("IMPORTANT: Start your response with '**Problem**:'")
+
+	return promptBuilder.String()
+}
+
+// ParseTranscriptSummary extracts the fields of a transcript summary response.
+// Fields may span several lines; N/A answers are left empty.
+func ParseTranscriptSummary(response string) transcript.Decision {
+	var d transcript.Decision
+	fields := map[string]*string{
+		"problem":      &d.Problem,
+		"decision":     &d.Decision,
+		"rationale":    &d.Rationale,
+		"alternatives": &d.Alternatives,
+		"trade-offs":   &d.TradeOffs,
+		"tradeoffs":    &d.TradeOffs,
+		"future":       &d.Future,
+	}
+
+	var current *string
+	for _, line := range strings.Split(response, "\n") {
+		plain := strings.TrimSpace(strings.ReplaceAll(line, "*", ""))
+		if idx := strings.Index(plain, ":"); idx != -1 {
+			if field, ok := fields[strings.ToLower(strings.TrimSpace(plain[:idx]))]; ok {
+				current = field
+				*current = strings.TrimSpace(plain[idx+1:])
+				continue
+			}
+		}
+		if current != nil {
+			*current = strings.TrimSpace(*current + "\n" + line)
+		}
+	}
+
+	for _, field := range fields {
+		if strings.EqualFold(strings.TrimSuffix(*field, "."), "n/a") {
+			*field = ""
+		}
+	}
+	return d
+}
@@ -0,0 +1,251 @@
+package refs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/config"
+	"github.com/SilverFlin/DrDuck/internal/rules"
+)
+
+// maxFileSize skips files too large to be hand-written source
+const maxFileSize = 1 << 20
+
+// Ref is a code comment referencing an ADR, e.g. "// ADR-0007" or "# adr: 7"
+type Ref struct {
+	Path  string `json:"path"`
+	Line  int    `json:"line"`
+	ADRID int    `json:"adr_id"`
+	Text  string `json:"text"` // The trimmed source line
+}
+
+// Location returns the reference as path:line
+func (r Ref) Location() string {
+	return fmt.Sprintf("%s:%d", r.Path, r.Line)
+}
+
+var (
+	// commentPattern finds where a comment starts on a line
+	commentPattern = regexp.MustCompile(`//|/\*|<!--|#|--|;|^\s*\*`)
+	// refPattern finds ADR references inside a comment
+	refPattern = regexp.MustCompile(`(?i)\badr(?:-|:|\s)\s*0*(\d+)\b`)
+)
+
+// skipDirs are never scanned
+var skipDirs = map[string]bool{
+	".git":         true,
+	".drduck":      true,
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// Scan finds ADR references in the comments of the files under root. Files
+// ignored by git, binary files and files matching an exclude pattern are skipped.
+func Scan(root string, exclude []string) ([]Ref, error) {
+	files, err := listFiles(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []Ref
+	for _, file := range files {
+		if rules.MatchAny(exclude, file) {
+			continue
+		}
+		fileRefs, err := scanFile(root, file)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, fileRefs...)
+	}
+	return refs, nil
+}
+
+// CheckProject scans the current project and checks its references against its
+// ADRs. The ADR directory itself is not scanned, as ADRs reference each other.
+func CheckProject(cfg *config.Config) (*Report, error) {
+	exclude := append([]string{}, cfg.Refs.Exclude...)
+	if cfg.DocStorage == "same-repo" && cfg.DocPath != "" {
+		exclude = append(exclude, strings.TrimSuffix(filepath.ToSlash(cfg.DocPath), "/")+"/**")
+	}
+
+	refs, err := Scan(".", exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	adrs, err := adr.NewManager(cfg).List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ADRs: %w", err)
+	}
+	return Check(refs, adrs), nil
+}
+
+// ParseLine returns the ADR IDs referenced in a comment on a source line
+func ParseLine(line string) []int {
+	loc := commentPattern.FindStringIndex(line)
+	if loc == nil {
+		return nil
+	}
+
+	var ids []int
+	for _, match := range refPattern.FindAllStringSubmatch(line[loc[0]:], -1) {
+		if id, err := strconv.Atoi(match[1]); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// scanFile finds ADR references in one file
+func scanFile(root, file string) ([]Ref, error) {
+	path := filepath.Join(root, filepath.FromSlash(file))
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > maxFileSize {
+		// Deleted but still tracked, a symlink to a directory, or generated data
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if bytes.IndexByte(content[:min(len(content), 8000)], 0) != -1 {
+		return nil, nil // Binary file
+	}
+
+	var refs []Ref
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFileSize)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		for _, id := range ParseLine(line) {
+			refs = append(refs, Ref{Path: file, Line: lineNumber, ADRID: id, Text: strings.TrimSpace(line)})
+		}
+	}
+	return refs, nil
+}
+
+// listFiles returns the slash-separated paths of the files under root, using
+// git so ignored files are skipped, or walking the tree outside a repository
+func listFiles(root string) ([]string, error) {
+	cmd := exec.Command("git", "ls-files", "--cached", "--others", "--exclude-standard", "-z")
+	cmd.Dir = root
+	if output, err := cmd.Output(); err == nil {
+		var files []string
+		for _, file := range strings.Split(string(output), "\x00") {
+			if file != "" && !inSkippedDir(file) {
+				files = append(files, file)
+			}
+		}
+		sort.Strings(files)
+		return files, nil
+	}
+
+	var files []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != root && skipDirs[entry.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	return files, nil
+}
+
+// inSkippedDir reports whether a slash-separated path is inside a skipped directory
+func inSkippedDir(file string) bool {
+	for _, part := range strings.Split(file, "/") {
+		if skipDirs[part] {
+			return true
+		}
+	}
+	return false
+}
+
+// Report relates code references to the project's ADRs
+type Report struct {
+	ByADR        map[int][]Ref // References to existing ADRs, by ADR ID
+	Missing      []Ref         // References to ADRs that do not exist
+	Stale        []StaleRef    // References to rejected or superseded ADRs
+	Unreferenced []*adr.ADR    // Accepted ADRs no code references
+}
+
+// StaleRef is a reference to an ADR that no longer applies
+type StaleRef struct {
+	Ref
+	Status adr.Status
+}
+
+// Check builds the code-location ↔ ADR index and finds problems in it
+func Check(refs []Ref, adrs []*adr.ADR) *Report {
+	report := &Report{ByADR: make(map[int][]Ref)}
+
+	byID := make(map[int]*adr.ADR, len(adrs))
+	for _, a := range adrs {
+		byID[a.ID] = a
+	}
+
+	for _, ref := range refs {
+		target, exists := byID[ref.ADRID]
+		if !exists {
+			report.Missing = append(report.Missing, ref)
+			continue
+		}
+		report.ByADR[ref.ADRID] = append(report.ByADR[ref.ADRID], ref)
+		if hasStatus(target, adr.StatusRejected) || hasStatus(target, adr.StatusSuperseded) {
+			report.Stale = append(report.Stale, StaleRef{Ref: ref, Status: target.Status})
+		}
+	}
+
+	for _, a := range adrs {
+		if hasStatus(a, adr.StatusAccepted) && len(report.ByADR[a.ID]) == 0 {
+			report.Unreferenced = append(report.Unreferenced, a)
+		}
+	}
+	return report
+}
+
+// HasProblems reports whether any reference is missing or stale, or an
+// accepted ADR is not referenced
+func (r *Report) HasProblems() bool {
+	return len(r.Missing) > 0 || len(r.Stale) > 0 || len(r.Unreferenced) > 0
+}
+
+// ADRIDs returns the IDs of the referenced ADRs in ascending order
+func (r *Report) ADRIDs() []int {
+	ids := make([]int, 0, len(r.ByADR))
+	for id := range r.ByADR {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// hasStatus compares ADR statuses case-insensitively, as front matter is hand-edited
+func hasStatus(a *adr.ADR, status adr.Status) bool {
+	return strings.EqualFold(string(a.Status), string(status))
+}
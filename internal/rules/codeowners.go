@@ -0,0 +1,80 @@
+package rules
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// codeownersLocations lists where GitHub and GitLab look for a CODEOWNERS file
+var codeownersLocations = []string{
+	"CODEOWNERS",
+	".github/CODEOWNERS",
+	"docs/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+}
+
+// CodeownersEntry is a single pattern line from a CODEOWNERS file
+type CodeownersEntry struct {
+	Pattern string
+	Owners  []string
+}
+
+// LoadCodeowners reads the first CODEOWNERS file found under repoRoot.
+// A missing file is not an error; it simply yields no entries.
+func LoadCodeowners(repoRoot string) ([]CodeownersEntry, error) {
+	for _, location := range codeownersLocations {
+		file, err := os.Open(filepath.Join(repoRoot, location))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		var entries []CodeownersEntry
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") {
+				continue // Skip blanks, comments and GitLab section headers
+			}
+			fields := strings.Fields(line)
+			entries = append(entries, CodeownersEntry{Pattern: fields[0], Owners: fields[1:]})
+		}
+		return entries, scanner.Err()
+	}
+
+	return nil, nil
+}
+
+// Matches reports whether the CODEOWNERS pattern covers the given path
+func (e CodeownersEntry) Matches(filePath string) bool {
+	pattern := e.Pattern
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	// "dir/" covers everything below the directory
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+
+	// Unanchored patterns without an inner slash match at any depth
+	if !anchored && !strings.Contains(strings.TrimSuffix(pattern, "/**"), "/") {
+		pattern = "**/" + pattern
+	}
+
+	// A bare directory name also covers its contents
+	return MatchGlob("/"+pattern, filePath) || MatchGlob("/"+pattern+"/**", filePath)
+}
+
+// IsCatchAll reports whether the pattern covers the entire repository
+func (e CodeownersEntry) IsCatchAll() bool {
+	switch e.Pattern {
+	case "*", "/*", "**", "/**", "/":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadCodeowners(t *testing.T) {
+	repoRoot := t.TempDir()
+
+	entries, err := LoadCodeowners(repoRoot)
+	if err != nil || entries != nil {
+		t.Fatalf("LoadCodeowners without a file = %v, %v", entries, err)
+	}
+
+	os.MkdirAll(filepath.Join(repoRoot, ".github"), 0755)
+	content := "# Owners\n\n* @everyone\n[Database]\n/db/ @dba @oncall\n"
+	if err := os.WriteFile(filepath.Join(repoRoot, ".github", "CODEOWNERS"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err = LoadCodeowners(repoRoot)
+	if err != nil {
+		t.Fatalf("LoadCodeowners: %v", err)
+	}
+	want := []CodeownersEntry{
+		{Pattern: "*", Owners: []string{"@everyone"}},
+		{Pattern: "/db/", Owners: []string{"@dba", "@oncall"}},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entries = %+v, want %+v", entries, want)
+	}
+}
+
+func TestCodeownersEntryMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.js", "web/app.js", true},
+		{"*.js", "web/app.ts", false},
+		{"/db/", "db/schema.sql", true},
+		{"/db/", "services/db/schema.sql", false},
+		{"db/", "services/db/schema.sql", true},
+		{"docs", "docs/adr/0001.md", true},
+		{"docs", "web/docs/index.md", true},
+		{"/docs", "web/docs/index.md", false},
+		{"/build/logs/", "build/logs/today/out.log", true},
+		{"apps/*", "apps/web.go", true},
+		{"/config/app.yaml", "config/app.yaml", true},
+		{"/config/app.yaml", "config/app.yml", false},
+	}
+	for _, tt := range tests {
+		entry := CodeownersEntry{Pattern: tt.pattern}
+		if got := entry.Matches(tt.path); got != tt.want {
+			t.Errorf("%q.Matches(%q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCodeownersEntryIsCatchAll(t *testing.T) {
+	for _, pattern := range []string{"*", "/*", "**", "/**", "/"} {
+		if !(CodeownersEntry{Pattern: pattern}).IsCatchAll() {
+			t.Errorf("%q is not a catch-all", pattern)
+		}
+	}
+	for _, pattern := range []string{"*.go", "/docs/", "src/**"} {
+		if (CodeownersEntry{Pattern: pattern}).IsCatchAll() {
+			t.Errorf("%q is a catch-all", pattern)
+		}
+	}
+}
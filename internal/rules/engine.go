@@ -0,0 +1,387 @@
+package rules
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/SilverFlin/DrDuck/internal/apidiff"
+	"github.com/SilverFlin/DrDuck/internal/config"
+	"github.com/SilverFlin/DrDuck/internal/deps"
+	"github.com/SilverFlin/DrDuck/internal/diff"
+	"github.com/SilverFlin/DrDuck/internal/schema"
+)
+
+// Trigger identifies the kind of change a rule reacts to
+type Trigger string
+
+const (
+	TriggerDependencyAdded      Trigger = "dependency_added"
+	TriggerFileAdded            Trigger = "file_added"
+	TriggerFileChanged          Trigger = "file_changed"
+	TriggerPublicAPIChanged     Trigger = "public_api_changed"
+	TriggerCodeownersPath       Trigger = "codeowners_path"
+	TriggerContentMatch         Trigger = "content_match"
+	TriggerDestructiveMigration Trigger = "destructive_migration"
+)
+
+// Rule is a compiled ADR trigger
+type Rule struct {
+	Name        string
+	Description string
+	Trigger     Trigger
+	Paths       []string
+	Pattern     *regexp.Regexp
+}
+
+// Match records a rule that fired, with the files and details that caused it
+type Match struct {
+	Rule        string
+	Description string
+	Files       []string
+	Details     []string
+}
+
+// Result is the outcome of evaluating all rules against a diff
+type Result struct {
+	Matches []Match
+}
+
+// Engine evaluates deterministic ADR triggers against parsed diffs
+type Engine struct {
+	rules      []Rule
+	repoRoot   string
+	codeowners []CodeownersEntry
+
+	// compareDeps and compareAPI report the dependency changes in the manifests
+	// and the exported API changes in the packages of the diff
+	compareDeps func(files []*diff.File) ([]deps.Change, error)
+	compareAPI  func(files []string) ([]apidiff.Change, error)
+}
+
+// NewEngine compiles rule declarations from config into an engine.
+// repoRoot is used to locate the CODEOWNERS file.
+func NewEngine(ruleConfigs []config.RuleConfig, repoRoot string) (*Engine, error) {
+	engine := &Engine{
+		repoRoot:    repoRoot,
+		compareDeps: compareManifests,
+		compareAPI:  compareAPI,
+	}
+
+	for _, rc := range ruleConfigs {
+		rule := Rule{
+			Name:        rc.Name,
+			Description: rc.Description,
+			Trigger:     Trigger(rc.When),
+			Paths:       rc.Paths,
+		}
+
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule is missing a name")
+		}
+
+		switch rule.Trigger {
+		case TriggerDependencyAdded, TriggerFileAdded, TriggerFileChanged,
+			TriggerPublicAPIChanged, TriggerCodeownersPath, TriggerDestructiveMigration:
+			// No extra settings required
+		case TriggerContentMatch:
+			if rc.Pattern == "" {
+				return nil, fmt.Errorf("rule %q: content_match requires a pattern", rc.Name)
+			}
+			pattern, err := regexp.Compile(rc.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: invalid pattern: %w", rc.Name, err)
+			}
+			rule.Pattern = pattern
+		default:
+			return nil, fmt.Errorf("rule %q: unknown trigger %q", rc.Name, rc.When)
+		}
+
+		if rule.Trigger == TriggerFileAdded && len(rule.Paths) == 0 {
+			return nil, fmt.Errorf("rule %q: file_added requires at least one path", rc.Name)
+		}
+
+		engine.rules = append(engine.rules, rule)
+	}
+
+	return engine, nil
+}
+
+// Rules returns the compiled rules in declaration order
+func (e *Engine) Rules() []Rule {
+	return e.rules
+}
+
+// EvaluateDiff parses a raw unified diff and evaluates all rules against it
+func (e *Engine) EvaluateDiff(raw string) (*Result, error) {
+	return e.Evaluate(diff.Parse(raw))
+}
+
+// Evaluate runs every rule against the parsed diff
+func (e *Engine) Evaluate(files []*diff.File) (*Result, error) {
+	result := &Result{}
+
+	for _, rule := range e.rules {
+		var match Match
+		var err error
+
+		switch rule.Trigger {
+		case TriggerDependencyAdded:
+			match, err = e.evaluateDependencyAdded(rule, files)
+		case TriggerFileAdded:
+			match = e.evaluateFiles(rule, files, true)
+		case TriggerFileChanged:
+			match = e.evaluateFiles(rule, files, false)
+		case TriggerPublicAPIChanged:
+			match, err = e.evaluatePublicAPI(rule, files)
+		case TriggerCodeownersPath:
+			match, err = e.evaluateCodeowners(rule, files)
+		case TriggerContentMatch:
+			match = e.evaluateContent(rule, files)
+		case TriggerDestructiveMigration:
+			match = e.evaluateDestructiveMigration(rule, files)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("rule %q failed: %w", rule.Name, err)
+		}
+
+		if len(match.Files) > 0 {
+			match.Rule = rule.Name
+			match.Description = rule.Description
+			result.Matches = append(result.Matches, match)
+		}
+	}
+
+	return result, nil
+}
+
+// appliesTo reports whether a rule's path filter covers the file
+func (r Rule) appliesTo(filePath string) bool {
+	return len(r.Paths) == 0 || MatchAny(r.Paths, filePath)
+}
+
+// evaluateFiles fires for files matching the rule's paths (only new files when addedOnly)
+func (e *Engine) evaluateFiles(rule Rule, files []*diff.File, addedOnly bool) Match {
+	var match Match
+	for _, file := range files {
+		if addedOnly && file.Status != diff.StatusAdded {
+			continue
+		}
+		if rule.appliesTo(file.Path()) {
+			match.Files = append(match.Files, file.Path())
+		}
+	}
+	return match
+}
+
+// evaluatePublicAPI fires for changed Go files whose package gained, lost or
+// changed an exported declaration
+func (e *Engine) evaluatePublicAPI(rule Rule, files []*diff.File) (Match, error) {
+	var match Match
+
+	var goFiles []string
+	for _, file := range files {
+		if filePath := file.Path(); apidiff.IsAPIFile(filePath) && rule.appliesTo(filePath) {
+			goFiles = append(goFiles, filePath)
+		}
+	}
+	if len(goFiles) == 0 {
+		return match, nil
+	}
+
+	changes, err := e.compareAPI(goFiles)
+	if err != nil {
+		return match, fmt.Errorf("failed to compare the public API: %w", err)
+	}
+
+	changedPackages := make(map[string]bool)
+	for _, change := range changes {
+		changedPackages[change.Package] = true
+		match.Details = append(match.Details, change.String())
+	}
+	for _, filePath := range goFiles {
+		if changedPackages[path.Dir(filePath)] {
+			match.Files = append(match.Files, filePath)
+		}
+	}
+	return match, nil
+}
+
+// branchBase returns the commit the working tree is compared against: the
+// branch's base, or HEAD when the branch has no upstream
+func branchBase() string {
+	baseRef, err := apidiff.BaseRef()
+	if err != nil {
+		return "HEAD"
+	}
+	return baseRef
+}
+
+// compareManifests compares the manifests in the diff on disk with the branch's base
+func compareManifests(files []*diff.File) ([]deps.Change, error) {
+	return deps.Compare(branchBase(), apidiff.WorkingTree, files)
+}
+
+// compareAPI compares the API of the packages containing the files on disk with the branch's base
+func compareAPI(files []string) ([]apidiff.Change, error) {
+	return apidiff.Compare(branchBase(), apidiff.WorkingTree, files)
+}
+
+// evaluateCodeowners fires for files covered by a (non catch-all) CODEOWNERS pattern
+func (e *Engine) evaluateCodeowners(rule Rule, files []*diff.File) (Match, error) {
+	var match Match
+
+	if e.codeowners == nil {
+		entries, err := LoadCodeowners(e.repoRoot)
+		if err != nil {
+			return match, fmt.Errorf("failed to read CODEOWNERS: %w", err)
+		}
+		e.codeowners = entries
+	}
+
+	for _, file := range files {
+		filePath := file.Path()
+		if !rule.appliesTo(filePath) {
+			continue
+		}
+		// Later CODEOWNERS entries take precedence, as on GitHub
+		for i := len(e.codeowners) - 1; i >= 0; i-- {
+			entry := e.codeowners[i]
+			if entry.Matches(filePath) {
+				if !entry.IsCatchAll() {
+					match.Files = append(match.Files, filePath)
+					match.Details = append(match.Details, fmt.Sprintf("%s (owners: %s)", filePath, strings.Join(entry.Owners, " ")))
+				}
+				break
+			}
+		}
+	}
+
+	return match, nil
+}
+
+// evaluateContent fires when an added line matches the rule's pattern
+func (e *Engine) evaluateContent(rule Rule, files []*diff.File) Match {
+	var match Match
+	for _, file := range files {
+		if !rule.appliesTo(file.Path()) {
+			continue
+		}
+		for _, line := range file.AddedLines() {
+			if rule.Pattern.MatchString(line) {
+				match.Files = append(match.Files, file.Path())
+				match.Details = append(match.Details, fmt.Sprintf("%s: %s", file.Path(), strings.TrimSpace(line)))
+				break
+			}
+		}
+	}
+	return match
+}
+
+// evaluateDependencyAdded fires when a manifest gains a dependency it did not have before
+func (e *Engine) evaluateDependencyAdded(rule Rule, files []*diff.File) (Match, error) {
+	var match Match
+
+	var manifests []*diff.File
+	for _, file := range files {
+		if deps.IsManifest(file.Path()) && rule.appliesTo(file.Path()) {
+			manifests = append(manifests, file)
+		}
+	}
+	if len(manifests) == 0 {
+		return match, nil
+	}
+
+	changes, err := e.compareDeps(manifests)
+	if err != nil {
+		return match, fmt.Errorf("failed to compare dependencies: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, change := range deps.OfKind(changes, deps.KindAdded) {
+		if !seen[change.Manifest] {
+			seen[change.Manifest] = true
+			match.Files = append(match.Files, change.Manifest)
+		}
+		match.Details = append(match.Details, change.String())
+	}
+	return match, nil
+}
+
+// evaluateDestructiveMigration fires when a migration drops, truncates, renames or retypes data
+func (e *Engine) evaluateDestructiveMigration(rule Rule, files []*diff.File) Match {
+	var match Match
+	for _, migration := range schema.Destructive(schema.Analyze(files)) {
+		if !rule.appliesTo(migration.Path) {
+			continue
+		}
+		match.Files = append(match.Files, migration.Path)
+		for _, op := range migration.Destructive() {
+			match.Details = append(match.Details, fmt.Sprintf("%s: %s", migration.Path, op))
+		}
+	}
+	return match
+}
+
+// NeedsADR reports whether any rule fired
+func (r *Result) NeedsADR() bool {
+	return len(r.Matches) > 0
+}
+
+// MatchedFiles returns the unique, sorted set of files that triggered any rule
+func (r *Result) MatchedFiles() []string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, match := range r.Matches {
+		for _, file := range match.Files {
+			if !seen[file] {
+				seen[file] = true
+				files = append(files, file)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Report formats the result in the same shape as an AI change analysis so it
+// can be consumed by the existing decision parsing in hooks and complete-adr
+func (r *Result) Report() string {
+	var builder strings.Builder
+
+	if !r.NeedsADR() {
+		builder.WriteString("**Decision**: No\n")
+		builder.WriteString("**Reasoning**: No configured ADR rule matched these changes\n")
+		builder.WriteString("**Suggested ADR Title**: N/A\n")
+		builder.WriteString("**Key Points**: N/A")
+		return builder.String()
+	}
+
+	names := make([]string, 0, len(r.Matches))
+	for _, match := range r.Matches {
+		names = append(names, match.Rule)
+	}
+
+	builder.WriteString("**Decision**: Yes\n")
+	builder.WriteString(fmt.Sprintf("**Reasoning**: Matched ADR rules: %s\n", strings.Join(names, ", ")))
+	builder.WriteString(fmt.Sprintf("**Suggested ADR Title**: document-%s\n", r.Matches[0].Rule))
+	builder.WriteString("**Key Points**:\n")
+	for _, match := range r.Matches {
+		description := match.Description
+		if description == "" {
+			description = match.Rule
+		}
+		builder.WriteString(fmt.Sprintf("- %s (%s)\n", description, match.Rule))
+		details := match.Details
+		if len(details) == 0 {
+			details = match.Files
+		}
+		for _, detail := range details {
+			builder.WriteString(fmt.Sprintf("  - %s\n", detail))
+		}
+	}
+
+	return strings.TrimRight(builder.String(), "\n")
+}
@@ -0,0 +1,157 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/SilverFlin/DrDuck/internal/apidiff"
+	"github.com/SilverFlin/DrDuck/internal/config"
+	"github.com/SilverFlin/DrDuck/internal/deps"
+	"github.com/SilverFlin/DrDuck/internal/diff"
+)
+
+const sampleDiff = `diff --git a/go.mod b/go.mod
+--- a/go.mod
++++ b/go.mod
+@@ -3,2 +3,3 @@
+ 	github.com/spf13/cobra v1.8.0
++	go.etcd.io/bbolt v1.4.3
+ )
+diff --git a/db/migrations/002_drop_email.up.sql b/db/migrations/002_drop_email.up.sql
+new file mode 100644
+--- /dev/null
++++ b/db/migrations/002_drop_email.up.sql
+@@ -0,0 +1 @@
++ALTER TABLE users DROP COLUMN email;
+diff --git a/pkg/store/store.go b/pkg/store/store.go
+--- a/pkg/store/store.go
++++ b/pkg/store/store.go
+@@ -1,3 +1,3 @@
+ package store
+
+-func Open(path string) error { return nil }
++func Open(path string, readOnly bool) error { return nil }
+diff --git a/internal/flags/flags.go b/internal/flags/flags.go
+--- a/internal/flags/flags.go
++++ b/internal/flags/flags.go
+@@ -1,2 +1,3 @@
+ package flags
++var checkout = featureflag.New("checkout")
+`
+
+func TestNewEngineRejectsInvalidRules(t *testing.T) {
+	tests := []struct {
+		name string
+		rule config.RuleConfig
+	}{
+		{"missing name", config.RuleConfig{When: "file_changed"}},
+		{"unknown trigger", config.RuleConfig{Name: "r", When: "file_renamed"}},
+		{"content_match without pattern", config.RuleConfig{Name: "r", When: "content_match"}},
+		{"invalid pattern", config.RuleConfig{Name: "r", When: "content_match", Pattern: "("}},
+		{"file_added without paths", config.RuleConfig{Name: "r", When: "file_added"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewEngine([]config.RuleConfig{tt.rule}, t.TempDir()); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	repoRoot := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoRoot, "CODEOWNERS"), []byte("* @everyone\n/pkg/ @platform\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	engine, err := NewEngine([]config.RuleConfig{
+		{Name: "new-dependency", When: "dependency_added"},
+		{Name: "new-migration", When: "file_added", Paths: []string{"**/db/migrations/**"}},
+		{Name: "destructive-migration", When: "destructive_migration"},
+		{Name: "public-api-change", When: "public_api_changed"},
+		{Name: "codeowners-path", When: "codeowners_path"},
+		{Name: "feature-flags", When: "content_match", Pattern: `featureflag\.New`},
+		{Name: "docs", When: "file_changed", Paths: []string{"docs/**"}},
+	}, repoRoot)
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+
+	engine.compareDeps = func(files []*diff.File) ([]deps.Change, error) {
+		if len(files) != 1 || files[0].Path() != "go.mod" {
+			t.Errorf("compared dependencies of %v, want only go.mod", diff.Paths(files))
+		}
+		return deps.Diff("go.mod",
+			"require (\n\tgithub.com/spf13/cobra v1.8.0\n)\n",
+			"require (\n\tgithub.com/spf13/cobra v1.8.0\n\tgo.etcd.io/bbolt v1.4.3\n)\n"), nil
+	}
+
+	before, after := apidiff.Surface{}, apidiff.Surface{}
+	before.AddFile("pkg/store/store.go", []byte("package store\n\nfunc Open(path string) error { return nil }\n"))
+	after.AddFile("pkg/store/store.go", []byte("package store\n\nfunc Open(path string, readOnly bool) error { return nil }\n"))
+	var compared []string
+	engine.compareAPI = func(files []string) ([]apidiff.Change, error) {
+		compared = files
+		return apidiff.Diff(before, after), nil
+	}
+
+	result, err := engine.EvaluateDiff(sampleDiff)
+	if err != nil {
+		t.Fatalf("EvaluateDiff: %v", err)
+	}
+
+	got := make(map[string][]string)
+	for _, match := range result.Matches {
+		got[match.Rule] = match.Files
+	}
+	want := map[string][]string{
+		"new-dependency":        {"go.mod"},
+		"new-migration":         {"db/migrations/002_drop_email.up.sql"},
+		"destructive-migration": {"db/migrations/002_drop_email.up.sql"},
+		"public-api-change":     {"pkg/store/store.go"},
+		"codeowners-path":       {"pkg/store/store.go"},
+		"feature-flags":         {"internal/flags/flags.go"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matches = %v, want %v", got, want)
+	}
+
+	// Internal packages have no public API to compare
+	if !reflect.DeepEqual(compared, []string{"pkg/store/store.go"}) {
+		t.Errorf("compared API of %v, want only pkg/store/store.go", compared)
+	}
+
+	if !result.NeedsADR() {
+		t.Error("NeedsADR = false, want true")
+	}
+	report := result.Report()
+	for _, want := range []string{"**Decision**: Yes", "go.etcd.io/bbolt", "public-api-change", "Open", "@platform"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report is missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestEvaluatePublicAPIIgnoresUnchangedAPI(t *testing.T) {
+	engine, err := NewEngine([]config.RuleConfig{{Name: "api", When: "public_api_changed"}}, t.TempDir())
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	// A body-only edit touches the declaration line but not the signature
+	engine.compareAPI = func([]string) ([]apidiff.Change, error) { return nil, nil }
+
+	result, err := engine.Evaluate(diff.Parse(sampleDiff))
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if result.NeedsADR() {
+		t.Errorf("matches = %+v, want none", result.Matches)
+	}
+	if !strings.Contains(result.Report(), "**Decision**: No") {
+		t.Errorf("report = %q, want a No decision", result.Report())
+	}
+}
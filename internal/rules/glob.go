@@ -0,0 +1,81 @@
+package rules
+
+import (
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var (
+	globCache   = make(map[string]*regexp.Regexp)
+	globCacheMu sync.Mutex
+)
+
+// MatchGlob reports whether a slash-separated path matches a glob pattern.
+// Supported syntax: "*" (any run of characters except "/"), "?" (one character),
+// and "**" (any number of directories). Patterns without a "/" match against
+// the file's base name, so "*.tf" matches "infra/main.tf".
+func MatchGlob(pattern, filePath string) bool {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return false
+	}
+	filePath = strings.TrimPrefix(filePath, "./")
+
+	if !strings.Contains(pattern, "/") {
+		return globRegexp(pattern).MatchString(path.Base(filePath))
+	}
+
+	return globRegexp(strings.TrimPrefix(pattern, "/")).MatchString(filePath)
+}
+
+// MatchAny reports whether the path matches at least one of the patterns
+func MatchAny(patterns []string, filePath string) bool {
+	for _, pattern := range patterns {
+		if MatchGlob(pattern, filePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// globRegexp compiles (and memoizes) the regular expression for a glob pattern
+func globRegexp(pattern string) *regexp.Regexp {
+	globCacheMu.Lock()
+	defer globCacheMu.Unlock()
+
+	if re, ok := globCache[pattern]; ok {
+		return re
+	}
+
+	var builder strings.Builder
+	builder.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				// "**/" matches zero or more directories
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					builder.WriteString("(?:.*/)?")
+				} else {
+					builder.WriteString(".*")
+				}
+			} else {
+				builder.WriteString("[^/]*")
+			}
+		case '?':
+			builder.WriteString("[^/]")
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	builder.WriteString("$")
+
+	re := regexp.MustCompile(builder.String())
+	globCache[pattern] = re
+	return re
+}
@@ -0,0 +1,44 @@
+package rules
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.tf", "infra/main.tf", true},
+		{"*.tf", "infra/main.tfvars", false},
+		{"infra/*.tf", "infra/main.tf", true},
+		{"infra/*.tf", "infra/modules/main.tf", false},
+		{"infra/**/*.tf", "infra/main.tf", true},
+		{"infra/**/*.tf", "infra/modules/vpc/main.tf", true},
+		{"**/db/migrations/**", "services/api/db/migrations/001.sql", true},
+		{"**/db/migrations/**", "db/migrations/001.sql", true},
+		{"**/db/migrations/**", "db/seeds/001.sql", false},
+		{"/go.mod", "go.mod", true},
+		{"go.?od", "go.mod", true},
+		{"go.?od", "go.mood", false},
+		{"pkg/a+b/*.go", "pkg/a+b/x.go", true},
+		{"pkg/a+b/*.go", "pkg/aab/x.go", false},
+		{"*.go", "./main.go", true},
+		{"", "main.go", false},
+		{"  ", "main.go", false},
+	}
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestMatchAny(t *testing.T) {
+	patterns := []string{"docs/**", "*.md"}
+	if !MatchAny(patterns, "README.md") || !MatchAny(patterns, "docs/adr/0001.txt") {
+		t.Error("MatchAny missed a matching pattern")
+	}
+	if MatchAny(patterns, "main.go") || MatchAny(nil, "README.md") {
+		t.Error("MatchAny matched without a matching pattern")
+	}
+}
@@ -0,0 +1,345 @@
+package schema
+
+import (
+	"regexp"
+	"strings"
+)
+
+// railsArg matches a Ruby symbol or string argument
+const railsArg = `[:"']?(\w+)["']?`
+
+var (
+	railsDownMethod = regexp.MustCompile(`^\s*def\s+(?:self\.)?down\b`)
+	railsMethod     = regexp.MustCompile(`^\s*def\s+`)
+
+	railsCreateTable     = regexp.MustCompile(`\bcreate_table\s*\(?\s*` + railsArg)
+	railsChangeTable     = regexp.MustCompile(`\bchange_table\s*\(?\s*` + railsArg)
+	railsDropTable       = regexp.MustCompile(`\bdrop_table\s*\(?\s*` + railsArg)
+	railsRenameTable     = regexp.MustCompile(`\brename_table\s*\(?\s*` + railsArg + `\s*,\s*` + railsArg)
+	railsAddColumn       = regexp.MustCompile(`\badd_column\s*\(?\s*` + railsArg + `\s*,\s*` + railsArg + `(?:\s*,\s*` + railsArg + `)?`)
+	railsRemoveColumn    = regexp.MustCompile(`\bremove_columns?\s*\(?\s*` + railsArg + `\s*,\s*` + railsArg)
+	railsRenameColumn    = regexp.MustCompile(`\brename_column\s*\(?\s*` + railsArg + `\s*,\s*` + railsArg + `\s*,\s*` + railsArg)
+	railsChangeColumn    = regexp.MustCompile(`\bchange_column\s*\(?\s*` + railsArg + `\s*,\s*` + railsArg + `\s*,\s*` + railsArg)
+	railsAddIndex        = regexp.MustCompile(`\badd_index\s*\(?\s*` + railsArg + `\s*,\s*(.+)$`)
+	railsRemoveIndex     = regexp.MustCompile(`\bremove_index\s*\(?\s*` + railsArg + `(.*)$`)
+	railsAddReference    = regexp.MustCompile(`\badd_(?:reference|belongs_to)\s*\(?\s*` + railsArg + `\s*,\s*` + railsArg)
+	railsRemoveReference = regexp.MustCompile(`\bremove_(?:reference|belongs_to)\s*\(?\s*` + railsArg + `\s*,\s*` + railsArg)
+	railsAddForeignKey   = regexp.MustCompile(`\badd_foreign_key\s*\(?\s*` + railsArg + `\s*,\s*` + railsArg)
+	railsRemoveFK        = regexp.MustCompile(`\bremove_foreign_key\s*\(?\s*` + railsArg + `\s*,\s*` + railsArg)
+	railsIndexName       = regexp.MustCompile(`\bname:\s*` + railsArg)
+
+	// Column helpers inside a change_table block
+	railsTableRemove = regexp.MustCompile(`^\s*t\.remove\s+` + railsArg)
+	railsTableRename = regexp.MustCompile(`^\s*t\.rename\s+` + railsArg + `\s*,\s*` + railsArg)
+	railsTableIndex  = regexp.MustCompile(`^\s*t\.index\s+(.+)$`)
+	railsTableColumn = regexp.MustCompile(`^\s*t\.(\w+)\s+` + railsArg)
+)
+
+// parseRails extracts schema operations from a Rails migration, skipping "down" methods
+func parseRails(text string) []Operation {
+	var operations []Operation
+	inDown := false
+	changeTable := ""
+
+	for _, line := range strings.Split(text, "\n") {
+		if railsMethod.MatchString(line) {
+			inDown = railsDownMethod.MatchString(line)
+			changeTable = ""
+		}
+		if inDown {
+			continue
+		}
+
+		switch {
+		case railsCreateTable.MatchString(line):
+			m := railsCreateTable.FindStringSubmatch(line)
+			operations = append(operations, Operation{Kind: OpCreateTable, Table: m[1]})
+			changeTable = ""
+		case railsChangeTable.MatchString(line):
+			changeTable = railsChangeTable.FindStringSubmatch(line)[1]
+		case railsDropTable.MatchString(line):
+			m := railsDropTable.FindStringSubmatch(line)
+			operations = append(operations, Operation{Kind: OpDropTable, Table: m[1]})
+		case railsRenameTable.MatchString(line):
+			m := railsRenameTable.FindStringSubmatch(line)
+			operations = append(operations, Operation{Kind: OpRenameTable, Table: m[1], NewName: m[2]})
+		case railsAddColumn.MatchString(line):
+			m := railsAddColumn.FindStringSubmatch(line)
+			operations = append(operations, Operation{Kind: OpAddColumn, Table: m[1], Name: m[2], Detail: m[3]})
+		case railsRemoveColumn.MatchString(line):
+			m := railsRemoveColumn.FindStringSubmatch(line)
+			operations = append(operations, Operation{Kind: OpDropColumn, Table: m[1], Name: m[2]})
+		case railsRenameColumn.MatchString(line):
+			m := railsRenameColumn.FindStringSubmatch(line)
+			operations = append(operations, Operation{Kind: OpRenameColumn, Table: m[1], Name: m[2], NewName: m[3]})
+		case railsChangeColumn.MatchString(line):
+			m := railsChangeColumn.FindStringSubmatch(line)
+			operations = append(operations, Operation{Kind: OpAlterColumn, Table: m[1], Name: m[2], Detail: "type " + m[3], TypeChange: true})
+		case railsAddIndex.MatchString(line):
+			m := railsAddIndex.FindStringSubmatch(line)
+			operations = append(operations, Operation{Kind: OpCreateIndex, Table: m[1], Name: railsIndex(m[2])})
+		case railsRemoveIndex.MatchString(line):
+			m := railsRemoveIndex.FindStringSubmatch(line)
+			operations = append(operations, Operation{Kind: OpDropIndex, Table: m[1], Name: railsIndex(m[2])})
+		case railsAddReference.MatchString(line):
+			m := railsAddReference.FindStringSubmatch(line)
+			operations = append(operations, Operation{Kind: OpAddColumn, Table: m[1], Name: m[2] + "_id", Detail: "reference"})
+		case railsRemoveReference.MatchString(line):
+			m := railsRemoveReference.FindStringSubmatch(line)
+			operations = append(operations, Operation{Kind: OpDropColumn, Table: m[1], Name: m[2] + "_id"})
+		case railsAddForeignKey.MatchString(line):
+			m := railsAddForeignKey.FindStringSubmatch(line)
+			operations = append(operations, Operation{Kind: OpAddConstraint, Table: m[1], Name: "foreign key to " + m[2]})
+		case railsRemoveFK.MatchString(line):
+			m := railsRemoveFK.FindStringSubmatch(line)
+			operations = append(operations, Operation{Kind: OpDropConstraint, Table: m[1], Name: "foreign key to " + m[2]})
+		case changeTable != "":
+			operations = append(operations, parseRailsChangeTable(changeTable, line)...)
+		}
+	}
+	return operations
+}
+
+// parseRailsChangeTable interprets a "t.<helper>" line inside a change_table block
+func parseRailsChangeTable(table, line string) []Operation {
+	if m := railsTableRemove.FindStringSubmatch(line); m != nil {
+		return []Operation{{Kind: OpDropColumn, Table: table, Name: m[1]}}
+	}
+	if m := railsTableRename.FindStringSubmatch(line); m != nil {
+		return []Operation{{Kind: OpRenameColumn, Table: table, Name: m[1], NewName: m[2]}}
+	}
+	if m := railsTableIndex.FindStringSubmatch(line); m != nil {
+		return []Operation{{Kind: OpCreateIndex, Table: table, Name: railsIndex(m[1])}}
+	}
+	if m := railsTableColumn.FindStringSubmatch(line); m != nil {
+		switch m[1] {
+		case "timestamps":
+			return nil
+		case "references", "belongs_to":
+			return []Operation{{Kind: OpAddColumn, Table: table, Name: m[2] + "_id", Detail: "reference"}}
+		}
+		return []Operation{{Kind: OpAddColumn, Table: table, Name: m[2], Detail: m[1]}}
+	}
+	return nil
+}
+
+// railsIndex returns the explicit index name, or the indexed columns
+func railsIndex(args string) string {
+	if m := railsIndexName.FindStringSubmatch(args); m != nil {
+		return m[1]
+	}
+	columns := strings.SplitN(args, ",", 2)[0]
+	if strings.HasPrefix(strings.TrimSpace(columns), "[") {
+		columns = args[:strings.Index(args, "]")+1]
+	}
+	return strings.NewReplacer(":", "", "\"", "", "'", "", " ", "").Replace(strings.TrimSpace(columns))
+}
+
+var (
+	djangoOperation = regexp.MustCompile(`migrations\.(\w+)\(`)
+	djangoKeyword   = regexp.MustCompile(`\b(\w+)\s*=\s*["']([^"']+)["']`)
+)
+
+// parseDjango extracts schema operations from a Django migration module
+func parseDjango(text string) []Operation {
+	var operations []Operation
+	locations := djangoOperation.FindAllStringSubmatchIndex(text, -1)
+
+	for i, loc := range locations {
+		end := len(text)
+		if i+1 < len(locations) {
+			end = locations[i+1][0]
+		}
+		name := text[loc[2]:loc[3]]
+
+		// Keep the first occurrence of each keyword: nested calls come later
+		args := make(map[string]string)
+		for _, kw := range djangoKeyword.FindAllStringSubmatch(text[loc[1]:end], -1) {
+			if _, exists := args[kw[1]]; !exists {
+				args[kw[1]] = kw[2]
+			}
+		}
+		model := strings.ToLower(args["model_name"])
+
+		switch name {
+		case "CreateModel":
+			operations = append(operations, Operation{Kind: OpCreateTable, Table: strings.ToLower(args["name"])})
+		case "DeleteModel":
+			operations = append(operations, Operation{Kind: OpDropTable, Table: strings.ToLower(args["name"])})
+		case "RenameModel":
+			operations = append(operations, Operation{Kind: OpRenameTable, Table: strings.ToLower(args["old_name"]), NewName: strings.ToLower(args["new_name"])})
+		case "AddField":
+			operations = append(operations, Operation{Kind: OpAddColumn, Table: model, Name: args["name"]})
+		case "RemoveField":
+			operations = append(operations, Operation{Kind: OpDropColumn, Table: model, Name: args["name"]})
+		case "RenameField":
+			operations = append(operations, Operation{Kind: OpRenameColumn, Table: model, Name: args["old_name"], NewName: args["new_name"]})
+		case "AlterField":
+			operations = append(operations, Operation{Kind: OpAlterColumn, Table: model, Name: args["name"]})
+		case "AddIndex":
+			operations = append(operations, Operation{Kind: OpCreateIndex, Table: model, Name: args["name"]})
+		case "RemoveIndex":
+			operations = append(operations, Operation{Kind: OpDropIndex, Table: model, Name: args["name"]})
+		case "AddConstraint":
+			operations = append(operations, Operation{Kind: OpAddConstraint, Table: model, Name: args["name"]})
+		case "RemoveConstraint":
+			operations = append(operations, Operation{Kind: OpDropConstraint, Table: model, Name: args["name"]})
+		case "RunSQL":
+			// reverse_sql only runs when the migration is rolled back
+			forward := text[loc[1]:end]
+			if reverse := strings.Index(forward, "reverse_sql"); reverse >= 0 {
+				forward = forward[:reverse]
+			}
+			operations = append(operations, parseSQL(strings.Join(djangoStrings(forward), "\n"))...)
+		}
+	}
+	return operations
+}
+
+// djangoStringLiteral matches single- and triple-quoted Python strings
+var djangoStringLiteral = regexp.MustCompile(`(?s)"""(.*?)"""|'''(.*?)'''|"([^"\n]*)"|'([^'\n]*)'`)
+
+// djangoStrings returns the contents of the string literals in a RunSQL call
+func djangoStrings(text string) []string {
+	var result []string
+	for _, m := range djangoStringLiteral.FindAllStringSubmatch(text, -1) {
+		for _, group := range m[1:] {
+			if group != "" {
+				result = append(result, group)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// liquibaseChange is one change type from a Liquibase changelog, in any format
+type liquibaseChange struct {
+	kind    string
+	attrs   map[string]string
+	columns []string
+}
+
+// liquibaseKinds are the change types that alter the schema
+var liquibaseKinds = map[string]bool{
+	"createTable": true, "dropTable": true, "renameTable": true,
+	"addColumn": true, "dropColumn": true, "renameColumn": true, "modifyDataType": true,
+	"createIndex": true, "dropIndex": true,
+	"addForeignKeyConstraint": true, "dropForeignKeyConstraint": true,
+	"addUniqueConstraint": true, "dropUniqueConstraint": true,
+	"addPrimaryKey": true, "dropPrimaryKey": true,
+	"addNotNullConstraint": true, "dropNotNullConstraint": true,
+}
+
+var (
+	liquibaseTag       = regexp.MustCompile(`<(\w+)\b([^>]*)>`)
+	liquibaseAttribute = regexp.MustCompile(`(\w+)\s*=\s*"([^"]*)"`)
+	liquibaseKindLine  = regexp.MustCompile(`^\s*-?\s*"?(\w+)"?\s*:\s*\{?\s*$`)
+	liquibaseValueLine = regexp.MustCompile(`^\s*-?\s*"?(\w+)"?\s*:\s*"?([^"{}\[\],]+?)"?\s*,?\s*$`)
+)
+
+// parseLiquibase extracts schema operations from an XML, YAML, JSON or SQL changelog
+func parseLiquibase(text, ext string) []Operation {
+	var changes []*liquibaseChange
+
+	switch strings.ToLower(ext) {
+	case ".sql":
+		return parseSQL(text)
+	case ".xml":
+		var current *liquibaseChange
+		for _, tag := range liquibaseTag.FindAllStringSubmatch(text, -1) {
+			attrs := make(map[string]string)
+			for _, attr := range liquibaseAttribute.FindAllStringSubmatch(tag[2], -1) {
+				attrs[attr[1]] = attr[2]
+			}
+			switch {
+			case liquibaseKinds[tag[1]]:
+				current = &liquibaseChange{kind: tag[1], attrs: attrs}
+				changes = append(changes, current)
+			case tag[1] == "column" && current != nil:
+				current.columns = append(current.columns, attrs["name"])
+			}
+		}
+	default:
+		// YAML and JSON share a "key: value" shape once quotes and commas are ignored
+		var current *liquibaseChange
+		for _, line := range strings.Split(text, "\n") {
+			if m := liquibaseKindLine.FindStringSubmatch(line); m != nil {
+				if liquibaseKinds[m[1]] {
+					current = &liquibaseChange{kind: m[1], attrs: make(map[string]string)}
+					changes = append(changes, current)
+				}
+				continue
+			}
+			if m := liquibaseValueLine.FindStringSubmatch(line); m != nil && current != nil {
+				if m[1] == "name" {
+					current.columns = append(current.columns, strings.TrimSpace(m[2]))
+				} else {
+					current.attrs[m[1]] = strings.TrimSpace(m[2])
+				}
+			}
+		}
+	}
+
+	var operations []Operation
+	for _, change := range changes {
+		operations = append(operations, change.operations()...)
+	}
+	return operations
+}
+
+// operations converts a Liquibase change into schema operations
+func (c *liquibaseChange) operations() []Operation {
+	table := c.attrs["tableName"]
+	switch c.kind {
+	case "createTable":
+		return []Operation{{Kind: OpCreateTable, Table: table}}
+	case "dropTable":
+		return []Operation{{Kind: OpDropTable, Table: table}}
+	case "renameTable":
+		return []Operation{{Kind: OpRenameTable, Table: c.attrs["oldTableName"], NewName: c.attrs["newTableName"]}}
+	case "addColumn":
+		var operations []Operation
+		for _, column := range c.columns {
+			operations = append(operations, Operation{Kind: OpAddColumn, Table: table, Name: column})
+		}
+		return operations
+	case "dropColumn":
+		columns := c.columns
+		if name := c.attrs["columnName"]; name != "" {
+			columns = append([]string{name}, columns...)
+		}
+		var operations []Operation
+		for _, column := range columns {
+			operations = append(operations, Operation{Kind: OpDropColumn, Table: table, Name: column})
+		}
+		return operations
+	case "renameColumn":
+		return []Operation{{Kind: OpRenameColumn, Table: table, Name: c.attrs["oldColumnName"], NewName: c.attrs["newColumnName"]}}
+	case "modifyDataType":
+		return []Operation{{Kind: OpAlterColumn, Table: table, Name: c.attrs["columnName"], Detail: "type " + c.attrs["newDataType"], TypeChange: true}}
+	case "addNotNullConstraint":
+		return []Operation{{Kind: OpAlterColumn, Table: table, Name: c.attrs["columnName"], Detail: "set not null"}}
+	case "dropNotNullConstraint":
+		return []Operation{{Kind: OpAlterColumn, Table: table, Name: c.attrs["columnName"], Detail: "drop not null"}}
+	case "createIndex":
+		return []Operation{{Kind: OpCreateIndex, Table: table, Name: c.attrs["indexName"]}}
+	case "dropIndex":
+		return []Operation{{Kind: OpDropIndex, Table: table, Name: c.attrs["indexName"]}}
+	case "addForeignKeyConstraint", "addUniqueConstraint", "addPrimaryKey":
+		return []Operation{{Kind: OpAddConstraint, Table: firstNonEmpty(table, c.attrs["baseTableName"]), Name: c.attrs["constraintName"]}}
+	case "dropForeignKeyConstraint", "dropUniqueConstraint", "dropPrimaryKey":
+		return []Operation{{Kind: OpDropConstraint, Table: firstNonEmpty(table, c.attrs["baseTableName"]), Name: c.attrs["constraintName"]}}
+	}
+	return nil
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,261 @@
+package schema
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/SilverFlin/DrDuck/internal/diff"
+)
+
+// Framework identifies the migration tool a file belongs to
+type Framework string
+
+const (
+	FrameworkGolangMigrate Framework = "golang-migrate"
+	FrameworkFlyway        Framework = "flyway"
+	FrameworkLiquibase     Framework = "liquibase"
+	FrameworkRails         Framework = "rails"
+	FrameworkDjango        Framework = "django"
+	FrameworkSQL           Framework = "sql"
+)
+
+// OpKind classifies a single schema operation
+type OpKind string
+
+const (
+	OpCreateTable    OpKind = "create table"
+	OpDropTable      OpKind = "drop table"
+	OpRenameTable    OpKind = "rename table"
+	OpTruncateTable  OpKind = "truncate table"
+	OpAddColumn      OpKind = "add column"
+	OpDropColumn     OpKind = "drop column"
+	OpRenameColumn   OpKind = "rename column"
+	OpAlterColumn    OpKind = "alter column"
+	OpCreateIndex    OpKind = "create index"
+	OpDropIndex      OpKind = "drop index"
+	OpAddConstraint  OpKind = "add constraint"
+	OpDropConstraint OpKind = "drop constraint"
+)
+
+// Operation is a single schema change found in a migration
+type Operation struct {
+	Kind       OpKind
+	Table      string
+	Name       string // Column, index or constraint name, depending on Kind
+	NewName    string // Target name for renames
+	Detail     string // Extra information, e.g. the new column type
+	TypeChange bool   // The column type is redefined, which may lose data
+}
+
+// Destructive reports whether the operation can lose data or break existing readers
+func (o Operation) Destructive() bool {
+	switch o.Kind {
+	case OpDropTable, OpTruncateTable, OpDropColumn, OpRenameTable, OpRenameColumn:
+		return true
+	case OpAlterColumn:
+		return o.TypeChange
+	}
+	return false
+}
+
+// String renders the operation as a single human-readable line
+func (o Operation) String() string {
+	var target string
+	switch o.Kind {
+	case OpCreateTable, OpDropTable, OpTruncateTable:
+		target = o.Table
+	case OpRenameTable:
+		target = fmt.Sprintf("%s → %s", o.Table, o.NewName)
+	case OpRenameColumn:
+		target = fmt.Sprintf("%s.%s → %s", o.Table, o.Name, o.NewName)
+	case OpCreateIndex, OpDropIndex, OpAddConstraint, OpDropConstraint:
+		target = o.Name
+		if o.Table != "" {
+			if target == "" {
+				target = "on " + o.Table
+			} else {
+				target += " on " + o.Table
+			}
+		}
+	default:
+		target = o.Table + "." + o.Name
+	}
+
+	line := fmt.Sprintf("%s %s", o.Kind, target)
+	if o.Detail != "" {
+		line += fmt.Sprintf(" (%s)", o.Detail)
+	}
+	return line
+}
+
+// Migration holds the schema operations added by one migration file
+type Migration struct {
+	Path       string
+	Framework  Framework
+	Rollback   bool // Down/undo migrations only revert other changes
+	Operations []Operation
+}
+
+// Destructive returns the operations that can lose data. Rollback
+// migrations are exempt, since dropping what the up migration created is expected.
+func (m Migration) Destructive() []Operation {
+	if m.Rollback {
+		return nil
+	}
+	var destructive []Operation
+	for _, op := range m.Operations {
+		if op.Destructive() {
+			destructive = append(destructive, op)
+		}
+	}
+	return destructive
+}
+
+// flywayPattern matches versioned (V), undo (U) and repeatable (R) Flyway scripts
+var flywayPattern = regexp.MustCompile(`^[VUR][\d._]*__.+\.sql$`)
+
+// djangoPattern matches numbered Django migration modules
+var djangoPattern = regexp.MustCompile(`^\d{4}_\w+\.py$`)
+
+// liquibaseExtensions are the changelog formats Liquibase accepts
+var liquibaseExtensions = map[string]bool{".xml": true, ".yaml": true, ".yml": true, ".json": true, ".sql": true}
+
+// migrationDirs are directory names that mark plain SQL files as migrations
+var migrationDirs = map[string]bool{"migrations": true, "migration": true, "migrate": true, "schema": true, "sql": true, "db": true}
+
+// Detect reports which migration framework the path belongs to, if any
+func Detect(filePath string) (Framework, bool) {
+	filePath = strings.TrimPrefix(toSlash(filePath), "/")
+	base := path.Base(filePath)
+	ext := strings.ToLower(path.Ext(base))
+	padded := "/" + strings.ToLower(filePath)
+
+	switch {
+	case strings.HasSuffix(base, ".up.sql") || strings.HasSuffix(base, ".down.sql"):
+		return FrameworkGolangMigrate, true
+	case flywayPattern.MatchString(base):
+		return FrameworkFlyway, true
+	case liquibaseExtensions[ext] && (strings.Contains(strings.ToLower(base), "changelog") || strings.Contains(padded, "/changelog/") || strings.Contains(padded, "/liquibase/")):
+		return FrameworkLiquibase, true
+	case ext == ".rb" && strings.Contains(padded, "/db/migrate/"):
+		return FrameworkRails, true
+	case ext == ".py" && strings.Contains(padded, "/migrations/") && djangoPattern.MatchString(base):
+		return FrameworkDjango, true
+	case ext == ".sql":
+		for _, dir := range strings.Split(path.Dir(strings.ToLower(filePath)), "/") {
+			if migrationDirs[dir] {
+				return FrameworkSQL, true
+			}
+		}
+	}
+	return "", false
+}
+
+// IsMigration reports whether the path is a migration file DrDuck understands
+func IsMigration(filePath string) bool {
+	_, ok := Detect(filePath)
+	return ok
+}
+
+// isRollback reports whether the migration only reverts another one
+func isRollback(framework Framework, filePath string) bool {
+	base := path.Base(toSlash(filePath))
+	switch framework {
+	case FrameworkGolangMigrate:
+		return strings.HasSuffix(base, ".down.sql")
+	case FrameworkFlyway:
+		return strings.HasPrefix(base, "U")
+	}
+	return false
+}
+
+// Analyze extracts the schema operations added by every migration in the diff.
+// Deleted migration files are ignored.
+func Analyze(files []*diff.File) []Migration {
+	var migrations []Migration
+	for _, file := range files {
+		if file.Status == diff.StatusDeleted || file.Binary {
+			continue
+		}
+		framework, ok := Detect(file.Path())
+		if !ok {
+			continue
+		}
+
+		// Only added lines count: context lines belong to migrations that already ran
+		text := strings.Join(file.AddedLines(), "\n")
+
+		var operations []Operation
+		switch framework {
+		case FrameworkRails:
+			operations = parseRails(text)
+		case FrameworkDjango:
+			operations = parseDjango(text)
+		case FrameworkLiquibase:
+			operations = parseLiquibase(text, path.Ext(file.Path()))
+		default:
+			operations = parseSQL(text)
+		}
+
+		migrations = append(migrations, Migration{
+			Path:       file.Path(),
+			Framework:  framework,
+			Rollback:   isRollback(framework, file.Path()),
+			Operations: operations,
+		})
+	}
+	return migrations
+}
+
+// Destructive returns the migrations that contain destructive operations
+func Destructive(migrations []Migration) []Migration {
+	var result []Migration
+	for _, migration := range migrations {
+		if len(migration.Destructive()) > 0 {
+			result = append(result, migration)
+		}
+	}
+	return result
+}
+
+// Summarize renders the migrations and their operations as a markdown list
+func Summarize(migrations []Migration) string {
+	var builder strings.Builder
+	for _, migration := range migrations {
+		builder.WriteString(fmt.Sprintf("- %s (%s", migration.Path, migration.Framework))
+		if migration.Rollback {
+			builder.WriteString(", rollback")
+		}
+		builder.WriteString(")\n")
+
+		if len(migration.Operations) == 0 {
+			builder.WriteString("  - no schema operations recognized\n")
+			continue
+		}
+		for _, op := range migration.Operations {
+			builder.WriteString("  - ")
+			builder.WriteString(op.String())
+			if op.Destructive() && !migration.Rollback {
+				builder.WriteString(" ⚠️ destructive")
+			}
+			builder.WriteString("\n")
+		}
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+// toSlash normalizes Windows separators so path matching works everywhere
+func toSlash(filePath string) string {
+	return strings.ReplaceAll(filePath, "\\", "/")
+}
+
+// identifierQuotes removes SQL identifier quoting, including inside qualified names
+var identifierQuotes = strings.NewReplacer("\"", "", "`", "", "[", "", "]", "")
+
+// unquote strips SQL and ORM identifier quoting
+func unquote(identifier string) string {
+	identifier = identifierQuotes.Replace(strings.TrimSpace(identifier))
+	return strings.Trim(identifier, "':")
+}
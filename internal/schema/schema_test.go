@@ -0,0 +1,258 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/SilverFlin/DrDuck/internal/diff"
+)
+
+// addedFileDiff renders a fixture under testdata as the diff of a new file
+func addedFileDiff(t *testing.T, filePath string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join("testdata", filePath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+
+	var builder strings.Builder
+	builder.WriteString("diff --git a/" + filePath + " b/" + filePath + "\n")
+	builder.WriteString("new file mode 100644\n--- /dev/null\n+++ b/" + filePath + "\n")
+	builder.WriteString("@@ -0,0 +1," + strconv.Itoa(len(lines)) + " @@\n")
+	for _, line := range lines {
+		builder.WriteString("+" + line + "\n")
+	}
+	return builder.String()
+}
+
+func TestAnalyzeFixtures(t *testing.T) {
+	tests := []struct {
+		path        string
+		framework   Framework
+		rollback    bool
+		operations  []string
+		destructive []string
+	}{
+		{
+			path:      "db/migrations/000002_rename_users.up.sql",
+			framework: FrameworkGolangMigrate,
+			operations: []string{
+				"rename column users.name → full_name",
+				"rename table accounts → customers",
+				"drop column public.orders.legacy_id",
+				"add column public.orders.total (numeric(10, 2))",
+				"alter column public.orders.status (type varchar(32))",
+				"create index idx_orders_total on orders",
+				"create table audit_log",
+			},
+			destructive: []string{
+				"rename column users.name → full_name",
+				"rename table accounts → customers",
+				"drop column public.orders.legacy_id",
+				"alter column public.orders.status (type varchar(32))",
+			},
+		},
+		{
+			path:      "db/migrations/000002_rename_users.down.sql",
+			framework: FrameworkGolangMigrate,
+			rollback:  true,
+			operations: []string{
+				"drop table audit_log",
+				"rename table customers → accounts",
+				"rename column users.full_name → name",
+			},
+		},
+		{
+			path:      "flyway/V3__mysql_cleanup.sql",
+			framework: FrameworkFlyway,
+			operations: []string{
+				"rename column users.email → email_address (type varchar(255))",
+				"alter column users.age (type bigint unsigned)",
+				"drop index idx_email on users",
+				"create index uq_email on users",
+				"truncate table sessions",
+				"drop table tmp_import",
+				"drop table tmp_export",
+				"rename table logins → sign_ins",
+			},
+			destructive: []string{
+				"rename column users.email → email_address (type varchar(255))",
+				"alter column users.age (type bigint unsigned)",
+				"truncate table sessions",
+				"drop table tmp_import",
+				"drop table tmp_export",
+				"rename table logins → sign_ins",
+			},
+		},
+		{
+			path:      "rails/db/migrate/20240301120000_rename_profile_fields.rb",
+			framework: FrameworkRails,
+			operations: []string{
+				"rename column users.name → full_name",
+				"drop column users.legacy_token",
+				"add column users.locale (string)",
+				"add column posts.author_id (reference)",
+				"create index [email,locale] on users",
+				"drop column posts.draft",
+				"rename column posts.title → headline",
+				"add column posts.published_at (datetime)",
+				"alter column orders.amount (type decimal)",
+			},
+			destructive: []string{
+				"rename column users.name → full_name",
+				"drop column users.legacy_token",
+				"drop column posts.draft",
+				"rename column posts.title → headline",
+				"alter column orders.amount (type decimal)",
+			},
+		},
+		{
+			path:      "shop/migrations/0007_rename_customer.py",
+			framework: FrameworkDjango,
+			operations: []string{
+				"rename column customer.name → full_name",
+				"drop column order.legacy_id",
+				"add column order.currency",
+				"rename table voucher → coupon",
+				"drop column shop_order.notes",
+			},
+			destructive: []string{
+				"rename column customer.name → full_name",
+				"drop column order.legacy_id",
+				"rename table voucher → coupon",
+				"drop column shop_order.notes",
+			},
+		},
+		{
+			path:      "liquibase/changelog-2024.xml",
+			framework: FrameworkLiquibase,
+			operations: []string{
+				"rename column users.name → full_name",
+				"drop column users.legacy_token",
+				"drop column users.legacy_salt",
+				"add column users.locale",
+				"alter column orders.amount (type decimal(12,2))",
+			},
+			destructive: []string{
+				"rename column users.name → full_name",
+				"drop column users.legacy_token",
+				"drop column users.legacy_salt",
+				"alter column orders.amount (type decimal(12,2))",
+			},
+		},
+		{
+			path:      "liquibase/changelog-2024.yaml",
+			framework: FrameworkLiquibase,
+			operations: []string{
+				"rename table vouchers → coupons",
+				"drop column orders.legacy_id",
+				"create index idx_orders_created on orders",
+			},
+			destructive: []string{
+				"rename table vouchers → coupons",
+				"drop column orders.legacy_id",
+			},
+		},
+		{
+			path:      "sql/seed_reference_data.sql",
+			framework: FrameworkSQL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			migrations := Analyze(diff.Parse(addedFileDiff(t, tt.path)))
+			if len(migrations) != 1 {
+				t.Fatalf("Analyze returned %d migrations, want 1", len(migrations))
+			}
+			migration := migrations[0]
+
+			if migration.Path != tt.path || migration.Framework != tt.framework || migration.Rollback != tt.rollback {
+				t.Errorf("migration = %s (%s, rollback %v), want %s (%s, rollback %v)",
+					migration.Path, migration.Framework, migration.Rollback, tt.path, tt.framework, tt.rollback)
+			}
+			if got := operationStrings(migration.Operations); !reflect.DeepEqual(got, tt.operations) {
+				t.Errorf("operations =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.operations, "\n"))
+			}
+			if got := operationStrings(migration.Destructive()); !reflect.DeepEqual(got, tt.destructive) {
+				t.Errorf("destructive =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.destructive, "\n"))
+			}
+		})
+	}
+}
+
+func operationStrings(operations []Operation) []string {
+	var result []string
+	for _, op := range operations {
+		result = append(result, op.String())
+	}
+	return result
+}
+
+func TestAnalyzeOnlyCountsAddedLines(t *testing.T) {
+	raw := `diff --git a/db/migrations/001_init.sql b/db/migrations/001_init.sql
+--- a/db/migrations/001_init.sql
++++ b/db/migrations/001_init.sql
+@@ -1,2 +1,2 @@
+ ALTER TABLE users DROP COLUMN nickname;
+-ALTER TABLE users ADD COLUMN bio text;
++ALTER TABLE users RENAME COLUMN bio TO about;
+diff --git a/db/migrations/000_legacy.sql b/db/migrations/000_legacy.sql
+deleted file mode 100644
+--- a/db/migrations/000_legacy.sql
++++ /dev/null
+@@ -1 +0,0 @@
+-DROP TABLE legacy;
+diff --git a/README.md b/README.md
+--- a/README.md
++++ b/README.md
+@@ -1 +1 @@
+-DROP TABLE docs;
++ALTER TABLE docs DROP COLUMN title;
+`
+	migrations := Analyze(diff.Parse(raw))
+	if len(migrations) != 1 {
+		t.Fatalf("Analyze returned %d migrations, want only the modified one", len(migrations))
+	}
+	want := []string{"rename column users.bio → about"}
+	if got := operationStrings(migrations[0].Operations); !reflect.DeepEqual(got, want) {
+		t.Errorf("operations = %v, want %v", got, want)
+	}
+
+	summary := Summarize(Destructive(migrations))
+	if !strings.Contains(summary, "rename column users.bio → about ⚠️ destructive") {
+		t.Errorf("summary does not flag the rename:\n%s", summary)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		path      string
+		framework Framework
+		ok        bool
+	}{
+		{"migrations/000001_init.up.sql", FrameworkGolangMigrate, true},
+		{"src/main/resources/db/migration/V1_2__add_users.sql", FrameworkFlyway, true},
+		{"src/main/resources/db/migration/R__views.sql", FrameworkFlyway, true},
+		{"db/changelog/db.changelog-master.yaml", FrameworkLiquibase, true},
+		{"db/migrate/20240101000000_create_users.rb", FrameworkRails, true},
+		{"app/migrations/0001_initial.py", FrameworkDjango, true},
+		{"app/migrations/__init__.py", "", false},
+		{"schema/tables.sql", FrameworkSQL, true},
+		{`db\seed.sql`, FrameworkSQL, true},
+		{"queries/report.sql", "", false},
+		{"app/models/user.rb", "", false},
+	}
+	for _, tt := range tests {
+		framework, ok := Detect(tt.path)
+		if framework != tt.framework || ok != tt.ok {
+			t.Errorf("Detect(%q) = %q, %v, want %q, %v", tt.path, framework, ok, tt.framework, tt.ok)
+		}
+	}
+}
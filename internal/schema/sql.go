@@ -0,0 +1,194 @@
+package schema
+
+import (
+	"regexp"
+	"strings"
+)
+
+// identifier matches a possibly schema-qualified, possibly quoted SQL identifier
+const identifier = "(?:[\\w$]+|\"[^\"]+\"|`[^`]+`|\\[[^\\]]+\\])(?:\\.(?:[\\w$]+|\"[^\"]+\"|`[^`]+`|\\[[^\\]]+\\]))?"
+
+var (
+	sqlLineComment  = regexp.MustCompile(`--[^\n]*`)
+	sqlBlockComment = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	sqlWhitespace   = regexp.MustCompile(`\s+`)
+
+	sqlCreateTable = regexp.MustCompile(`(?i)^CREATE\s+(?:OR\s+REPLACE\s+)?(?:(?:GLOBAL\s+|LOCAL\s+)?(?:TEMP|TEMPORARY)\s+|UNLOGGED\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(` + identifier + `)`)
+	sqlDropTable   = regexp.MustCompile(`(?i)^DROP\s+TABLE\s+(?:IF\s+EXISTS\s+)?(.+?)(?:\s+(?:CASCADE|RESTRICT))?$`)
+	sqlTruncate    = regexp.MustCompile(`(?i)^TRUNCATE\s+(?:TABLE\s+)?(?:ONLY\s+)?(` + identifier + `)`)
+	sqlRenameTable = regexp.MustCompile(`(?i)^RENAME\s+TABLE\s+(` + identifier + `)\s+TO\s+(` + identifier + `)`)
+	sqlAlterTable  = regexp.MustCompile(`(?i)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?(` + identifier + `)\s+(.+)$`)
+	sqlCreateIndex = regexp.MustCompile(`(?i)^CREATE\s+(?:UNIQUE\s+)?(?:CLUSTERED\s+|NONCLUSTERED\s+)?INDEX\s+(?:CONCURRENTLY\s+)?(?:IF\s+NOT\s+EXISTS\s+)?(` + identifier + `\s+)?ON\s+(?:ONLY\s+)?(` + identifier + `)`)
+	sqlDropIndex   = regexp.MustCompile(`(?i)^DROP\s+INDEX\s+(?:CONCURRENTLY\s+)?(?:IF\s+EXISTS\s+)?(` + identifier + `)(?:\s+ON\s+(` + identifier + `))?`)
+
+	sqlAddConstraint  = regexp.MustCompile(`(?i)^ADD\s+(?:CONSTRAINT\s+(` + identifier + `)|PRIMARY\s+KEY|FOREIGN\s+KEY|UNIQUE|CHECK)`)
+	sqlAddIndex       = regexp.MustCompile(`(?i)^ADD\s+(?:UNIQUE\s+)?(?:INDEX|KEY)\s+(` + identifier + `)?`)
+	sqlAddColumn      = regexp.MustCompile(`(?i)^ADD\s+(?:COLUMN\s+)?(?:IF\s+NOT\s+EXISTS\s+)?(` + identifier + `)\s*(.*)$`)
+	sqlDropConstraint = regexp.MustCompile(`(?i)^DROP\s+(?:CONSTRAINT\s+(?:IF\s+EXISTS\s+)?(` + identifier + `)|PRIMARY\s+KEY|FOREIGN\s+KEY\s+(` + identifier + `))`)
+	sqlDropIndexAlter = regexp.MustCompile(`(?i)^DROP\s+(?:INDEX|KEY)\s+(` + identifier + `)`)
+	sqlDropColumn     = regexp.MustCompile(`(?i)^DROP\s+(?:COLUMN\s+)?(?:IF\s+EXISTS\s+)?(` + identifier + `)`)
+	sqlRenameTo       = regexp.MustCompile(`(?i)^RENAME\s+TO\s+(` + identifier + `)`)
+	sqlRenameColumn   = regexp.MustCompile(`(?i)^RENAME\s+(?:COLUMN\s+)?(` + identifier + `)\s+TO\s+(` + identifier + `)`)
+	sqlAlterColumn    = regexp.MustCompile(`(?i)^ALTER\s+(?:COLUMN\s+)?(` + identifier + `)\s+(.+)$`)
+	sqlModifyColumn   = regexp.MustCompile(`(?i)^MODIFY\s+(?:COLUMN\s+)?(` + identifier + `)\s+(.+)$`)
+	sqlChangeColumn   = regexp.MustCompile(`(?i)^CHANGE\s+(?:COLUMN\s+)?(` + identifier + `)\s+(` + identifier + `)\s+(.+)$`)
+	sqlTypeChange     = regexp.MustCompile(`(?i)^(?:SET\s+DATA\s+)?TYPE\s+(.+)$`)
+)
+
+// parseSQL extracts schema operations from SQL migration text
+func parseSQL(text string) []Operation {
+	text = sqlBlockComment.ReplaceAllString(text, " ")
+	text = sqlLineComment.ReplaceAllString(text, " ")
+
+	var operations []Operation
+	for _, statement := range splitTopLevel(text, ';') {
+		statement = strings.TrimSpace(sqlWhitespace.ReplaceAllString(statement, " "))
+		if statement == "" {
+			continue
+		}
+		operations = append(operations, parseSQLStatement(statement)...)
+	}
+	return operations
+}
+
+// parseSQLStatement extracts the schema operations of a single statement
+func parseSQLStatement(statement string) []Operation {
+	if m := sqlCreateTable.FindStringSubmatch(statement); m != nil {
+		return []Operation{{Kind: OpCreateTable, Table: unquote(m[1])}}
+	}
+	if m := sqlCreateIndex.FindStringSubmatch(statement); m != nil {
+		return []Operation{{Kind: OpCreateIndex, Name: unquote(m[1]), Table: unquote(m[2])}}
+	}
+	if m := sqlDropTable.FindStringSubmatch(statement); m != nil {
+		var operations []Operation
+		for _, table := range strings.Split(m[1], ",") {
+			operations = append(operations, Operation{Kind: OpDropTable, Table: unquote(table)})
+		}
+		return operations
+	}
+	if m := sqlDropIndex.FindStringSubmatch(statement); m != nil {
+		return []Operation{{Kind: OpDropIndex, Name: unquote(m[1]), Table: unquote(m[2])}}
+	}
+	if m := sqlTruncate.FindStringSubmatch(statement); m != nil {
+		return []Operation{{Kind: OpTruncateTable, Table: unquote(m[1])}}
+	}
+	if m := sqlRenameTable.FindStringSubmatch(statement); m != nil {
+		return []Operation{{Kind: OpRenameTable, Table: unquote(m[1]), NewName: unquote(m[2])}}
+	}
+	if m := sqlAlterTable.FindStringSubmatch(statement); m != nil {
+		table := unquote(m[1])
+		var operations []Operation
+		for _, action := range splitTopLevel(m[2], ',') {
+			if op, ok := parseAlterAction(table, strings.TrimSpace(action)); ok {
+				operations = append(operations, op)
+			}
+		}
+		return operations
+	}
+	return nil
+}
+
+// parseAlterAction interprets one comma-separated action of an ALTER TABLE statement
+func parseAlterAction(table, action string) (Operation, bool) {
+	if m := sqlAddIndex.FindStringSubmatch(action); m != nil {
+		return Operation{Kind: OpCreateIndex, Table: table, Name: unquote(m[1])}, true
+	}
+	if m := sqlAddConstraint.FindStringSubmatch(action); m != nil {
+		return Operation{Kind: OpAddConstraint, Table: table, Name: unquote(m[1])}, true
+	}
+	if m := sqlAddColumn.FindStringSubmatch(action); m != nil {
+		return Operation{Kind: OpAddColumn, Table: table, Name: unquote(m[1]), Detail: columnType(m[2])}, true
+	}
+	if m := sqlDropConstraint.FindStringSubmatch(action); m != nil {
+		name := unquote(m[1])
+		if name == "" {
+			name = unquote(m[2])
+		}
+		return Operation{Kind: OpDropConstraint, Table: table, Name: name}, true
+	}
+	if m := sqlDropIndexAlter.FindStringSubmatch(action); m != nil {
+		return Operation{Kind: OpDropIndex, Table: table, Name: unquote(m[1])}, true
+	}
+	if m := sqlDropColumn.FindStringSubmatch(action); m != nil {
+		return Operation{Kind: OpDropColumn, Table: table, Name: unquote(m[1])}, true
+	}
+	if m := sqlRenameTo.FindStringSubmatch(action); m != nil {
+		return Operation{Kind: OpRenameTable, Table: table, NewName: unquote(m[1])}, true
+	}
+	if m := sqlRenameColumn.FindStringSubmatch(action); m != nil {
+		return Operation{Kind: OpRenameColumn, Table: table, Name: unquote(m[1]), NewName: unquote(m[2])}, true
+	}
+	if m := sqlAlterColumn.FindStringSubmatch(action); m != nil {
+		op := Operation{Kind: OpAlterColumn, Table: table, Name: unquote(m[1]), Detail: m[2]}
+		if t := sqlTypeChange.FindStringSubmatch(m[2]); t != nil {
+			op.TypeChange = true
+			op.Detail = "type " + columnType(t[1])
+		}
+		return op, true
+	}
+	if m := sqlModifyColumn.FindStringSubmatch(action); m != nil {
+		return Operation{Kind: OpAlterColumn, Table: table, Name: unquote(m[1]), Detail: "type " + columnType(m[2]), TypeChange: true}, true
+	}
+	if m := sqlChangeColumn.FindStringSubmatch(action); m != nil {
+		oldName, newName := unquote(m[1]), unquote(m[2])
+		if oldName != newName {
+			return Operation{Kind: OpRenameColumn, Table: table, Name: oldName, NewName: newName, Detail: "type " + columnType(m[3])}, true
+		}
+		return Operation{Kind: OpAlterColumn, Table: table, Name: oldName, Detail: "type " + columnType(m[3]), TypeChange: true}, true
+	}
+	return Operation{}, false
+}
+
+// columnType keeps the type part of a column definition, dropping constraints and defaults
+func columnType(definition string) string {
+	fields := strings.Fields(definition)
+	if len(fields) == 0 {
+		return ""
+	}
+	result := fields[0]
+	// Keep multi-word types such as "double precision" or "timestamp with time zone"
+	for _, field := range fields[1:] {
+		upper := strings.ToUpper(field)
+		if upper == "NOT" || upper == "NULL" || upper == "DEFAULT" || upper == "PRIMARY" ||
+			upper == "REFERENCES" || upper == "UNIQUE" || upper == "CHECK" || upper == "USING" ||
+			upper == "COLLATE" || upper == "CONSTRAINT" || upper == "GENERATED" {
+			break
+		}
+		result += " " + field
+	}
+	return strings.ToLower(result)
+}
+
+// splitTopLevel splits text on sep, ignoring separators inside parentheses and quotes
+func splitTopLevel(text string, sep rune) []string {
+	var parts []string
+	var current strings.Builder
+	depth := 0
+	var quote rune
+
+	for _, r := range text {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			if depth > 0 {
+				depth--
+			}
+		case r == sep && depth == 0:
+			parts = append(parts, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	if strings.TrimSpace(current.String()) != "" {
+		parts = append(parts, current.String())
+	}
+	return parts
+}
@@ -0,0 +1,3 @@
+DROP TABLE audit_log;
+ALTER TABLE customers RENAME TO accounts;
+ALTER TABLE users RENAME COLUMN full_name TO name;
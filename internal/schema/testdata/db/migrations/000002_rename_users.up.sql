@@ -0,0 +1,15 @@
+-- Rename the user columns and split legacy order data
+ALTER TABLE users RENAME COLUMN name TO full_name;
+ALTER TABLE accounts RENAME TO customers;
+
+/* Orders keep their totals, not the legacy ids */
+ALTER TABLE "public"."orders"
+    DROP COLUMN IF EXISTS legacy_id,
+    ADD COLUMN total numeric(10, 2) NOT NULL DEFAULT 0,
+    ALTER COLUMN status SET DATA TYPE varchar(32);
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_orders_total ON orders (total);
+CREATE TABLE IF NOT EXISTS audit_log (
+    id bigserial PRIMARY KEY,
+    note text DEFAULT 'a; b'
+);
@@ -0,0 +1,8 @@
+ALTER TABLE `users`
+    CHANGE `email` `email_address` varchar(255) NOT NULL,
+    MODIFY age bigint UNSIGNED,
+    DROP INDEX idx_email,
+    ADD UNIQUE KEY uq_email (email_address);
+TRUNCATE TABLE sessions;
+DROP TABLE IF EXISTS tmp_import, tmp_export CASCADE;
+RENAME TABLE `logins` TO `sign_ins`;
@@ -0,0 +1,22 @@
+class RenameProfileFields < ActiveRecord::Migration[7.1]
+  def up
+    rename_column :users, :name, :full_name
+    remove_column :users, :legacy_token, :string
+    add_column :users, :locale, :string, default: "en"
+    add_reference :posts, :author
+    add_index :users, [:email, :locale], unique: true
+
+    change_table :posts do |t|
+      t.remove :draft
+      t.rename :title, :headline
+      t.datetime :published_at
+      t.timestamps
+    end
+
+    change_column :orders, :amount, :decimal
+  end
+
+  def down
+    drop_table :audit_entries
+  end
+end
@@ -0,0 +1,22 @@
+from django.db import migrations, models
+
+
+class Migration(migrations.Migration):
+    dependencies = [("shop", "0006_order_total")]
+
+    operations = [
+        migrations.RenameField(model_name="customer", old_name="name", new_name="full_name"),
+        migrations.RemoveField(model_name="Order", name="legacy_id"),
+        migrations.AddField(
+            model_name="order",
+            name="currency",
+            field=models.CharField(max_length=3, default="EUR"),
+        ),
+        migrations.RenameModel(old_name="Voucher", new_name="Coupon"),
+        migrations.RunSQL(
+            sql="""
+                ALTER TABLE shop_order DROP COLUMN notes;
+            """,
+            reverse_sql="ALTER TABLE shop_order ADD COLUMN notes text;",
+        ),
+    ]
@@ -0,0 +1,2 @@
+INSERT INTO countries (code, name) VALUES ('NL', 'Netherlands');
+UPDATE settings SET value = 'on' WHERE key = 'signups';
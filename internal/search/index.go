@@ -0,0 +1,183 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/similarity"
+)
+
+const (
+	IndexVersion = "1"
+	IndexDir     = "search"
+	IndexFile    = "index.json"
+)
+
+// Entry is one indexed ADR file
+type Entry struct {
+	Path     string               `json:"path"`
+	ModTime  time.Time            `json:"mod_time"`
+	Size     int64                `json:"size"`
+	ID       int                  `json:"id"`
+	Title    string               `json:"title"`
+	Status   string               `json:"status"`
+	Date     time.Time            `json:"date"`
+	Tags     []string             `json:"tags,omitempty"`
+	Sections []adr.Section        `json:"sections"`
+	Terms    similarity.TermStats `json:"terms"`
+}
+
+// Index is the on-disk full-text index of the project's ADRs
+type Index struct {
+	Version string            `json:"version"`
+	Entries map[string]*Entry `json:"entries"` // By ADR file path
+
+	path  string
+	dirty bool
+}
+
+// Open loads the index stored in the DrDuck config directory. A missing,
+// unreadable or outdated index is replaced by an empty one.
+func Open(configDir string) *Index {
+	index := &Index{
+		Version: IndexVersion,
+		Entries: make(map[string]*Entry),
+		path:    filepath.Join(configDir, IndexDir, IndexFile),
+	}
+
+	data, err := os.ReadFile(index.path)
+	if err != nil {
+		return index
+	}
+
+	var stored Index
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Version != IndexVersion || stored.Entries == nil {
+		index.dirty = true
+		return index
+	}
+	index.Entries = stored.Entries
+	return index
+}
+
+// Load opens the index, re-indexes the ADR files that changed (all of them
+// when rebuild is set) and saves it. The index is only a cache, so a failed
+// save is ignored and just means re-indexing next time.
+func Load(configDir string, adrs []*adr.ADR, rebuild bool) (*Index, error) {
+	index := Open(configDir)
+	if rebuild {
+		index.Reset()
+	}
+	if _, err := index.Update(adrs); err != nil {
+		return nil, fmt.Errorf("failed to update search index: %w", err)
+	}
+	_ = index.Save()
+	return index, nil
+}
+
+// Update re-indexes the ADR files that changed since they were indexed and
+// drops the ones that no longer exist. It returns how many files were re-indexed.
+func (x *Index) Update(adrs []*adr.ADR) (int, error) {
+	seen := make(map[string]bool, len(adrs))
+	reindexed := 0
+
+	for _, a := range adrs {
+		seen[a.FilePath] = true
+
+		info, err := os.Stat(a.FilePath)
+		if err != nil {
+			return reindexed, fmt.Errorf("failed to stat %s: %w", a.FilePath, err)
+		}
+
+		entry, exists := x.Entries[a.FilePath]
+		if !exists || !entry.ModTime.Equal(info.ModTime()) || entry.Size != info.Size() {
+			content, err := os.ReadFile(a.FilePath)
+			if err != nil {
+				return reindexed, fmt.Errorf("failed to read %s: %w", a.FilePath, err)
+			}
+
+			_, body := adr.SplitFrontMatter(string(content))
+			sections := adr.ParseSections(body)
+			entry = &Entry{
+				Path:     a.FilePath,
+				ModTime:  info.ModTime(),
+				Size:     info.Size(),
+				Sections: sections,
+				Terms:    similarity.DocumentTerms(a.Title, sectionText(sections)),
+			}
+			x.Entries[a.FilePath] = entry
+			x.dirty = true
+			reindexed++
+		}
+
+		// Metadata is cheap to refresh and List has already parsed it
+		entry.ID = a.ID
+		entry.Title = a.Title
+		entry.Status = string(a.Status)
+		entry.Date = a.Date
+		entry.Tags = a.Tags
+	}
+
+	for path := range x.Entries {
+		if !seen[path] {
+			delete(x.Entries, path)
+			x.dirty = true
+		}
+	}
+	return reindexed, nil
+}
+
+// Save writes the index if it changed, replacing the file atomically
+func (x *Index) Save() error {
+	if !x.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(x)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search index: %w", err)
+	}
+
+	dir := filepath.Dir(x.path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create search index directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, IndexFile+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary index file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), x.path); err != nil {
+		return fmt.Errorf("failed to replace search index: %w", err)
+	}
+
+	x.dirty = false
+	return nil
+}
+
+// Reset drops all entries so the next Update re-indexes every file
+func (x *Index) Reset() {
+	x.Entries = make(map[string]*Entry)
+	x.dirty = true
+}
+
+// sectionText joins section titles and contents for term counting
+func sectionText(sections []adr.Section) string {
+	var text string
+	for _, section := range sections {
+		text += section.Title + "\n" + section.Content + "\n\n"
+	}
+	return text
+}
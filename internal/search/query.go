@@ -0,0 +1,139 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+)
+
+// Query is a parsed search query: free text plus field filters such as
+// status:accepted, tag:database, after:2025-01-01 and before:2026-01-01
+type Query struct {
+	Text     string    // Free text, ranked with BM25
+	Statuses []string  // Any of these statuses, compared case-insensitively
+	Tags     []string  // All of these tags
+	After    time.Time // ADR date on or after this day
+	Before   time.Time // ADR date before this day
+}
+
+// ParseQuery parses a search query. Filter values containing spaces can be
+// quoted, e.g. status:"in progress".
+func ParseQuery(input string) (Query, error) {
+	var query Query
+	var text []string
+
+	for _, token := range splitQuery(input) {
+		field, value, hasField := strings.Cut(token, ":")
+		value = strings.Trim(value, `"`)
+		if !hasField || value == "" {
+			text = append(text, strings.Trim(token, `"`))
+			continue
+		}
+
+		switch strings.ToLower(field) {
+		case "status":
+			query.Statuses = append(query.Statuses, value)
+		case "tag":
+			query.Tags = append(query.Tags, value)
+		case "after":
+			date, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return query, fmt.Errorf("invalid date in after:%s, expected YYYY-MM-DD", value)
+			}
+			query.After = date
+		case "before":
+			date, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return query, fmt.Errorf("invalid date in before:%s, expected YYYY-MM-DD", value)
+			}
+			query.Before = date
+		default:
+			// Not a filter, e.g. "ADR-0007:" or a URL
+			text = append(text, token)
+		}
+	}
+
+	query.Text = strings.Join(text, " ")
+	return query, nil
+}
+
+// Matches reports whether an entry passes the query's filters
+func (q Query) Matches(entry *Entry) bool {
+	return q.matches(entry.Status, entry.Tags, entry.Date)
+}
+
+// MatchesADR reports whether an ADR passes the query's filters
+func (q Query) MatchesADR(a *adr.ADR) bool {
+	return q.matches(string(a.Status), a.Tags, a.Date)
+}
+
+// matches reports whether an ADR's metadata passes the query's filters
+func (q Query) matches(status string, tags []string, date time.Time) bool {
+	if len(q.Statuses) > 0 {
+		found := false
+		for _, wanted := range q.Statuses {
+			if strings.EqualFold(normalizeStatus(wanted), normalizeStatus(status)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	for _, tag := range q.Tags {
+		found := false
+		for _, adrTag := range tags {
+			if strings.EqualFold(tag, adrTag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if !q.After.IsZero() && date.Before(q.After) {
+		return false
+	}
+	if !q.Before.IsZero() && !date.Before(q.Before) {
+		return false
+	}
+	return true
+}
+
+// normalizeStatus lets status:in-progress match "In Progress"
+func normalizeStatus(status string) string {
+	return strings.NewReplacer("-", " ", "_", " ").Replace(status)
+}
+
+// splitQuery splits a query on whitespace, keeping quoted text together
+func splitQuery(input string) []string {
+	var tokens []string
+	var current strings.Builder
+	inQuotes := false
+
+	for _, r := range input {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			current.WriteRune(r)
+		case unicode.IsSpace(r) && !inQuotes:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
@@ -0,0 +1,126 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(value string) time.Time {
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Query
+		wantErr bool
+	}{
+		{
+			name:  "free text",
+			input: "  cache   invalidation ",
+			want:  Query{Text: "cache invalidation"},
+		},
+		{
+			name:  "field filters",
+			input: "postgres status:accepted tag:database TAG:storage Status:proposed",
+			want: Query{
+				Text:     "postgres",
+				Statuses: []string{"accepted", "proposed"},
+				Tags:     []string{"database", "storage"},
+			},
+		},
+		{
+			name:  "quoted filter value",
+			input: `status:"in progress" retries`,
+			want:  Query{Text: "retries", Statuses: []string{"in progress"}},
+		},
+		{
+			name:  "quoted phrase",
+			input: `"event sourcing" tag:"data model"`,
+			want:  Query{Text: "event sourcing", Tags: []string{"data model"}},
+		},
+		{
+			name:  "unterminated quote runs to the end",
+			input: `tag:"data model`,
+			want:  Query{Tags: []string{"data model"}},
+		},
+		{
+			name:  "date range",
+			input: "after:2025-01-01 before:2026-01-01 queue",
+			want:  Query{Text: "queue", After: date("2025-01-01"), Before: date("2026-01-01")},
+		},
+		{
+			name:  "filter without value is text",
+			input: `status: tag:""`,
+			want:  Query{Text: "status: tag:"},
+		},
+		{
+			name:  "unknown field is text",
+			input: "ADR-0007: https://example.com/adr",
+			want:  Query{Text: "ADR-0007: https://example.com/adr"},
+		},
+		{
+			name:    "malformed after date",
+			input:   "after:2025-13-01",
+			wantErr: true,
+		},
+		{
+			name:    "malformed before date",
+			input:   "before:yesterday",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseQuery(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseQuery(%q) = %+v, want an error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseQuery(%q): %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseQuery(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryMatches(t *testing.T) {
+	entry := &Entry{Status: "In Progress", Tags: []string{"Database", "storage"}, Date: date("2025-06-15")}
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", true},
+		{"status:in-progress", true},
+		{`status:"in progress"`, true},
+		{"status:in_progress status:accepted", true},
+		{"status:accepted", false},
+		{"tag:database tag:STORAGE", true},
+		{"tag:database tag:cache", false},
+		{"after:2025-06-15 before:2025-06-16", true},
+		{"after:2025-06-16", false},
+		{"before:2025-06-15", false},
+	}
+	for _, tt := range tests {
+		query, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Fatalf("ParseQuery(%q): %v", tt.query, err)
+		}
+		if got := query.Matches(entry); got != tt.want {
+			t.Errorf("%q matches = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
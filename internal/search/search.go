@@ -0,0 +1,182 @@
+package search
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/similarity"
+)
+
+const (
+	snippetRadius  = 70 // Characters of context on each side of the first match
+	snippetsPerADR = 2
+)
+
+// Span is a highlighted byte range of a text
+type Span struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Snippet is an excerpt of an ADR section around matching words
+type Snippet struct {
+	Section    string `json:"section"`
+	Text       string `json:"text"`
+	Highlights []Span `json:"highlights"`
+}
+
+// Result is an ADR matching a query
+type Result struct {
+	Entry           *Entry
+	Score           float64 // BM25 score normalized to [0, 1); 0 for filter-only queries
+	TitleHighlights []Span
+	Snippets        []Snippet
+}
+
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// Search returns the indexed ADRs passing the query's filters, ranked by how
+// well they match its free text. Without free text, ADRs are listed by ID.
+func (x *Index) Search(query Query, limit int) []Result {
+	entries := x.sortedEntries()
+	terms := similarity.QueryTerms(query.Text)
+
+	var results []Result
+	if len(terms) == 0 {
+		for _, entry := range entries {
+			if query.Matches(entry) {
+				results = append(results, Result{Entry: entry})
+			}
+		}
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].Entry.ID < results[j].Entry.ID
+		})
+	} else {
+		// Rank across all ADRs so term rarity does not depend on the filters
+		docs := make([]similarity.Document, len(entries))
+		stats := make([]similarity.TermStats, len(entries))
+		byPath := make(map[string]*Entry, len(entries))
+		for i, entry := range entries {
+			docs[i] = similarity.Document{ADR: &adr.ADR{ID: entry.ID, Title: entry.Title, FilePath: entry.Path}, Title: entry.Title}
+			stats[i] = entry.Terms
+			byPath[entry.Path] = entry
+		}
+
+		for _, match := range similarity.NewBM25IndexFromStats(docs, stats).Search(query.Text, 0) {
+			entry := byPath[match.ADR.FilePath]
+			if !query.Matches(entry) {
+				continue
+			}
+			results = append(results, Result{
+				Entry:           entry,
+				Score:           match.Score,
+				TitleHighlights: highlight(entry.Title, terms),
+				Snippets:        snippets(entry.Sections, terms),
+			})
+		}
+	}
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// sortedEntries returns the entries ordered by path, so ties rank stably
+func (x *Index) sortedEntries() []*Entry {
+	entries := make([]*Entry, 0, len(x.Entries))
+	for _, entry := range x.Entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+	return entries
+}
+
+// snippets returns excerpts of the sections with the most matching words
+func snippets(sections []adr.Section, terms []string) []Snippet {
+	type candidate struct {
+		section string
+		text    string
+		spans   []Span
+	}
+
+	var candidates []candidate
+	for _, section := range sections {
+		if section.Title == "" {
+			continue // Preamble, usually just the title heading
+		}
+		text := strings.Join(strings.Fields(section.Content), " ")
+		if spans := highlight(text, terms); len(spans) > 0 {
+			candidates = append(candidates, candidate{section: section.Title, text: text, spans: spans})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return len(candidates[i].spans) > len(candidates[j].spans)
+	})
+	if len(candidates) > snippetsPerADR {
+		candidates = candidates[:snippetsPerADR]
+	}
+
+	var result []Snippet
+	for _, c := range candidates {
+		result = append(result, excerpt(c.section, c.text, c.spans))
+	}
+	return result
+}
+
+// excerpt cuts a window of text around the first highlighted span
+func excerpt(section, text string, spans []Span) Snippet {
+	start := max(spans[0].Start-snippetRadius, 0)
+	end := min(spans[0].End+snippetRadius, len(text))
+
+	// Do not cut words or multi-byte characters in half
+	for start > 0 && (!utf8.RuneStart(text[start]) || text[start-1] != ' ') {
+		start++
+		if start >= spans[0].Start {
+			start = spans[0].Start
+			break
+		}
+	}
+	for end < len(text) && text[end] != ' ' {
+		end++
+	}
+
+	prefix, suffix := "", ""
+	if start > 0 {
+		prefix = "…"
+	}
+	if end < len(text) {
+		suffix = "…"
+	}
+
+	snippet := Snippet{Section: section, Text: prefix + text[start:end] + suffix}
+	for _, span := range spans {
+		if span.Start >= start && span.End <= end {
+			offset := len(prefix) - start
+			snippet.Highlights = append(snippet.Highlights, Span{Start: span.Start + offset, End: span.End + offset})
+		}
+	}
+	return snippet
+}
+
+// highlight returns the spans of the words in text that match a query term
+func highlight(text string, terms []string) []Span {
+	wanted := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		wanted[term] = true
+	}
+
+	var spans []Span
+	for _, loc := range wordPattern.FindAllStringIndex(text, -1) {
+		wordTerms := similarity.Tokenize(text[loc[0]:loc[1]])
+		if len(wordTerms) == 1 && wanted[wordTerms[0]] {
+			spans = append(spans, Span{Start: loc[0], End: loc[1]})
+		}
+	}
+	return spans
+}
@@ -0,0 +1,167 @@
+package signals
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/SilverFlin/DrDuck/internal/apidiff"
+	"github.com/SilverFlin/DrDuck/internal/deps"
+	"github.com/SilverFlin/DrDuck/internal/diff"
+	"github.com/SilverFlin/DrDuck/internal/prompts/templates"
+	"github.com/SilverFlin/DrDuck/internal/schema"
+)
+
+// Signals holds structured facts extracted from a diff. They are shown to the
+// AI provider next to the raw changes so it does not have to infer them.
+type Signals struct {
+	Dependencies []deps.Change
+	Schema       []schema.Migration
+	API          []apidiff.Change
+}
+
+// Collect extracts the structured signals the diff alone is enough for
+func Collect(files []*diff.File) *Signals {
+	return &Signals{
+		Schema: schema.Analyze(files),
+	}
+}
+
+// CollectFromDiff parses a raw unified diff and extracts its signals
+func CollectFromDiff(raw string) *Signals {
+	return Collect(diff.Parse(raw))
+}
+
+// CompareRefs fills in the dependency changes of the manifests and the
+// exported Go API changes of the packages touched by the diff, comparing whole
+// files between baseRef and HEAD. It needs git, so Collect does not do it.
+func (s *Signals) CompareRefs(baseRef string, files []*diff.File) error {
+	var errs []error
+	if dependencies, err := deps.Compare(baseRef, "HEAD", files); err != nil {
+		errs = append(errs, err)
+	} else {
+		s.Dependencies = dependencies
+	}
+	if changes, err := apidiff.Compare(baseRef, "HEAD", diff.Paths(files)); err != nil {
+		errs = append(errs, err)
+	} else {
+		s.API = changes
+	}
+	return errors.Join(errs...)
+}
+
+// Empty reports whether no signals were found
+func (s *Signals) Empty() bool {
+	return len(s.blocks()) == 0
+}
+
+// block is one titled group of signals
+type block struct {
+	title string
+	intro string
+	body  string
+}
+
+// blocks returns the non-empty signal groups in display order
+func (s *Signals) blocks() []block {
+	if s == nil {
+		return nil
+	}
+
+	var blocks []block
+	if significant := deps.Significant(s.Dependencies); len(significant) > 0 {
+		blocks = append(blocks, block{
+			title: "Dependency Changes",
+			intro: "Introducing, removing or majorly upgrading a library is usually an architectural decision.",
+			body:  deps.Summarize(significant),
+		})
+	}
+	if len(s.Schema) > 0 {
+		intro := "Summary of the database migrations in this change (their raw diff is omitted)."
+		if len(s.DestructiveMigrations()) > 0 {
+			intro += " Destructive operations can lose data or break running code and should be documented in an ADR."
+		}
+		blocks = append(blocks, block{
+			title: "Schema Changes",
+			intro: intro,
+			body:  schema.Summarize(s.Schema),
+		})
+	}
+	if len(s.API) > 0 {
+		intro := "Exported Go identifiers that changed between the base ref and HEAD."
+		if len(s.BreakingAPIChanges()) > 0 {
+			intro += " Breaking changes affect every importer and should be documented in an ADR."
+		}
+		blocks = append(blocks, block{
+			title: "Public API Changes",
+			intro: intro,
+			body:  apidiff.Summarize(s.API),
+		})
+	}
+	return blocks
+}
+
+// BreakingAPIChanges returns the exported API changes that can break importers
+func (s *Signals) BreakingAPIChanges() []apidiff.Change {
+	if s == nil {
+		return nil
+	}
+	return apidiff.BreakingChanges(s.API)
+}
+
+// DestructiveMigrations returns the migrations that drop, truncate, rename or retype data
+func (s *Signals) DestructiveMigrations() []schema.Migration {
+	if s == nil {
+		return nil
+	}
+	return schema.Destructive(s.Schema)
+}
+
+// Condense replaces the hunks of migrations that the schema summary already
+// describes with their file headers, so prompts carry the summary instead of raw SQL
+func (s *Signals) Condense(raw string) string {
+	summarized := make(map[string]bool)
+	if s != nil {
+		for _, migration := range s.Schema {
+			if len(migration.Operations) > 0 {
+				summarized[migration.Path] = true
+			}
+		}
+	}
+	if len(summarized) == 0 {
+		return raw
+	}
+
+	var parts []string
+	for _, file := range diff.Parse(raw) {
+		if summarized[file.Path()] {
+			parts = append(parts, file.Header())
+		} else {
+			parts = append(parts, file.Raw)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// Sections renders the signals as extra change analysis prompt sections
+func (s *Signals) Sections() []templates.AnalysisSection {
+	var sections []templates.AnalysisSection
+	for _, b := range s.blocks() {
+		sections = append(sections, templates.AnalysisSection{
+			Title:   b.title,
+			Content: b.intro + "\n" + b.body,
+		})
+	}
+	return sections
+}
+
+// Summary renders the signals as plain text for ADR generation prompts
+func (s *Signals) Summary() string {
+	var builder strings.Builder
+	for _, b := range s.blocks() {
+		builder.WriteString(b.title)
+		builder.WriteString(":\n")
+		builder.WriteString(b.body)
+		builder.WriteString("\n")
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
@@ -0,0 +1,174 @@
+package similarity
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// BM25 parameters: k1 limits how much repeated terms count, b how much long
+// documents are penalized
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// titleWeight is how many times title terms are counted, as titles name the decision
+const titleWeight = 3
+
+// stopWords are too common in ADRs to say anything about the decision
+var stopWords = map[string]bool{
+	"a": true, "about": true, "adr": true, "all": true, "an": true, "and": true, "are": true,
+	"as": true, "at": true, "be": true, "by": true, "can": true, "decision": true, "do": true,
+	"for": true, "from": true, "has": true, "have": true, "how": true, "in": true, "instead": true,
+	"into": true, "is": true, "it": true, "its": true, "new": true, "not": true, "of": true,
+	"on": true, "or": true, "our": true, "should": true, "so": true, "that": true, "the": true,
+	"their": true, "this": true, "to": true, "use": true, "used": true, "using": true,
+	"we": true, "when": true, "which": true, "will": true, "with": true,
+}
+
+// BM25Index ranks documents against free-text queries with the Okapi BM25 formula
+type BM25Index struct {
+	docs      []Document
+	stats     []TermStats
+	avgLength float64
+	docFreq   map[string]int
+}
+
+// TermStats are the weighted term counts of one document
+type TermStats struct {
+	Freqs  map[string]int `json:"freqs"`
+	Length int            `json:"length"`
+}
+
+// DocumentTerms counts the terms of a document, counting title terms more than body terms
+func DocumentTerms(title, body string) TermStats {
+	stats := TermStats{Freqs: make(map[string]int)}
+	for _, term := range Tokenize(title) {
+		stats.Freqs[term] += titleWeight
+		stats.Length += titleWeight
+	}
+	for _, term := range Tokenize(body) {
+		stats.Freqs[term]++
+		stats.Length++
+	}
+	return stats
+}
+
+// NewBM25Index indexes documents
+func NewBM25Index(docs []Document) *BM25Index {
+	stats := make([]TermStats, len(docs))
+	for i, doc := range docs {
+		stats[i] = DocumentTerms(doc.Title, doc.Body)
+	}
+	return NewBM25IndexFromStats(docs, stats)
+}
+
+// NewBM25IndexFromStats indexes documents whose terms were counted before,
+// e.g. by an index persisted on disk
+func NewBM25IndexFromStats(docs []Document, stats []TermStats) *BM25Index {
+	index := &BM25Index{
+		docs:    docs,
+		stats:   stats,
+		docFreq: make(map[string]int),
+	}
+
+	total := 0
+	for _, docStats := range stats {
+		for term := range docStats.Freqs {
+			index.docFreq[term]++
+		}
+		total += docStats.Length
+	}
+	if len(stats) > 0 {
+		index.avgLength = float64(total) / float64(len(stats))
+	}
+	return index
+}
+
+// Search returns the documents matching the query, best first. Scores are
+// normalized to [0, 1) by the best score any document could reach.
+func (x *BM25Index) Search(query string, limit int) []Match {
+	terms := QueryTerms(query)
+	if len(terms) == 0 || len(x.docs) == 0 {
+		return nil
+	}
+
+	// The BM25 term weight approaches idf*(k1+1) as term frequency grows
+	maxScore := 0.0
+	for _, term := range terms {
+		maxScore += x.idf(term) * (bm25K1 + 1)
+	}
+
+	var matches []Match
+	for i, doc := range x.docs {
+		score := 0.0
+		for _, term := range terms {
+			tf := float64(x.stats[i].Freqs[term])
+			if tf == 0 {
+				continue
+			}
+			norm := 1 - bm25B + bm25B*float64(x.stats[i].Length)/x.avgLength
+			score += x.idf(term) * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+		}
+		if score > 0 {
+			matches = append(matches, Match{Document: doc, Score: score / maxScore})
+		}
+	}
+	return topMatches(matches, limit)
+}
+
+// idf returns the inverse document frequency of a term, always positive
+func (x *BM25Index) idf(term string) float64 {
+	n := float64(x.docFreq[term])
+	return math.Log(1 + (float64(len(x.docs))-n+0.5)/(n+0.5))
+}
+
+// Tokenize splits text into lowercase, lightly stemmed terms without stop words.
+// Kebab-case and snake_case titles are split into words.
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var terms []string
+	for _, word := range words {
+		if len(word) < 2 || stopWords[word] {
+			continue
+		}
+		terms = append(terms, stem(word))
+	}
+	return terms
+}
+
+// stem strips common English suffixes so "caching", "cached" and "caches" match "cache"
+func stem(word string) string {
+	switch {
+	case len(word) > 5 && strings.HasSuffix(word, "ing"):
+		word = word[:len(word)-3]
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		word = word[:len(word)-3] + "y"
+	case len(word) > 4 && (strings.HasSuffix(word, "ed") || strings.HasSuffix(word, "es")):
+		word = word[:len(word)-2]
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		word = word[:len(word)-1]
+	}
+	if len(word) > 4 && strings.HasSuffix(word, "e") {
+		word = word[:len(word)-1]
+	}
+	return word
+}
+
+// QueryTerms returns the distinct terms of a query, in order
+func QueryTerms(query string) []string {
+	terms := Tokenize(query)
+	seen := make(map[string]bool, len(terms))
+	var unique []string
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			unique = append(unique, term)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,75 @@
+package similarity
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/config"
+)
+
+// Document is a searchable ADR
+type Document struct {
+	ADR   *adr.ADR
+	Title string
+	Body  string
+}
+
+// Match is a document similar to a query, with a score between 0 and 1
+type Match struct {
+	Document
+	Score float64
+}
+
+// LoadDocuments reads the title and body of each ADR, without front matter
+// and template placeholder comments
+func LoadDocuments(adrs []*adr.ADR) ([]Document, error) {
+	docs := make([]Document, 0, len(adrs))
+	for _, a := range adrs {
+		content, err := os.ReadFile(a.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ADR-%04d: %w", a.ID, err)
+		}
+
+		_, body := adr.SplitFrontMatter(string(content))
+		var text strings.Builder
+		for _, section := range adr.ParseSections(body) {
+			text.WriteString(section.Title + "\n" + section.Content + "\n\n")
+		}
+
+		docs = append(docs, Document{ADR: a, Title: a.Title, Body: text.String()})
+	}
+	return docs, nil
+}
+
+// topMatches sorts matches best first and keeps at most limit of them
+func topMatches(matches []Match, limit int) []Match {
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// FindSimilar returns the ADRs whose score against the query reaches the
+// configured threshold, best first, ranked by the local BM25 index
+func FindSimilar(adrs []*adr.ADR, query string, settings config.SimilarityConfig) ([]Match, error) {
+	docs, err := LoadDocuments(adrs)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := NewBM25Index(docs).Search(query, 0)
+
+	var similar []Match
+	for _, match := range matches {
+		if match.Score >= settings.Threshold {
+			similar = append(similar, match)
+		}
+	}
+	return topMatches(similar, settings.MaxResults), nil
+}
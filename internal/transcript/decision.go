@@ -0,0 +1,116 @@
+package transcript
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Limits for the heuristic extraction
+const (
+	maxProblemLength  = 600
+	maxSentenceLength = 300
+	maxSentences      = 4
+)
+
+var (
+	codeFencePattern   = regexp.MustCompile("(?s)```.*?(```|$)")
+	listMarkerPattern  = regexp.MustCompile(`^(?:[-*+>#]+|\d+[.)])\s+`)
+	chosenPattern      = regexp.MustCompile(`(?i)\b(let'?s (?:go with|use|stick with)|we(?:'ll| will| should) (?:go with|use|stick with)|i(?:'ll| will) (?:go with|use)|decided|decision is|going with|went with|chose|settled? on|opt(?:ed)? for)\b`)
+	alternativePattern = regexp.MustCompile(`(?i)\b(alternatives?|options?|instead of|rather than|versus|vs\.?|compared (?:to|with)|could also|another approach)\b`)
+	rationalePattern   = regexp.MustCompile(`(?i)\b(because|since|so that|due to|the reason|which means|this gives)\b`)
+	tradeOffPattern    = regexp.MustCompile(`(?i)\b(trade-?offs?|downsides?|drawbacks?|cons|risks?|at the expense of|the cost is|however)\b`)
+	futurePattern      = regexp.MustCompile(`(?i)\b(in the future|later on|eventually|revisit|migrat\w+|scal(?:e|ing|ability)|long[- ]term)\b`)
+)
+
+// Decision is the decision context a conversation contains, in the shape of
+// the ADR questionnaire
+type Decision struct {
+	Problem      string
+	Decision     string
+	Rationale    string
+	Alternatives string
+	TradeOffs    string
+	Future       string
+}
+
+// IsEmpty reports whether nothing was found
+func (d Decision) IsEmpty() bool {
+	return d == Decision{}
+}
+
+// Extract finds the decision context of a conversation with keyword
+// heuristics, for when no AI provider can summarize it. The problem is taken
+// from the developer's first message and the decision from the last sentence
+// that announces a choice.
+func Extract(t *Transcript) Decision {
+	var d Decision
+	var alternatives, rationale, tradeOffs, future []string
+	seen := map[string]bool{}
+	collect := func(list []string, sentence string) []string {
+		if seen[sentence] || len(list) >= maxSentences {
+			return list
+		}
+		seen[sentence] = true
+		return append(list, Truncate(sentence, maxSentenceLength))
+	}
+
+	var lastUserMessage string
+	for _, message := range t.Messages {
+		prose := strings.TrimSpace(codeFencePattern.ReplaceAllString(message.Text, ""))
+		if message.Role == RoleUser {
+			if d.Problem == "" {
+				d.Problem = Truncate(prose, maxProblemLength)
+			}
+			lastUserMessage = prose
+		}
+
+		for _, sentence := range Sentences(prose) {
+			switch {
+			case chosenPattern.MatchString(sentence):
+				d.Decision = Truncate(sentence, maxSentenceLength)
+			case alternativePattern.MatchString(sentence):
+				alternatives = collect(alternatives, sentence)
+			case tradeOffPattern.MatchString(sentence):
+				tradeOffs = collect(tradeOffs, sentence)
+			case rationalePattern.MatchString(sentence):
+				rationale = collect(rationale, sentence)
+			case futurePattern.MatchString(sentence):
+				future = collect(future, sentence)
+			}
+		}
+	}
+
+	// Without an explicit choice, the developer's last request is the best guess
+	if d.Decision == "" && lastUserMessage != d.Problem {
+		d.Decision = Truncate(lastUserMessage, maxSentenceLength)
+	}
+	d.Alternatives = strings.Join(alternatives, "\n")
+	d.Rationale = strings.Join(rationale, "\n")
+	d.TradeOffs = strings.Join(tradeOffs, "\n")
+	d.Future = strings.Join(future, "\n")
+	return d
+}
+
+// Sentences splits text at line breaks and at sentence ends followed by a
+// space, so that file names such as config.yml stay whole. List markers and
+// headings are dropped.
+func Sentences(text string) []string {
+	var result []string
+	add := func(sentence string) {
+		sentence = strings.TrimSpace(listMarkerPattern.ReplaceAllString(strings.TrimSpace(sentence), ""))
+		if len(sentence) > 3 {
+			result = append(result, sentence)
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		start := 0
+		for n := 0; n < len(line); n++ {
+			if strings.IndexByte(".!?", line[n]) >= 0 && (n+1 == len(line) || line[n+1] == ' ') {
+				add(line[start : n+1])
+				start = n + 1
+			}
+		}
+		add(line[start:])
+	}
+	return result
+}
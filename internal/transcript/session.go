@@ -0,0 +1,72 @@
+package transcript
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Limits that keep the context read from a coding assistant's session small
+// enough for a prompt
+const (
+	MaxIntentPrompts  = 5
+	MaxPromptLength   = 500
+	MaxDecisions      = 10
+	MaxDecisionLength = 300
+)
+
+// DecisionPattern matches sentences of a session that discuss a decision
+var DecisionPattern = regexp.MustCompile(`(?i)\b(decid\w*|decision|chose|choose|chosen|instead of|rather than|trade-?offs?|alternatives?|went with|go with|opt(?:ed)? for|prefer\w*)\b`)
+
+// Intent returns what the developer asked for in a session. The first prompt
+// usually states the goal, the latest ones where it ended up.
+func Intent(prompts []string) []string {
+	intent := prompts
+	if len(prompts) > MaxIntentPrompts {
+		intent = append([]string{prompts[0]}, prompts[len(prompts)-MaxIntentPrompts+1:]...)
+	}
+	for n, prompt := range intent {
+		intent[n] = Truncate(prompt, MaxPromptLength)
+	}
+	return intent
+}
+
+// RelativeTo returns path relative to dir, or false when it is outside dir
+func RelativeTo(dir, path string) (string, bool) {
+	if !filepath.IsAbs(path) {
+		return filepath.ToSlash(filepath.Clean(path)), true
+	}
+	relative, err := filepath.Rel(dir, path)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(relative), true
+}
+
+// FormatContext renders what a session was about as context for an ADR
+// prompt, under a heading that names the session
+func FormatContext(heading string, intent, files, decisions []string) string {
+	var b strings.Builder
+	b.WriteString(heading + "\n")
+
+	if len(intent) > 0 {
+		b.WriteString("\nWhat the developer asked for:\n")
+		for _, prompt := range intent {
+			fmt.Fprintf(&b, "- %s\n", strings.ReplaceAll(prompt, "\n", " "))
+		}
+	}
+	if len(files) > 0 {
+		b.WriteString("\nFiles edited in the session:\n")
+		for _, file := range files {
+			fmt.Fprintf(&b, "- %s\n", file)
+		}
+	}
+	if len(decisions) > 0 {
+		b.WriteString("\nDecision discussion:\n")
+		for _, decision := range decisions {
+			fmt.Fprintf(&b, "- %s\n", decision)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,478 @@
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Roles of the messages in a transcript
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Formats a transcript can be read from
+const (
+	FormatChatGPT  = "chatgpt"
+	FormatCopilot  = "copilot"
+	FormatMessages = "messages"
+	FormatJSONL    = "jsonl"
+	FormatAider    = "aider"
+	FormatMarkdown = "markdown"
+	FormatNotes    = "notes"
+)
+
+// roleLabels are the speaker names used in pasted and exported conversations
+const roleLabels = `user|you|human|me|assistant|ai|chatgpt|gpt-4o?|claude|copilot|github copilot|gemini|model|bot`
+
+var (
+	headingRolePattern = regexp.MustCompile(`(?i)^#{1,6}\s*(?:\*\*|__)?(` + roleLabels + `)(?:\s+said)?\s*:?\s*(?:\*\*|__)?\s*:?\s*$`)
+	inlineRolePattern  = regexp.MustCompile(`(?i)^(?:\*\*|__)?(` + roleLabels + `)(?:\s+said)?\s*(?::\s*(?:\*\*|__)?|(?:\*\*|__)\s*:)\s*(.*)$`)
+	aiderHeaderPattern = regexp.MustCompile(`(?m)^# aider chat started at`)
+)
+
+// Message is one turn of a conversation
+type Message struct {
+	Role string
+	Text string
+}
+
+// Transcript is a conversation between a developer and an AI assistant
+type Transcript struct {
+	Format   string
+	Title    string
+	Messages []Message
+}
+
+// Read reads a transcript. The name, when known, helps tell the format apart;
+// the content decides otherwise.
+func Read(r io.Reader, name string) (*Transcript, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	return Parse(data, name)
+}
+
+// Parse parses a transcript in one of the supported formats: a ChatGPT data
+// export, a Copilot Chat export, a JSON list of role/content messages, JSON
+// Lines of messages (including Claude Code transcripts), an Aider chat history,
+// or a Markdown or text conversation with speaker labels. Text without labels
+// is taken as the developer's notes.
+func Parse(data []byte, name string) (*Transcript, error) {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("transcript is empty")
+	}
+
+	var t *Transcript
+	var err error
+	switch {
+	case strings.EqualFold(filepath.Ext(name), ".jsonl"):
+		t, err = parseJSONL(trimmed)
+	case trimmed[0] == '{' || trimmed[0] == '[':
+		if json.Valid(trimmed) {
+			t, err = parseJSON(trimmed)
+		} else {
+			t, err = parseJSONL(trimmed)
+		}
+	default:
+		t = parseText(string(trimmed), name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	messages := t.Messages[:0]
+	for _, message := range t.Messages {
+		if message.Text = strings.TrimSpace(message.Text); message.Text != "" {
+			messages = append(messages, message)
+		}
+	}
+	t.Messages = messages
+	if len(t.Messages) == 0 {
+		return nil, fmt.Errorf("no messages found in %s transcript", t.Format)
+	}
+	return t, nil
+}
+
+// normalizeRole maps the role names of the various formats to RoleUser and
+// RoleAssistant; system and tool messages map to ""
+func normalizeRole(role string) string {
+	switch strings.ToLower(strings.TrimSpace(role)) {
+	case "user", "human", "you", "me":
+		return RoleUser
+	case "system", "tool", "function", "developer", "":
+		return ""
+	}
+	return RoleAssistant
+}
+
+// textContent returns the text of a message's content: a string, a list of
+// strings or a list of blocks with text
+func textContent(raw json.RawMessage) string {
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return text
+	}
+
+	var items []json.RawMessage
+	if json.Unmarshal(raw, &items) != nil {
+		return ""
+	}
+	var parts []string
+	for _, item := range items {
+		if json.Unmarshal(item, &text) == nil {
+			parts = append(parts, text)
+			continue
+		}
+		var block struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		}
+		// Tool calls and results are not part of the conversation
+		if json.Unmarshal(item, &block) == nil && block.Text != "" && (block.Type == "" || strings.Contains(block.Type, "text")) {
+			parts = append(parts, block.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// parseJSON parses the JSON export formats
+func parseJSON(data []byte) (*Transcript, error) {
+	var probe struct {
+		Mapping  json.RawMessage `json:"mapping"`
+		Requests json.RawMessage `json:"requests"`
+		Messages json.RawMessage `json:"messages"`
+	}
+
+	if data[0] == '[' {
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, fmt.Errorf("failed to parse transcript: %w", err)
+		}
+		if len(items) > 0 && json.Unmarshal(items[0], &probe) == nil && probe.Mapping != nil {
+			return parseChatGPTExport(items)
+		}
+		return parseMessages(data)
+	}
+
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse transcript: %w", err)
+	}
+	switch {
+	case probe.Mapping != nil:
+		return parseChatGPTExport([]json.RawMessage{data})
+	case probe.Requests != nil:
+		return parseCopilot(data)
+	case probe.Messages != nil:
+		return parseMessages(probe.Messages)
+	}
+	return nil, fmt.Errorf("unrecognized JSON transcript: expected a ChatGPT, Copilot Chat or messages export")
+}
+
+// chatGPTConversation is a conversation of a ChatGPT data export
+// (conversations.json). Messages form a tree; edits and regenerations branch it.
+type chatGPTConversation struct {
+	Title       string  `json:"title"`
+	UpdateTime  float64 `json:"update_time"`
+	CurrentNode string  `json:"current_node"`
+	Mapping     map[string]struct {
+		Parent  string `json:"parent"`
+		Message *struct {
+			Author struct {
+				Role string `json:"role"`
+			} `json:"author"`
+			CreateTime float64 `json:"create_time"`
+			Content    struct {
+				Parts json.RawMessage `json:"parts"`
+			} `json:"content"`
+		} `json:"message"`
+	} `json:"mapping"`
+}
+
+// parseChatGPTExport reads the most recently updated conversation of a ChatGPT
+// export, following the branch that was shown last
+func parseChatGPTExport(items []json.RawMessage) (*Transcript, error) {
+	var latest *chatGPTConversation
+	for _, item := range items {
+		var conversation chatGPTConversation
+		if err := json.Unmarshal(item, &conversation); err != nil {
+			return nil, fmt.Errorf("failed to parse ChatGPT conversation: %w", err)
+		}
+		if latest == nil || conversation.UpdateTime > latest.UpdateTime {
+			latest = &conversation
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("ChatGPT export has no conversations")
+	}
+
+	var path []string
+	if latest.CurrentNode != "" {
+		seen := map[string]bool{}
+		for id := latest.CurrentNode; id != "" && !seen[id]; id = latest.Mapping[id].Parent {
+			seen[id] = true
+			path = append([]string{id}, path...)
+		}
+	} else {
+		for id := range latest.Mapping {
+			path = append(path, id)
+		}
+		sort.Slice(path, func(a, b int) bool {
+			first, second := latest.Mapping[path[a]].Message, latest.Mapping[path[b]].Message
+			return first != nil && (second == nil || first.CreateTime < second.CreateTime)
+		})
+	}
+
+	t := &Transcript{Format: FormatChatGPT, Title: latest.Title}
+	for _, id := range path {
+		message := latest.Mapping[id].Message
+		if message == nil {
+			continue
+		}
+		if role := normalizeRole(message.Author.Role); role != "" {
+			t.Messages = append(t.Messages, Message{Role: role, Text: textContent(message.Content.Parts)})
+		}
+	}
+	return t, nil
+}
+
+// parseCopilot reads a Copilot Chat session exported from VS Code
+func parseCopilot(data []byte) (*Transcript, error) {
+	var session struct {
+		Requests []struct {
+			Message struct {
+				Text string `json:"text"`
+			} `json:"message"`
+			Response []struct {
+				Value   json.RawMessage `json:"value"`
+				Content *struct {
+					Value string `json:"value"`
+				} `json:"content"`
+			} `json:"response"`
+		} `json:"requests"`
+	}
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse Copilot Chat export: %w", err)
+	}
+
+	t := &Transcript{Format: FormatCopilot}
+	for _, request := range session.Requests {
+		t.Messages = append(t.Messages, Message{Role: RoleUser, Text: request.Message.Text})
+
+		var response strings.Builder
+		for _, part := range request.Response {
+			var value string
+			if json.Unmarshal(part.Value, &value) == nil {
+				response.WriteString(value)
+			} else if part.Content != nil {
+				response.WriteString(part.Content.Value)
+			}
+		}
+		t.Messages = append(t.Messages, Message{Role: RoleAssistant, Text: response.String()})
+	}
+	return t, nil
+}
+
+// jsonMessage is a message in the role/content shape most chat APIs use, or a
+// Claude Code transcript entry wrapping one
+type jsonMessage struct {
+	Role        string          `json:"role"`
+	Type        string          `json:"type"`
+	Content     json.RawMessage `json:"content"`
+	Text        string          `json:"text"`
+	IsSidechain bool            `json:"isSidechain"`
+	IsMeta      bool            `json:"isMeta"`
+	IsSummary   bool            `json:"isCompactSummary"`
+	Message     *struct {
+		Role    string          `json:"role"`
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// message converts the entry, returning false for entries that are not part
+// of the conversation
+func (m jsonMessage) message() (Message, bool) {
+	if m.IsSidechain || m.IsMeta || m.IsSummary {
+		return Message{}, false
+	}
+	role, content := m.Role, m.Content
+	if m.Message != nil {
+		role, content = m.Message.Role, m.Message.Content
+	}
+	if role == "" && (m.Type == "user" || m.Type == "assistant") {
+		role = m.Type
+	}
+	role = normalizeRole(role)
+	if role == "" {
+		return Message{}, false
+	}
+
+	text := textContent(content)
+	if text == "" {
+		text = m.Text
+	}
+	return Message{Role: role, Text: text}, true
+}
+
+// parseMessages reads a JSON list of role/content messages
+func parseMessages(data []byte) (*Transcript, error) {
+	var entries []jsonMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse transcript messages: %w", err)
+	}
+
+	t := &Transcript{Format: FormatMessages}
+	for _, entry := range entries {
+		if message, ok := entry.message(); ok {
+			t.Messages = append(t.Messages, message)
+		}
+	}
+	return t, nil
+}
+
+// parseJSONL reads one role/content message per line
+func parseJSONL(data []byte) (*Transcript, error) {
+	t := &Transcript{Format: FormatJSONL}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 32*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var entry jsonMessage
+		if err := json.Unmarshal(text, &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse transcript line %d: %w", line, err)
+		}
+		if message, ok := entry.message(); ok {
+			t.Messages = append(t.Messages, message)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	return t, nil
+}
+
+// parseText reads an Aider chat history, a conversation with speaker labels,
+// or plain notes
+func parseText(text string, name string) *Transcript {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if aiderHeaderPattern.MatchString(text) || strings.Contains(filepath.Base(name), ".aider.chat.history") {
+		return parseAider(text)
+	}
+
+	t := &Transcript{Format: FormatMarkdown}
+	var current *Message
+	var preamble []string
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if match := headingRolePattern.FindStringSubmatch(trimmed); match != nil {
+			t.Messages = append(t.Messages, Message{Role: normalizeRole(match[1])})
+			current = &t.Messages[len(t.Messages)-1]
+			continue
+		}
+		if match := inlineRolePattern.FindStringSubmatch(trimmed); match != nil {
+			t.Messages = append(t.Messages, Message{Role: normalizeRole(match[1]), Text: match[2]})
+			current = &t.Messages[len(t.Messages)-1]
+			continue
+		}
+		if current == nil {
+			preamble = append(preamble, line)
+			continue
+		}
+		current.Text += "\n" + line
+	}
+
+	if len(t.Messages) == 0 {
+		return &Transcript{Format: FormatNotes, Messages: []Message{{Role: RoleUser, Text: text}}}
+	}
+	// A heading before the first speaker is usually the conversation's title
+	for _, line := range preamble {
+		if strings.HasPrefix(line, "# ") {
+			t.Title = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+			break
+		}
+	}
+	return t
+}
+
+// parseAider reads an .aider.chat.history.md file: prompts are "#### " lines,
+// tool output is quoted with "> " and everything else is the assistant
+func parseAider(text string) *Transcript {
+	t := &Transcript{Format: FormatAider}
+	add := func(role, line string) {
+		if n := len(t.Messages); n > 0 && t.Messages[n-1].Role == role {
+			t.Messages[n-1].Text += "\n" + line
+			return
+		}
+		t.Messages = append(t.Messages, Message{Role: role, Text: line})
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case strings.HasPrefix(line, "# aider chat started at"):
+		case strings.HasPrefix(line, "#### "):
+			add(RoleUser, strings.TrimPrefix(line, "#### "))
+		case strings.HasPrefix(line, ">"):
+		case len(t.Messages) > 0:
+			add(RoleAssistant, line)
+		}
+	}
+	return t
+}
+
+// Render writes the conversation out as labelled turns, keeping it under limit
+// bytes by dropping messages from the middle: the first message usually states
+// the problem and the last ones the outcome
+func Render(t *Transcript, limit int) string {
+	turns := make([]string, len(t.Messages))
+	for n, message := range t.Messages {
+		label := "Developer"
+		if message.Role == RoleAssistant {
+			label = "Assistant"
+		}
+		turns[n] = fmt.Sprintf("%s: %s", label, message.Text)
+	}
+
+	rendered := strings.Join(turns, "\n\n")
+	if len(rendered) <= limit || len(turns) < 2 {
+		return Truncate(rendered, limit)
+	}
+
+	first := Truncate(turns[0], limit/4)
+	budget := limit - len(first)
+	var tail []string
+	for n := len(turns) - 1; n > 0; n-- {
+		if len(turns[n])+2 > budget {
+			break
+		}
+		budget -= len(turns[n]) + 2
+		tail = append([]string{turns[n]}, tail...)
+	}
+	omitted := len(turns) - 1 - len(tail)
+	return fmt.Sprintf("%s\n\n[... %d messages omitted ...]\n\n%s", first, omitted, strings.Join(tail, "\n\n"))
+}
+
+// Truncate shortens text to at most n bytes without splitting a character,
+// marking the cut with an ellipsis
+func Truncate(text string, n int) string {
+	if len(text) <= n {
+		return text
+	}
+	for n > 0 && !utf8.RuneStart(text[n]) {
+		n--
+	}
+	return strings.TrimRightFunc(text[:n], unicode.IsSpace) + "…"
+}
@@ -0,0 +1,130 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		file      string
+		stdin     bool // Read without a file name, as with --transcript -
+		wantTitle string
+		wantFmt   string
+		want      []Message
+	}{
+		{
+			file:      "conversations.json",
+			wantFmt:   FormatChatGPT,
+			wantTitle: "Queue for webhooks",
+			want: []Message{
+				{Role: RoleUser, Text: "Should webhooks go through a queue?"},
+				{Role: RoleAssistant, Text: "Yes, use SQS so retries survive restarts."},
+				{Role: RoleUser, Text: "What about Kafka?"},
+				{Role: RoleAssistant, Text: "Kafka is more to operate than we need."},
+			},
+		},
+		{
+			file:    "copilot-chat.json",
+			wantFmt: FormatCopilot,
+			want: []Message{
+				{Role: RoleUser, Text: "How should we store sessions?"},
+				{Role: RoleAssistant, Text: "Use Redis with a TTL per session."},
+				{Role: RoleUser, Text: "And if Redis is down?"},
+				{Role: RoleAssistant, Text: "Fall back to signed cookies."},
+			},
+		},
+		{
+			file:    "messages.json",
+			wantFmt: FormatMessages,
+			want: []Message{
+				{Role: RoleUser, Text: "Pick a date library for the API."},
+				{Role: RoleAssistant, Text: "Use the standard time package."},
+				{Role: RoleUser, Text: "Keep it dependency-free then."},
+			},
+		},
+		{
+			file:    "session.jsonl",
+			wantFmt: FormatJSONL,
+			want: []Message{
+				{Role: RoleUser, Text: "Add retries to the payment client."},
+				{Role: RoleAssistant, Text: "I chose exponential backoff over fixed delays."},
+				{Role: RoleUser, Text: "Cap it at five attempts."},
+			},
+		},
+		{
+			file:    "session.jsonl",
+			stdin:   true,
+			wantFmt: FormatJSONL,
+			want: []Message{
+				{Role: RoleUser, Text: "Add retries to the payment client."},
+				{Role: RoleAssistant, Text: "I chose exponential backoff over fixed delays."},
+				{Role: RoleUser, Text: "Cap it at five attempts."},
+			},
+		},
+		{
+			file:    ".aider.chat.history.md",
+			wantFmt: FormatAider,
+			want: []Message{
+				{Role: RoleUser, Text: "Switch the config format to TOML\nand keep YAML as a fallback."},
+				{Role: RoleAssistant, Text: "I'll switch to TOML because it has comments and typed values."},
+				{Role: RoleUser, Text: "Why not JSON?"},
+				{Role: RoleAssistant, Text: "JSON has no comments."},
+			},
+		},
+		{
+			file:      "chat.md",
+			wantFmt:   FormatMarkdown,
+			wantTitle: "Choosing a search index",
+			want: []Message{
+				{Role: RoleUser, Text: "Do we need Elasticsearch?"},
+				{Role: RoleAssistant, Text: "No. **BM25** over a local index is enough for a few hundred ADRs."},
+				{Role: RoleUser, Text: "Fine, go with the local index."},
+				{Role: RoleAssistant, Text: "Done."},
+			},
+		},
+		{
+			file:    "notes.txt",
+			wantFmt: FormatNotes,
+			want: []Message{
+				{Role: RoleUser, Text: "Talked with the team: we keep Postgres and add read replicas\ninstead of sharding, because the write load is low."},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		testName, path := tt.file, filepath.Join("testdata", tt.file)
+		name := path
+		if tt.stdin {
+			testName, name = tt.file+" from stdin", ""
+		}
+		t.Run(testName, func(t *testing.T) {
+			file, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			got, err := Read(file, name)
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if got.Format != tt.wantFmt || got.Title != tt.wantTitle {
+				t.Errorf("got format %q, title %q; want %q, %q", got.Format, got.Title, tt.wantFmt, tt.wantTitle)
+			}
+			if !reflect.DeepEqual(got.Messages, tt.want) {
+				t.Errorf("got messages %q\nwant %q", got.Messages, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRejectsEmptyTranscripts(t *testing.T) {
+	for _, input := range []string{"", " \n", `{"type":"summary","summary":"Nothing"}`} {
+		if _, err := Parse([]byte(input), ""); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", input)
+		}
+	}
+}
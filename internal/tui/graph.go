@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+)
+
+// graph holds which ADRs mention or link to which
+type graph struct {
+	references   map[int][]int // ADRs each ADR refers to
+	referencedBy map[int][]int // ADRs referring to each ADR
+}
+
+// buildGraph finds the references between ADRs in their file contents
+func buildGraph(adrs []*adr.ADR, contents map[int]string) graph {
+	g := graph{references: map[int][]int{}, referencedBy: map[int][]int{}}
+	for _, a := range adrs {
+		for _, id := range adr.ReferencedIDs(contents[a.ID], a.ID) {
+			g.references[a.ID] = append(g.references[a.ID], id)
+			g.referencedBy[id] = append(g.referencedBy[id], a.ID)
+		}
+	}
+	return g
+}
+
+// graphEntry is a line of the relationship view. Entries with an ID can be
+// selected to jump to that ADR.
+type graphEntry struct {
+	prefix string
+	id     int
+	label  string
+}
+
+// entries lays out an ADR's relationships as a tree: the ADRs it refers to,
+// the ADRs referring to it, and one more level of each
+func (g graph) entries(root *adr.ADR, byID map[int]*adr.ADR) []graphEntry {
+	describe := func(id int) string {
+		if a, ok := byID[id]; ok {
+			return fmt.Sprintf("%s ADR-%04d %s (%s)", statusIcon(a.Status), a.ID, a.Title, a.Status)
+		}
+		return fmt.Sprintf("❓ ADR-%04d (missing)", id)
+	}
+
+	entries := []graphEntry{{id: root.ID, label: describe(root.ID)}}
+	groups := []struct {
+		name  string
+		edges map[int][]int
+	}{
+		{"refers to", g.references},
+		{"referred to by", g.referencedBy},
+	}
+	for i, group := range groups {
+		branch, indent := "├─ ", "│  "
+		if i == len(groups)-1 {
+			branch, indent = "└─ ", "   "
+		}
+		ids := group.edges[root.ID]
+		if len(ids) == 0 {
+			entries = append(entries, graphEntry{prefix: branch, label: group.name + ": none"})
+			continue
+		}
+		entries = append(entries, graphEntry{prefix: branch, label: group.name})
+
+		for j, id := range ids {
+			childBranch, childIndent := indent+"├─ ", indent+"│  "
+			if j == len(ids)-1 {
+				childBranch, childIndent = indent+"└─ ", indent+"   "
+			}
+			entries = append(entries, graphEntry{prefix: childBranch, id: id, label: describe(id)})
+
+			// One more level in the same direction, without going back to the root
+			var next []int
+			for _, nextID := range group.edges[id] {
+				if nextID != root.ID {
+					next = append(next, nextID)
+				}
+			}
+			for k, nextID := range next {
+				leaf := childIndent + "├─ "
+				if k == len(next)-1 {
+					leaf = childIndent + "└─ "
+				}
+				entries = append(entries, graphEntry{prefix: leaf, id: nextID, label: describe(nextID)})
+			}
+		}
+	}
+	return entries
+}
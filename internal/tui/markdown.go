@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/markdown"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	boldPattern     = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	codeSpanPattern = regexp.MustCompile("`([^`]+)`")
+	linkPattern     = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+)
+
+// renderMarkdown renders an ADR file for the terminal, wrapped to width. The
+// front matter and template placeholder comments are left out.
+func renderMarkdown(source string, width int) string {
+	_, body := adr.SplitFrontMatter(source)
+	if width < 20 {
+		width = 20
+	}
+	return strings.Join(renderBlocks(markdown.Parse(body), width), "\n")
+}
+
+// renderBlocks renders blocks as terminal lines, separating them with blank lines
+func renderBlocks(blocks []markdown.Block, width int) []string {
+	wrap := lipgloss.NewStyle().Width(width)
+
+	var out []string
+	for i, block := range blocks {
+		switch block.Kind {
+		case markdown.Paragraph:
+			out = append(out, wrap.Render(renderInline(block.Text)))
+		case markdown.Heading:
+			switch block.Level {
+			case 1:
+				out = append(out, titleStyle.Render(wrap.Render(block.Text)))
+			case 2:
+				out = append(out, sectionStyle.Render(block.Text))
+			default:
+				out = append(out, subsectionStyle.Render(block.Text))
+			}
+		case markdown.Code:
+			for _, line := range block.Lines {
+				out = append(out, codeStyle.Render("  "+line))
+			}
+		case markdown.Rule:
+			out = append(out, dimStyle.Render(strings.Repeat("─", width)))
+		case markdown.Quote:
+			quote := lipgloss.NewStyle().Italic(true).Render(strings.Join(renderBlocks(block.Blocks, width-2), "\n"))
+			for _, quoted := range strings.Split(quote, "\n") {
+				out = append(out, dimStyle.Render("│ ")+quoted)
+			}
+		case markdown.List:
+			for _, listItem := range block.Items {
+				indent := strings.Repeat(" ", listItem.Indent/2*2)
+				item := lipgloss.NewStyle().Width(width - len(indent) - 2).Render(renderInline(listItem.Text))
+				for j, itemLine := range strings.Split(item, "\n") {
+					bullet := "  "
+					if j == 0 {
+						bullet = accentStyle.Render("• ")
+					}
+					out = append(out, indent+bullet+itemLine)
+				}
+			}
+		}
+		if i < len(blocks)-1 {
+			out = append(out, "")
+		}
+	}
+	return out
+}
+
+// renderInline styles bold text, code spans and links
+func renderInline(text string) string {
+	text = codeSpanPattern.ReplaceAllStringFunc(text, func(span string) string {
+		return codeStyle.Render(codeSpanPattern.FindStringSubmatch(span)[1])
+	})
+	text = boldPattern.ReplaceAllStringFunc(text, func(span string) string {
+		return lipgloss.NewStyle().Bold(true).Render(boldPattern.FindStringSubmatch(span)[1])
+	})
+	return linkPattern.ReplaceAllStringFunc(text, func(span string) string {
+		return lipgloss.NewStyle().Underline(true).Render(linkPattern.FindStringSubmatch(span)[1])
+	})
+}
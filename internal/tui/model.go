@@ -0,0 +1,723 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/config"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Options configures the browser. The suggest command is passed in so that the
+// TUI behaves exactly like the CLI command.
+type Options struct {
+	Config         *config.Config
+	Editor         string
+	SuggestCommand func(target *adr.ADR) *exec.Cmd
+}
+
+// Statuses in the order they are filtered and offered
+var allStatuses = []adr.Status{
+	adr.StatusDraft,
+	adr.StatusInProgress,
+	adr.StatusAccepted,
+	adr.StatusRejected,
+	adr.StatusSuperseded,
+}
+
+var (
+	accentColor = lipgloss.AdaptiveColor{Light: "#B8860B", Dark: "#FFD75F"}
+	dimColor    = lipgloss.AdaptiveColor{Light: "#808080", Dark: "#6C6C6C"}
+
+	titleStyle      = lipgloss.NewStyle().Bold(true).Foreground(accentColor)
+	sectionStyle    = lipgloss.NewStyle().Bold(true).Underline(true)
+	subsectionStyle = lipgloss.NewStyle().Bold(true)
+	codeStyle       = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#005F87", Dark: "#87D7FF"})
+	dimStyle        = lipgloss.NewStyle().Foreground(dimColor)
+	accentStyle     = lipgloss.NewStyle().Foreground(accentColor)
+	selectedStyle   = lipgloss.NewStyle().Bold(true).Foreground(accentColor)
+	errorStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	paneStyle       = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(dimColor)
+)
+
+// mode is what the keyboard currently controls
+type mode int
+
+const (
+	modeBrowse mode = iota
+	modeFilter
+	modeStatus
+	modeConfirmAccept
+	modeSuggest
+	modeGraph
+)
+
+// editorClosedMsg is sent when the editor started with 'e' exits
+type editorClosedMsg struct{ err error }
+
+// suggestDoneMsg carries the output of 'drduck suggest'
+type suggestDoneMsg struct {
+	id     int
+	output string
+	err    error
+}
+
+// model is the state of the browser
+type model struct {
+	opts Options
+	adrs *adr.Manager
+
+	all      []*adr.ADR
+	byID     map[int]*adr.ADR
+	contents map[int]string
+	tags     []string
+	visible  []*adr.ADR
+	cursor   int
+
+	statusFilter int    // Index into allStatuses plus one, 0 for all
+	tagFilter    string // Empty for all
+	filter       textinput.Model
+
+	mode    mode
+	preview viewport.Model
+	width   int
+	height  int
+	message string
+	isError bool
+
+	statusChoices []adr.Status
+	statusCursor  int
+	acceptIssues  []string
+
+	suggestFor int
+	suggestion viewport.Model
+
+	graph       []graphEntry
+	graphCursor int
+}
+
+// Run starts the full-screen browser and returns when the user quits
+func Run(opts Options) error {
+	m, err := newModel(opts)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tea.NewProgram(m, tea.WithAltScreen()).Run(); err != nil {
+		return fmt.Errorf("failed to run TUI: %w", err)
+	}
+	return nil
+}
+
+// newModel creates the browser with the project's ADRs loaded
+func newModel(opts Options) (*model, error) {
+	filter := textinput.New()
+	filter.Prompt = "/ "
+	filter.Placeholder = "filter titles"
+
+	m := &model{
+		opts:       opts,
+		adrs:       adr.NewManager(opts.Config),
+		filter:     filter,
+		preview:    viewport.New(0, 0),
+		suggestion: viewport.New(0, 0),
+	}
+	if err := m.reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// reload reads the ADRs from disk, keeping the selection when it still exists
+func (m *model) reload() error {
+	selected := -1
+	if current := m.selected(); current != nil {
+		selected = current.ID
+	}
+
+	adrs, err := m.adrs.List()
+	if err != nil {
+		return fmt.Errorf("failed to list ADRs: %w", err)
+	}
+
+	m.all = adrs
+	m.byID = make(map[int]*adr.ADR, len(adrs))
+	m.contents = make(map[int]string, len(adrs))
+	tagSet := map[string]bool{}
+	for _, a := range adrs {
+		m.byID[a.ID] = a
+		if content, err := os.ReadFile(a.FilePath); err == nil {
+			m.contents[a.ID] = string(content)
+		}
+		for _, tag := range a.Tags {
+			tagSet[tag] = true
+		}
+	}
+	m.tags = m.tags[:0]
+	for tag := range tagSet {
+		m.tags = append(m.tags, tag)
+	}
+	sort.Strings(m.tags)
+	if m.tagFilter != "" && !tagSet[m.tagFilter] {
+		m.tagFilter = ""
+	}
+
+	m.applyFilters()
+	m.selectID(selected)
+	return nil
+}
+
+// applyFilters recomputes the visible ADRs from the status, tag and text filters
+func (m *model) applyFilters() {
+	text := strings.ToLower(strings.TrimSpace(m.filter.Value()))
+	m.visible = m.visible[:0]
+	for _, a := range m.all {
+		if m.statusFilter > 0 && a.Status != allStatuses[m.statusFilter-1] {
+			continue
+		}
+		if m.tagFilter != "" && !hasTag(a, m.tagFilter) {
+			continue
+		}
+		if text != "" && !strings.Contains(strings.ToLower(fmt.Sprintf("%04d %s", a.ID, a.Title)), text) {
+			continue
+		}
+		m.visible = append(m.visible, a)
+	}
+	if m.cursor >= len(m.visible) {
+		m.cursor = len(m.visible) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	m.refreshPreview()
+}
+
+// selectID moves the cursor to an ADR if it is visible
+func (m *model) selectID(id int) bool {
+	for i, a := range m.visible {
+		if a.ID == id {
+			m.cursor = i
+			m.refreshPreview()
+			return true
+		}
+	}
+	return false
+}
+
+// selected returns the ADR under the cursor
+func (m *model) selected() *adr.ADR {
+	if m.cursor < 0 || m.cursor >= len(m.visible) {
+		return nil
+	}
+	return m.visible[m.cursor]
+}
+
+// refreshPreview renders the selected ADR into the preview pane
+func (m *model) refreshPreview() {
+	current := m.selected()
+	if current == nil {
+		m.preview.SetContent(dimStyle.Render("No ADRs match the filters"))
+		return
+	}
+	m.preview.SetContent(renderMarkdown(m.contents[current.ID], m.preview.Width))
+	m.preview.GotoTop()
+}
+
+// setMessage shows a line in the footer until the next key press
+func (m *model) setMessage(message string, isError bool) {
+	m.message = message
+	m.isError = isError
+}
+
+// Init implements tea.Model
+func (m *model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.resize()
+		return m, nil
+
+	case editorClosedMsg:
+		if msg.err != nil {
+			m.setMessage(fmt.Sprintf("Editor failed: %v", msg.err), true)
+		} else {
+			m.setMessage("Editor closed", false)
+		}
+		if err := m.reload(); err != nil {
+			m.setMessage(err.Error(), true)
+		}
+		return m, nil
+
+	case suggestDoneMsg:
+		if m.mode != modeSuggest || msg.id != m.suggestFor {
+			return m, nil
+		}
+		output := strings.TrimSpace(msg.output)
+		if msg.err != nil {
+			output += "\n\n" + errorStyle.Render(fmt.Sprintf("drduck suggest failed: %v", msg.err))
+		}
+		m.suggestion.SetContent(lipgloss.NewStyle().Width(m.suggestion.Width).Render(output))
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		m.message = ""
+		switch m.mode {
+		case modeFilter:
+			return m.updateFilter(msg)
+		case modeStatus:
+			return m.updateStatus(msg)
+		case modeConfirmAccept:
+			return m.updateConfirmAccept(msg)
+		case modeSuggest:
+			return m.updateSuggest(msg)
+		case modeGraph:
+			return m.updateGraph(msg)
+		default:
+			return m.updateBrowse(msg)
+		}
+	}
+	return m, nil
+}
+
+// updateBrowse handles keys in the list
+func (m *model) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc":
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+			m.refreshPreview()
+		}
+	case "down", "j":
+		if m.cursor < len(m.visible)-1 {
+			m.cursor++
+			m.refreshPreview()
+		}
+	case "home":
+		m.cursor = 0
+		m.refreshPreview()
+	case "end":
+		m.cursor = max(len(m.visible)-1, 0)
+		m.refreshPreview()
+	case "pgup", "pgdown", "ctrl+u", "ctrl+d":
+		var cmd tea.Cmd
+		m.preview, cmd = m.preview.Update(msg)
+		return m, cmd
+	case "/":
+		m.mode = modeFilter
+		return m, m.filter.Focus()
+	case "tab":
+		m.statusFilter = (m.statusFilter + 1) % (len(allStatuses) + 1)
+		m.applyFilters()
+	case "shift+tab":
+		m.statusFilter = (m.statusFilter + len(allStatuses)) % (len(allStatuses) + 1)
+		m.applyFilters()
+	case "t":
+		m.tagFilter = nextTag(m.tags, m.tagFilter)
+		m.applyFilters()
+	case "r":
+		if err := m.reload(); err != nil {
+			m.setMessage(err.Error(), true)
+		} else {
+			m.setMessage("Reloaded", false)
+		}
+	case "e", "enter":
+		if current := m.selected(); current != nil {
+			return m, m.edit(current)
+		}
+	case "s":
+		m.openStatusPicker()
+	case "a":
+		if current := m.selected(); current != nil {
+			return m, m.suggest(current)
+		}
+	case "g":
+		m.openGraph()
+	}
+	return m, nil
+}
+
+// updateFilter handles keys while typing the text filter
+func (m *model) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter", "esc":
+		if msg.String() == "esc" {
+			m.filter.SetValue("")
+			m.applyFilters()
+		}
+		m.filter.Blur()
+		m.mode = modeBrowse
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.filter, cmd = m.filter.Update(msg)
+	m.applyFilters()
+	return m, cmd
+}
+
+// edit opens the ADR in the editor, suspending the TUI until it exits
+func (m *model) edit(target *adr.ADR) tea.Cmd {
+	return tea.ExecProcess(exec.Command(m.opts.Editor, target.FilePath), func(err error) tea.Msg {
+		return editorClosedMsg{err: err}
+	})
+}
+
+// openStatusPicker offers the statuses the selected ADR can move to
+func (m *model) openStatusPicker() {
+	current := m.selected()
+	if current == nil {
+		return
+	}
+	m.statusChoices = nil
+	for _, status := range allStatuses {
+		if status != current.Status && adr.ValidateTransition(current.Status, status) == nil {
+			m.statusChoices = append(m.statusChoices, status)
+		}
+	}
+	if len(m.statusChoices) == 0 {
+		m.setMessage(fmt.Sprintf("ADR-%04d cannot change status from %s", current.ID, current.Status), true)
+		return
+	}
+	m.statusCursor = 0
+	m.mode = modeStatus
+}
+
+// updateStatus handles keys in the status picker
+func (m *model) updateStatus(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.mode = modeBrowse
+	case "up", "k":
+		if m.statusCursor > 0 {
+			m.statusCursor--
+		}
+	case "down", "j":
+		if m.statusCursor < len(m.statusChoices)-1 {
+			m.statusCursor++
+		}
+	case "enter":
+		m.mode = modeBrowse
+		m.changeStatus(m.statusChoices[m.statusCursor], false)
+	}
+	return m, nil
+}
+
+// updateConfirmAccept handles keys when accepting an ADR that failed the
+// content checks
+func (m *model) updateConfirmAccept(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "F":
+		m.mode = modeBrowse
+		m.changeStatus(adr.StatusAccepted, true)
+	case "e":
+		m.mode = modeBrowse
+		if current := m.selected(); current != nil {
+			return m, m.edit(current)
+		}
+	case "esc", "q", "n":
+		m.mode = modeBrowse
+	}
+	return m, nil
+}
+
+// changeStatus updates the selected ADR's status following the same rules as
+// 'drduck set-status' and 'drduck accept'
+func (m *model) changeStatus(newStatus adr.Status, force bool) {
+	current := m.selected()
+	if current == nil {
+		return
+	}
+
+	previousStatus := current.Status
+	issues, err := m.adrs.ChangeStatus(current, newStatus, force)
+	if err != nil {
+		m.setMessage(err.Error(), true)
+		return
+	}
+	if len(issues) > 0 {
+		m.acceptIssues = issues
+		m.mode = modeConfirmAccept
+		return
+	}
+	m.setMessage(fmt.Sprintf("ADR-%04d status updated from %s to %s", current.ID, previousStatus, newStatus), false)
+	if err := m.reload(); err != nil {
+		m.setMessage(err.Error(), true)
+	}
+}
+
+// suggest runs 'drduck suggest' for an ADR in the background
+func (m *model) suggest(target *adr.ADR) tea.Cmd {
+	m.mode = modeSuggest
+	m.suggestFor = target.ID
+	m.suggestion.SetContent(dimStyle.Render(fmt.Sprintf("🤖 Getting suggestions for ADR-%04d...", target.ID)))
+	m.suggestion.GotoTop()
+
+	command := m.opts.SuggestCommand(target)
+	return func() tea.Msg {
+		output, err := command.CombinedOutput()
+		return suggestDoneMsg{id: target.ID, output: string(output), err: err}
+	}
+}
+
+// updateSuggest handles keys while suggestions are shown
+func (m *model) updateSuggest(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.mode = modeBrowse
+		return m, nil
+	case "e":
+		m.mode = modeBrowse
+		if target, ok := m.byID[m.suggestFor]; ok {
+			return m, m.edit(target)
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.suggestion, cmd = m.suggestion.Update(msg)
+	return m, cmd
+}
+
+// openGraph shows the selected ADR's relationships
+func (m *model) openGraph() {
+	current := m.selected()
+	if current == nil {
+		return
+	}
+	m.graph = buildGraph(m.all, m.contents).entries(current, m.byID)
+	m.graphCursor = 0
+	m.mode = modeGraph
+}
+
+// updateGraph handles keys in the relationship view
+func (m *model) updateGraph(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "g":
+		m.mode = modeBrowse
+	case "up", "k":
+		for i := m.graphCursor - 1; i >= 0; i-- {
+			if m.graph[i].id != 0 {
+				m.graphCursor = i
+				break
+			}
+		}
+	case "down", "j":
+		for i := m.graphCursor + 1; i < len(m.graph); i++ {
+			if m.graph[i].id != 0 {
+				m.graphCursor = i
+				break
+			}
+		}
+	case "enter":
+		id := m.graph[m.graphCursor].id
+		if _, ok := m.byID[id]; !ok {
+			m.setMessage(fmt.Sprintf("ADR-%04d does not exist", id), true)
+			return m, nil
+		}
+		// Clear the filters that would hide the target
+		if !m.selectID(id) {
+			m.statusFilter, m.tagFilter = 0, ""
+			m.filter.SetValue("")
+			m.applyFilters()
+			m.selectID(id)
+		}
+		m.openGraph()
+	}
+	return m, nil
+}
+
+// resize lays out the panes for the terminal size
+func (m *model) resize() {
+	listWidth := m.listWidth()
+	bodyHeight := m.bodyHeight()
+
+	// Panes have a one-cell border on each side
+	m.preview.Width = max(m.width-listWidth-2, 10)
+	m.preview.Height = max(bodyHeight-2, 1)
+	m.suggestion.Width = max(m.width-2, 10)
+	m.suggestion.Height = max(bodyHeight-2, 1)
+	m.refreshPreview()
+}
+
+// listWidth returns the width of the list pane, borders included
+func (m *model) listWidth() int {
+	return min(max(m.width*2/5, 30), 60)
+}
+
+// bodyHeight returns the height available between the header and footer
+func (m *model) bodyHeight() int {
+	return max(m.height-2, 3)
+}
+
+// View implements tea.Model
+func (m *model) View() string {
+	if m.width == 0 {
+		return ""
+	}
+
+	var body string
+	switch m.mode {
+	case modeSuggest:
+		body = paneStyle.Width(m.width - 2).Height(m.bodyHeight() - 2).Render(m.suggestion.View())
+	case modeGraph:
+		body = paneStyle.Width(m.width - 2).Height(m.bodyHeight() - 2).Render(m.graphView())
+	default:
+		list := paneStyle.Width(m.listWidth() - 2).Height(m.bodyHeight() - 2).Render(m.listView())
+		preview := paneStyle.Width(m.width - m.listWidth() - 2).Height(m.bodyHeight() - 2).Render(m.preview.View())
+		body = lipgloss.JoinHorizontal(lipgloss.Top, list, preview)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, m.headerView(), body, m.footerView())
+}
+
+// headerView shows the active filters
+func (m *model) headerView() string {
+	status := "all"
+	if m.statusFilter > 0 {
+		status = string(allStatuses[m.statusFilter-1])
+	}
+	tag := "all"
+	if m.tagFilter != "" {
+		tag = m.tagFilter
+	}
+	header := fmt.Sprintf("🦆 DrDuck  %s %s  %s %s  %d/%d ADRs",
+		dimStyle.Render("status:"), status, dimStyle.Render("tag:"), tag, len(m.visible), len(m.all))
+	if m.mode == modeFilter || m.filter.Value() != "" {
+		header += "  " + m.filter.View()
+	}
+	return lipgloss.NewStyle().MaxWidth(m.width).Render(header)
+}
+
+// listView renders the ADR list, scrolled to keep the cursor visible
+func (m *model) listView() string {
+	height := m.bodyHeight() - 2
+	width := m.listWidth() - 4
+	if len(m.visible) == 0 {
+		return dimStyle.Render("No ADRs")
+	}
+
+	start := 0
+	if m.cursor >= height {
+		start = m.cursor - height + 1
+	}
+	var lines []string
+	for i := start; i < len(m.visible) && i < start+height; i++ {
+		a := m.visible[i]
+		line := fmt.Sprintf("%s %04d %s", statusIcon(a.Status), a.ID, a.Title)
+		if i == m.cursor {
+			line = selectedStyle.Render("▸ " + line)
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, lipgloss.NewStyle().MaxWidth(width).Render(line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// graphView renders the relationship tree
+func (m *model) graphView() string {
+	var lines []string
+	for i, entry := range m.graph {
+		label := entry.label
+		if i == m.graphCursor {
+			label = selectedStyle.Render(label)
+		}
+		lines = append(lines, dimStyle.Render(entry.prefix)+label)
+	}
+	lines = append(lines, "", dimStyle.Render("References are ADR-NNNN mentions and links to ADR files."))
+	return strings.Join(lines, "\n")
+}
+
+// footerView shows the keys of the current mode, or the last message
+func (m *model) footerView() string {
+	var footer string
+	switch {
+	case m.mode == modeStatus:
+		var choices []string
+		for i, status := range m.statusChoices {
+			choice := fmt.Sprintf("%s %s", statusIcon(status), status)
+			if i == m.statusCursor {
+				choice = selectedStyle.Render("[" + choice + "]")
+			}
+			choices = append(choices, choice)
+		}
+		footer = "Set status: " + strings.Join(choices, "  ") + dimStyle.Render("  ↑/↓ choose • enter confirm • esc cancel")
+	case m.mode == modeConfirmAccept:
+		footer = dimStyle.Render("F accept anyway • e edit • esc cancel  ") +
+			errorStyle.Render("Not ready for acceptance: "+strings.Join(m.acceptIssues, "; "))
+	case m.message != "":
+		if m.isError {
+			footer = errorStyle.Render(m.message)
+		} else {
+			footer = accentStyle.Render(m.message)
+		}
+	case m.mode == modeFilter:
+		footer = dimStyle.Render("type to filter • enter keep • esc clear")
+	case m.mode == modeSuggest:
+		footer = dimStyle.Render("↑/↓ scroll • e edit • esc back")
+	case m.mode == modeGraph:
+		footer = dimStyle.Render("↑/↓ move • enter open • esc back")
+	default:
+		footer = dimStyle.Render("↑/↓ move • / filter • tab status • t tag • e edit • s status • a suggest • g graph • r reload • q quit")
+	}
+	return lipgloss.NewStyle().MaxWidth(m.width).Render(footer)
+}
+
+// nextTag cycles through the tag filter values, starting and ending with all
+func nextTag(tags []string, current string) string {
+	if current == "" {
+		if len(tags) == 0 {
+			return ""
+		}
+		return tags[0]
+	}
+	for i, tag := range tags {
+		if tag == current && i+1 < len(tags) {
+			return tags[i+1]
+		}
+	}
+	return ""
+}
+
+// hasTag reports whether an ADR has a tag, compared case-insensitively
+func hasTag(a *adr.ADR, tag string) bool {
+	for _, t := range a.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// statusIcon returns the icon shown for a status
+func statusIcon(status adr.Status) string {
+	switch status {
+	case adr.StatusDraft:
+		return "📝"
+	case adr.StatusInProgress:
+		return "⚡"
+	case adr.StatusAccepted:
+		return "✅"
+	case adr.StatusSuperseded:
+		return "⏭️"
+	case adr.StatusRejected:
+		return "❌"
+	default:
+		return "❓"
+	}
+}
@@ -0,0 +1,224 @@
+package tui
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newTestModel creates a browser over three ADRs:
+//
+//	1 Use Redis for caching           Draft        storage
+//	2 Adopt gRPC between services     Accepted     api
+//	3 Use PostgreSQL for persistence  In Progress  storage
+func newTestModel(t *testing.T) *model {
+	t.Helper()
+
+	t.Chdir(t.TempDir())
+	cfg := config.DefaultConfig()
+	manager := adr.NewManager(cfg)
+
+	create := func(title, tag string, status adr.Status) {
+		created, err := manager.Create(title)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := os.ReadFile(created.FilePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tagged := strings.Replace(string(content), "\nstatus:", "\ntags: ["+tag+"]\nstatus:", 1)
+		if err := os.WriteFile(created.FilePath, []byte(tagged), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if status != adr.StatusDraft {
+			if _, err := manager.ChangeStatus(created, status, true); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	create("Use Redis for caching", "storage", adr.StatusDraft)
+	create("Adopt gRPC between services", "api", adr.StatusAccepted)
+	create("Use PostgreSQL for persistence", "storage", adr.StatusInProgress)
+
+	m, err := newModel(Options{Config: cfg})
+	if err != nil {
+		t.Fatalf("newModel: %v", err)
+	}
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	return m
+}
+
+// press sends key presses to the model; names other than special keys are typed
+func press(m *model, keys ...string) {
+	special := map[string]tea.KeyType{
+		"up":        tea.KeyUp,
+		"down":      tea.KeyDown,
+		"home":      tea.KeyHome,
+		"end":       tea.KeyEnd,
+		"enter":     tea.KeyEnter,
+		"esc":       tea.KeyEsc,
+		"tab":       tea.KeyTab,
+		"shift+tab": tea.KeyShiftTab,
+	}
+	for _, key := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		if keyType, ok := special[key]; ok {
+			msg = tea.KeyMsg{Type: keyType}
+		}
+		m.Update(msg)
+	}
+}
+
+// visibleIDs returns the IDs of the listed ADRs
+func visibleIDs(m *model) []int {
+	var ids []int
+	for _, a := range m.visible {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+// storedStatus reads an ADR's status from disk
+func storedStatus(t *testing.T, m *model, id int) adr.Status {
+	t.Helper()
+
+	stored, err := m.adrs.GetADRByID(id)
+	if err != nil {
+		t.Fatalf("GetADRByID: %v", err)
+	}
+	return stored.Status
+}
+
+func TestFilters(t *testing.T) {
+	m := newTestModel(t)
+
+	tests := []struct {
+		name string
+		keys []string
+		want []int
+	}{
+		{"all", nil, []int{1, 2, 3}},
+		{"status draft", []string{"tab"}, []int{1}},
+		{"status in progress", []string{"tab"}, []int{3}},
+		{"status back to draft", []string{"shift+tab"}, []int{1}},
+		{"status cycles to all", []string{"tab", "tab", "tab", "tab", "tab"}, []int{1, 2, 3}},
+		{"tag api", []string{"t"}, []int{2}},
+		{"tag storage", []string{"t"}, []int{1, 3}},
+		{"tag and status", []string{"tab"}, []int{1}},
+		{"tag cycles to all", []string{"shift+tab", "t"}, []int{1, 2, 3}},
+		{"text filter", []string{"/", "p", "o", "s", "t"}, []int{3}},
+		{"text filter by ID", []string{"esc", "/", "0", "0", "0", "2"}, []int{2}},
+		{"text filter kept", []string{"enter", "down"}, []int{2}},
+		{"text filter cleared", []string{"/", "esc"}, []int{1, 2, 3}},
+		{"no match", []string{"/", "k", "a", "f", "k", "a", "enter"}, nil},
+	}
+	for _, tt := range tests {
+		press(m, tt.keys...)
+		if got := visibleIDs(m); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: visible = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if m.mode != modeBrowse || m.selected() != nil {
+		t.Errorf("mode %d, selected %v, want browsing an empty list", m.mode, m.selected())
+	}
+	if view := m.View(); !strings.Contains(view, "0/3 ADRs") || !strings.Contains(view, "No ADRs") {
+		t.Errorf("view does not show the empty result:\n%s", view)
+	}
+}
+
+func TestNavigation(t *testing.T) {
+	m := newTestModel(t)
+
+	tests := []struct {
+		keys []string
+		want int
+	}{
+		{nil, 1},
+		{[]string{"down"}, 2},
+		{[]string{"j"}, 3},
+		{[]string{"down"}, 3}, // Stops at the last ADR
+		{[]string{"k"}, 2},
+		{[]string{"home"}, 1},
+		{[]string{"up"}, 1},
+		{[]string{"end"}, 3},
+	}
+	for _, tt := range tests {
+		press(m, tt.keys...)
+		if got := m.selected(); got == nil || got.ID != tt.want {
+			t.Fatalf("after %v selected %v, want ADR %d", tt.keys, got, tt.want)
+		}
+	}
+
+	// The preview follows the selection
+	if !strings.Contains(m.preview.View(), "PostgreSQL") {
+		t.Errorf("preview does not show the selected ADR:\n%s", m.preview.View())
+	}
+
+	// A filter that shortens the list keeps the cursor on it
+	press(m, "t")
+	if got := m.selected(); got == nil || got.ID != 2 {
+		t.Errorf("after filtering by tag selected %v, want ADR 2", got)
+	}
+
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd == nil {
+		t.Error("q does not quit")
+	}
+}
+
+func TestStatusChange(t *testing.T) {
+	m := newTestModel(t)
+
+	// ADR 3 is In Progress and may go back to Draft, or be accepted or rejected
+	press(m, "end", "s")
+	if m.mode != modeStatus || !reflect.DeepEqual(m.statusChoices, []adr.Status{adr.StatusDraft, adr.StatusAccepted, adr.StatusRejected}) {
+		t.Fatalf("mode %d, choices %v", m.mode, m.statusChoices)
+	}
+	press(m, "esc")
+	if m.mode != modeBrowse || storedStatus(t, m, 3) != adr.StatusInProgress {
+		t.Fatal("cancelling the picker changed something")
+	}
+
+	// The template placeholders fail the acceptance checks
+	press(m, "s", "down", "enter")
+	if m.mode != modeConfirmAccept || len(m.acceptIssues) == 0 {
+		t.Fatalf("mode %d, issues %v, want the acceptance confirmation", m.mode, m.acceptIssues)
+	}
+	if !strings.Contains(m.View(), "Not ready for acceptance") {
+		t.Errorf("view does not show the acceptance issues:\n%s", m.View())
+	}
+	press(m, "n")
+	if m.mode != modeBrowse || storedStatus(t, m, 3) != adr.StatusInProgress {
+		t.Fatal("declining the confirmation changed the status")
+	}
+
+	press(m, "s", "down", "enter", "F")
+	if got := storedStatus(t, m, 3); got != adr.StatusAccepted {
+		t.Fatalf("status = %s, want Accepted after accepting anyway", got)
+	}
+	if m.selected().ID != 3 || m.selected().Status != adr.StatusAccepted || !strings.Contains(m.message, "from In Progress to Accepted") {
+		t.Errorf("selected %+v, message %q", m.selected(), m.message)
+	}
+
+	// Changes other than acceptance apply right away
+	press(m, "home", "s", "enter")
+	if got := storedStatus(t, m, 1); got != adr.StatusInProgress {
+		t.Errorf("status = %s, want In Progress", got)
+	}
+
+	// Superseded ADRs cannot change status
+	press(m, "j", "s", "enter")
+	if got := storedStatus(t, m, 2); got != adr.StatusSuperseded {
+		t.Fatalf("status = %s, want Superseded", got)
+	}
+	press(m, "s")
+	if m.mode != modeBrowse || !m.isError || !strings.Contains(m.message, "cannot change status") {
+		t.Errorf("mode %d, message %q, want an error", m.mode, m.message)
+	}
+}
@@ -0,0 +1,418 @@
+package web
+
+import (
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/drift"
+	"github.com/SilverFlin/DrDuck/internal/hooks"
+	"github.com/SilverFlin/DrDuck/internal/search"
+)
+
+// APIPrefix is the path under which the current API version is served
+const APIPrefix = "/api/v1"
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+// registerAPI adds the JSON API routes
+func (s *Server) registerAPI(mux *http.ServeMux) {
+	mux.HandleFunc("GET "+APIPrefix+"/openapi.json", s.apiOpenAPI)
+	mux.HandleFunc("GET "+APIPrefix+"/adrs", s.apiListADRs)
+	mux.HandleFunc("POST "+APIPrefix+"/adrs", s.apiCreateADR)
+	mux.HandleFunc("GET "+APIPrefix+"/adrs/{id}", s.apiGetADR)
+	mux.HandleFunc("PUT "+APIPrefix+"/adrs/{id}/status", s.apiSetStatus)
+	mux.HandleFunc("POST "+APIPrefix+"/validate", s.apiValidate)
+	mux.HandleFunc("GET "+APIPrefix+"/search", s.apiSearch)
+}
+
+// apiADR is an ADR as returned by the API
+type apiADR struct {
+	ID       int           `json:"id"`
+	Title    string        `json:"title"`
+	Status   adr.Status    `json:"status"`
+	Date     string        `json:"date"`
+	Tags     []string      `json:"tags"`
+	Governs  []string      `json:"governs"`
+	Path     string        `json:"path"`
+	Content  string        `json:"content,omitempty"`  // Markdown without front matter, single ADR only
+	Sections []adr.Section `json:"sections,omitempty"` // Single ADR only
+}
+
+// apiError is the body of every API error response
+type apiError struct {
+	Error  string   `json:"error"`
+	Issues []string `json:"issues,omitempty"`
+}
+
+// toAPIADR converts an ADR to its API representation
+func toAPIADR(a *adr.ADR) apiADR {
+	tags, governs := a.Tags, a.Governs
+	if tags == nil {
+		tags = []string{}
+	}
+	if governs == nil {
+		governs = []string{}
+	}
+	return apiADR{
+		ID:      a.ID,
+		Title:   a.Title,
+		Status:  a.Status,
+		Date:    a.Date.Format("2006-01-02"),
+		Tags:    tags,
+		Governs: governs,
+		Path:    a.FilePath,
+	}
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(body); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to write API response: %v\n", err)
+	}
+}
+
+// writeAPIError writes a JSON error response
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, apiError{Error: message})
+}
+
+// authorizeChange checks that a request may change something: it must carry
+// the API token when one is configured, and otherwise come from this server's
+// own pages or this machine. It writes the error response if not.
+func (s *Server) authorizeChange(w http.ResponseWriter, r *http.Request) bool {
+	if s.opts.Token != "" {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="drduck"`)
+			writeAPIError(w, http.StatusUnauthorized, "a valid API token is required")
+			return false
+		}
+		return true
+	}
+
+	if !sameOrigin(r) {
+		writeAPIError(w, http.StatusForbidden, "cross-origin requests are not allowed")
+		return false
+	}
+	return true
+}
+
+// readJSON authorizes a change and decodes its JSON request body. Requiring
+// the JSON content type also keeps other websites from posting to the API
+// without a CORS preflight.
+func (s *Server) readJSON(w http.ResponseWriter, r *http.Request, body any) bool {
+	if !s.authorizeChange(w, r) {
+		return false
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		writeAPIError(w, http.StatusUnsupportedMediaType, "request body must be application/json")
+		return false
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(body); err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+// apiLookupADR finds the ADR named in the request path, writing a 404 if there is none
+func (s *Server) apiLookupADR(w http.ResponseWriter, r *http.Request) (*adr.ADR, bool) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(strings.TrimLeft(idStr, "0"))
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("invalid ADR ID: %s", idStr))
+		return nil, false
+	}
+
+	target, err := s.adrs.GetADRByID(id)
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("ADR not found: %v", err))
+		return nil, false
+	}
+	return target, true
+}
+
+func (s *Server) apiOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
+
+// apiListADRs lists ADRs, optionally filtered with the same status, tag and
+// date filters as drduck search
+func (s *Server) apiListADRs(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := search.Query{
+		Statuses: params["status"],
+		Tags:     params["tag"],
+	}
+	for field, date := range map[string]*time.Time{"after": &query.After, "before": &query.Before} {
+		value := params.Get(field)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid date in %s=%s, expected YYYY-MM-DD", field, value))
+			return
+		}
+		*date = parsed
+	}
+
+	adrs, err := s.adrs.List()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Sprintf("failed to list ADRs: %v", err))
+		return
+	}
+
+	result := []apiADR{}
+	for _, a := range adrs {
+		if query.MatchesADR(a) {
+			result = append(result, toAPIADR(a))
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"adrs": result})
+}
+
+func (s *Server) apiGetADR(w http.ResponseWriter, r *http.Request) {
+	target, ok := s.apiLookupADR(w, r)
+	if !ok {
+		return
+	}
+
+	content, err := os.ReadFile(target.FilePath)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Sprintf("failed to read ADR file: %v", err))
+		return
+	}
+
+	result := toAPIADR(target)
+	_, result.Content = adr.SplitFrontMatter(string(content))
+	result.Sections = adr.ParseSections(result.Content)
+	writeJSON(w, http.StatusOK, result)
+}
+
+// createRequest is the body of POST /adrs
+type createRequest struct {
+	Title string `json:"title"`
+}
+
+func (s *Server) apiCreateADR(w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	if !s.readJSON(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	created, err := s.adrs.Create(req.Title)
+	s.mu.Unlock()
+	if errors.Is(err, adr.ErrInvalidTitle) {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Sprintf("failed to create ADR: %v", err))
+		return
+	}
+	fmt.Printf("✅ Created ADR-%04d: %s\n", created.ID, created.Title)
+
+	w.Header().Set("Location", fmt.Sprintf("%s/adrs/%d", APIPrefix, created.ID))
+	writeJSON(w, http.StatusCreated, toAPIADR(created))
+}
+
+// statusRequest is the body of PUT /adrs/{id}/status
+type statusRequest struct {
+	Status string `json:"status"`
+	Force  bool   `json:"force"` // Accept even if the content checks fail
+}
+
+func (s *Server) apiSetStatus(w http.ResponseWriter, r *http.Request) {
+	var req statusRequest
+	if !s.readJSON(w, r, &req) {
+		return
+	}
+
+	newStatus, err := s.parseStatus(req.Status)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid status: %v", err))
+		return
+	}
+
+	target, ok := s.apiLookupADR(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.setStatus(target, newStatus, req.Force); err != nil {
+		var refused *statusError
+		if errors.As(err, &refused) {
+			writeJSON(w, http.StatusConflict, apiError{Error: refused.Message, Issues: refused.Issues})
+			return
+		}
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPIADR(target))
+}
+
+// parseStatus accepts a status as stored in ADRs ("In Progress") or as typed
+// on the command line ("in-progress")
+func (s *Server) parseStatus(status string) (adr.Status, error) {
+	for _, known := range allStatuses {
+		if strings.EqualFold(status, string(known)) {
+			return known, nil
+		}
+	}
+	return adr.ParseStatus(status)
+}
+
+// apiValidation is the result of validating the current changes
+type apiValidation struct {
+	Hook           string     `json:"hook"`
+	ShouldBlock    bool       `json:"should_block"`
+	NeedsADR       bool       `json:"needs_adr"`
+	SuggestedTitle string     `json:"suggested_title,omitempty"`
+	DraftADRs      []apiADR   `json:"draft_adrs"`
+	Drift          []apiDrift `json:"drift"`
+	Analysis       string     `json:"analysis,omitempty"` // The AI's reasoning
+	Message        string     `json:"message"`            // What the git hook would print
+}
+
+// apiDrift is an accepted ADR governing changed paths
+type apiDrift struct {
+	ADR         apiADR   `json:"adr"`
+	Files       []string `json:"files"`
+	Checked     bool     `json:"checked"` // Whether the AI was asked about contradictions
+	Contradicts bool     `json:"contradicts"`
+	Reasoning   string   `json:"reasoning,omitempty"`
+}
+
+// apiValidate runs the pre-commit or pre-push validation on the current
+// changes, without prompting
+func (s *Server) apiValidate(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeChange(w, r) {
+		return
+	}
+
+	validator := hooks.NewValidator(s.opts.Config)
+	validator.SetInteractive(false)
+
+	hook := r.URL.Query().Get("hook")
+	var result *hooks.ValidationResult
+	switch hook {
+	case "", "pre-push":
+		hook = "pre-push"
+		result = validator.ValidatePrePush()
+	case "pre-commit":
+		result = validator.ValidatePreCommit()
+	default:
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("unknown hook %q, expected pre-commit or pre-push", hook))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAPIValidation(hook, result))
+}
+
+// toAPIValidation converts a hook validation result to its API representation
+func toAPIValidation(hook string, result *hooks.ValidationResult) apiValidation {
+	validation := apiValidation{
+		Hook:           hook,
+		ShouldBlock:    result.ShouldBlock,
+		NeedsADR:       result.NeedsADR,
+		SuggestedTitle: result.SuggestedTitle,
+		DraftADRs:      []apiADR{},
+		Drift:          []apiDrift{},
+		Analysis:       result.AIResponse,
+		Message:        result.Message,
+	}
+	for _, draft := range result.DraftADRs {
+		validation.DraftADRs = append(validation.DraftADRs, toAPIADR(draft))
+	}
+	for _, finding := range result.Drift {
+		validation.Drift = append(validation.Drift, toAPIDrift(finding))
+	}
+	return validation
+}
+
+// toAPIDrift converts a drift finding to its API representation
+func toAPIDrift(finding drift.Finding) apiDrift {
+	return apiDrift{
+		ADR:         toAPIADR(finding.ADR),
+		Files:       finding.Files,
+		Checked:     finding.Checked,
+		Contradicts: finding.Contradicts,
+		Reasoning:   finding.Reasoning,
+	}
+}
+
+// apiSearchResult is a search match as returned by the API
+type apiSearchResult struct {
+	ADR      apiADR           `json:"adr"`
+	Score    float64          `json:"score"`
+	Snippets []search.Snippet `json:"snippets"`
+}
+
+func (s *Server) apiSearch(w http.ResponseWriter, r *http.Request) {
+	query, err := search.ParseQuery(r.URL.Query().Get("q"))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit := 10
+	if value := r.URL.Query().Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 0 {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid limit: %s", value))
+			return
+		}
+	}
+
+	index, err := s.searchIndex()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	adrs, err := s.adrs.List()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Sprintf("failed to list ADRs: %v", err))
+		return
+	}
+	byPath := make(map[string]*adr.ADR, len(adrs))
+	for _, a := range adrs {
+		byPath[a.FilePath] = a
+	}
+
+	results := []apiSearchResult{}
+	for _, result := range index.Search(query, limit) {
+		found, exists := byPath[result.Entry.Path]
+		if !exists {
+			continue // Removed since the index was updated
+		}
+		snippets := result.Snippets
+		if snippets == nil {
+			snippets = []search.Snippet{}
+		}
+		results = append(results, apiSearchResult{ADR: toAPIADR(found), Score: result.Score, Snippets: snippets})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"results": results})
+}
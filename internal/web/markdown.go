@@ -0,0 +1,108 @@
+package web
+
+import (
+	"html"
+	"html/template"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/SilverFlin/DrDuck/internal/markdown"
+)
+
+var (
+	linkPattern        = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	boldPattern        = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	italicPattern      = regexp.MustCompile(`\*([^*\s][^*]*?)\*`)
+	adrFileLinkPattern = regexp.MustCompile(`^(?:\./)?(\d+)-[^/]*\.md$`)
+)
+
+// renderMarkdown renders the subset of Markdown used in ADRs: headings,
+// paragraphs, lists, block quotes, code blocks, rules and inline emphasis,
+// code and links. Raw HTML is escaped and template comments are dropped.
+func renderMarkdown(source string) template.HTML {
+	var out strings.Builder
+	renderBlocks(&out, markdown.Parse(source))
+	return template.HTML(out.String())
+}
+
+// renderBlocks writes blocks as HTML
+func renderBlocks(out *strings.Builder, blocks []markdown.Block) {
+	for _, block := range blocks {
+		switch block.Kind {
+		case markdown.Paragraph:
+			out.WriteString("<p>" + renderInline(block.Text) + "</p>\n")
+		case markdown.Heading:
+			level := strconv.Itoa(block.Level)
+			out.WriteString("<h" + level + ">" + renderInline(block.Text) + "</h" + level + ">\n")
+		case markdown.Code:
+			out.WriteString("<pre><code>" + html.EscapeString(strings.Join(block.Lines, "\n")) + "</code></pre>\n")
+		case markdown.Rule:
+			out.WriteString("<hr>\n")
+		case markdown.Quote:
+			out.WriteString("<blockquote>")
+			renderBlocks(out, block.Blocks)
+			out.WriteString("</blockquote>\n")
+		case markdown.List:
+			tag := "ul"
+			if block.Ordered {
+				tag = "ol"
+			}
+			out.WriteString("<" + tag + ">\n")
+			for _, item := range block.Items {
+				out.WriteString("<li>" + renderInline(item.Text) + "</li>\n")
+			}
+			out.WriteString("</" + tag + ">\n")
+		}
+	}
+}
+
+// renderInline escapes a line of text and renders code spans, links and emphasis
+func renderInline(text string) string {
+	var out strings.Builder
+
+	// Odd parts are inside backticks and rendered verbatim
+	for i, part := range strings.Split(text, "`") {
+		if i%2 == 1 {
+			out.WriteString("<code>" + html.EscapeString(part) + "</code>")
+			continue
+		}
+
+		part = html.EscapeString(part)
+		part = linkPattern.ReplaceAllStringFunc(part, func(link string) string {
+			match := linkPattern.FindStringSubmatch(link)
+			href := safeHref(html.UnescapeString(match[2]))
+			if href == "" {
+				return match[1]
+			}
+			return `<a href="` + html.EscapeString(href) + `">` + match[1] + "</a>"
+		})
+		// Only asterisk emphasis: underscores are common in URLs and identifiers
+		part = boldPattern.ReplaceAllString(part, "<strong>$1</strong>")
+		part = italicPattern.ReplaceAllString(part, "<em>$1</em>")
+		out.WriteString(part)
+	}
+	return out.String()
+}
+
+// safeHref returns the target of a Markdown link, pointing links to other ADR
+// files at their page. Links with schemes other than http, https and mailto
+// are dropped.
+func safeHref(href string) string {
+	if match := adrFileLinkPattern.FindStringSubmatch(href); match != nil {
+		if id, err := strconv.Atoi(match[1]); err == nil {
+			return "/adrs/" + strconv.Itoa(id)
+		}
+	}
+
+	scheme, _, hasScheme := strings.Cut(href, ":")
+	if hasScheme && !strings.ContainsAny(scheme, "/?#") {
+		switch strings.ToLower(scheme) {
+		case "http", "https", "mailto":
+			return href
+		default:
+			return ""
+		}
+	}
+	return href
+}
@@ -0,0 +1,117 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// broadcaster notifies the open pages that they should reload
+type broadcaster struct {
+	mu      sync.Mutex
+	clients map[chan struct{}]bool
+}
+
+// newBroadcaster creates a broadcaster without clients
+func newBroadcaster() *broadcaster {
+	return &broadcaster{clients: make(map[chan struct{}]bool)}
+}
+
+// subscribe registers a client; its channel receives a value on every change
+func (b *broadcaster) subscribe() chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ch := make(chan struct{}, 1)
+	b.clients[ch] = true
+	return ch
+}
+
+// unsubscribe removes a client
+func (b *broadcaster) unsubscribe(ch chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.clients, ch)
+}
+
+// notify tells every client to reload, without blocking on slow ones
+func (b *broadcaster) notify() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.clients {
+		select {
+		case ch <- struct{}{}:
+		default: // A reload is already pending
+		}
+	}
+}
+
+// handleEvents streams a server-sent "reload" event whenever ADR files change
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher.Flush()
+
+	ch := s.reloads.subscribe()
+	defer s.reloads.unsubscribe(ch)
+
+	keepAlive := time.NewTicker(30 * time.Second)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ch:
+			fmt.Fprint(w, "data: reload\n\n")
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+		flusher.Flush()
+	}
+}
+
+// Watch polls the ADR files and the configuration until the context is
+// cancelled, telling open pages to reload when any of them changes
+func (s *Server) Watch(ctx context.Context, interval time.Duration) {
+	last := s.snapshot()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if current := s.snapshot(); current != last {
+				last = current
+				s.reloads.notify()
+			}
+		}
+	}
+}
+
+// snapshot fingerprints the ADR files by path, size and modification time
+func (s *Server) snapshot() string {
+	var b strings.Builder
+
+	adrs, err := s.adrs.List()
+	if err != nil {
+		return ""
+	}
+	for _, a := range adrs {
+		if info, err := os.Stat(a.FilePath); err == nil {
+			fmt.Fprintf(&b, "%s|%d|%d\n", a.FilePath, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,496 @@
+package web
+
+import (
+	"crypto/rand"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/ai"
+	"github.com/SilverFlin/DrDuck/internal/cache"
+	"github.com/SilverFlin/DrDuck/internal/config"
+	"github.com/SilverFlin/DrDuck/internal/search"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// Statuses in the order they are shown
+var allStatuses = []adr.Status{
+	adr.StatusDraft,
+	adr.StatusInProgress,
+	adr.StatusAccepted,
+	adr.StatusRejected,
+	adr.StatusSuperseded,
+}
+
+// Options configures the web UI
+type Options struct {
+	Config *config.Config
+	Cache  *cache.Manager
+
+	// Host is the address the server listens on; requests addressed to any
+	// other host are refused
+	Host string
+
+	// API also serves the JSON API under /api/v1
+	API bool
+
+	// Token, when set, must be sent as "Authorization: Bearer <token>" with
+	// every API request that changes something, and entered on the sign-in
+	// page before changing status in the UI
+	Token string
+}
+
+// Server serves the read-mostly web UI over the project's ADRs
+type Server struct {
+	mu      sync.Mutex // Serializes changes to ADR files
+	opts    Options
+	adrs    *adr.Manager
+	pages   map[string]*template.Template
+	reloads *broadcaster
+	secret  []byte // Signs the CSRF tokens of browser sessions
+}
+
+// New creates a web UI server for the project
+func New(opts Options) (*Server, error) {
+	funcs := template.FuncMap{
+		"icon":        statusIcon,
+		"statusClass": statusClass,
+		"slug":        statusSlug,
+		"percent":     func(score float64) string { return fmt.Sprintf("%d%%", int(score*100)) },
+		"date":        func(t time.Time) string { return t.Format("2006-01-02") },
+		"age":         formatAge,
+		"highlight":   highlightHTML,
+		"shortHash":   shortHash,
+		"shortCommit": shortCommit,
+	}
+
+	pages := make(map[string]*template.Template)
+	for _, page := range []string{"list.html", "adr.html", "status.html", "debt.html", "login.html"} {
+		tmpl, err := template.New(page).Funcs(funcs).ParseFS(templateFS, "templates/layout.html", "templates/"+page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", page, err)
+		}
+		pages[page] = tmpl
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate session secret: %w", err)
+	}
+
+	return &Server{
+		opts:    opts,
+		adrs:    adr.NewManager(opts.Config),
+		pages:   pages,
+		reloads: newBroadcaster(),
+		secret:  secret,
+	}, nil
+}
+
+// Handler returns the HTTP handler serving the UI
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleList)
+	mux.HandleFunc("GET /adrs/{id}", s.handleADR)
+	mux.HandleFunc("POST /adrs/{id}/status", s.handleSetStatus)
+	mux.HandleFunc("GET /status", s.handleStatus)
+	mux.HandleFunc("GET /debt", s.handleDebt)
+	mux.HandleFunc("GET /events", s.handleEvents)
+	mux.HandleFunc("GET /login", s.handleLogin)
+	mux.HandleFunc("POST /login", s.handleLogin)
+	if s.opts.API {
+		s.registerAPI(mux)
+	}
+	return s.checkHost(mux)
+}
+
+// page is the data shared by all pages
+type page struct {
+	Title   string
+	Nav     string
+	Project string
+	Data    any
+}
+
+// render writes a page, or an error page when rendering fails
+func (s *Server) render(w http.ResponseWriter, status int, name, title string, data any) {
+	project, _ := os.Getwd()
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	err := s.pages[name].ExecuteTemplate(w, "layout", page{
+		Title:   title,
+		Nav:     strings.TrimSuffix(name, ".html"),
+		Project: project,
+		Data:    data,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to render %s: %v\n", name, err)
+	}
+}
+
+// listPage is the data of the ADR list
+type listPage struct {
+	Query    string
+	Error    string
+	Statuses []adr.Status
+	Results  []search.Result
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	data := listPage{Query: r.URL.Query().Get("q"), Statuses: allStatuses}
+
+	query, err := search.ParseQuery(data.Query)
+	if err != nil {
+		data.Error = err.Error()
+		s.render(w, http.StatusBadRequest, "list.html", "ADRs", data)
+		return
+	}
+
+	index, err := s.searchIndex()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data.Results = index.Search(query, 0)
+	s.render(w, http.StatusOK, "list.html", "ADRs", data)
+}
+
+// searchIndex loads the search index and re-indexes changed ADR files
+func (s *Server) searchIndex() (*search.Index, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config directory: %w", err)
+	}
+
+	adrs, err := s.adrs.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ADRs: %w", err)
+	}
+
+	return search.Load(configDir, adrs, false)
+}
+
+// adrPage is the data of a single ADR
+type adrPage struct {
+	ADR         *adr.ADR
+	Body        template.HTML
+	Transitions []adr.Status
+	Error       string
+	Issues      []string // Why acceptance was refused, offering to accept anyway
+	CSRF        string   // Token the status forms must post back
+	SignedIn    bool     // Whether the session may change status
+}
+
+func (s *Server) handleADR(w http.ResponseWriter, r *http.Request) {
+	s.showADR(w, r, http.StatusOK, "", nil)
+}
+
+// showADR renders an ADR page, with the error of a refused status change if any
+func (s *Server) showADR(w http.ResponseWriter, r *http.Request, status int, message string, issues []string) {
+	target, ok := s.lookupADR(w, r)
+	if !ok {
+		return
+	}
+
+	content, err := os.ReadFile(target.FilePath)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read ADR file: %v", err), http.StatusInternalServerError)
+		return
+	}
+	_, body := adr.SplitFrontMatter(string(content))
+
+	data := adrPage{
+		ADR:      target,
+		Body:     renderMarkdown(body),
+		Error:    message,
+		Issues:   issues,
+		CSRF:     s.csrfToken(w, r),
+		SignedIn: s.signedIn(r),
+	}
+	for _, to := range allStatuses {
+		if to != target.Status && adr.ValidateTransition(target.Status, to) == nil {
+			data.Transitions = append(data.Transitions, to)
+		}
+	}
+
+	s.render(w, status, "adr.html", fmt.Sprintf("ADR-%04d: %s", target.ID, target.Title), data)
+}
+
+func (s *Server) handleSetStatus(w http.ResponseWriter, r *http.Request) {
+	if !sameOrigin(r) {
+		http.Error(w, "cross-origin requests are not allowed", http.StatusForbidden)
+		return
+	}
+	if !s.validCSRF(r) {
+		http.Error(w, "invalid or missing CSRF token, reload the page and try again", http.StatusForbidden)
+		return
+	}
+	if !s.signedIn(r) {
+		http.Error(w, "sign in with the server's token to change ADRs", http.StatusUnauthorized)
+		return
+	}
+
+	target, ok := s.lookupADR(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.setStatus(target, adr.Status(r.FormValue("status")), r.FormValue("force") == "true"); err != nil {
+		var refused *statusError
+		if !errors.As(err, &refused) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		s.showADR(w, r, http.StatusConflict, refused.Message, refused.Issues)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/adrs/%d", target.ID), http.StatusSeeOther)
+}
+
+// statusError is a status change refused by the transition rules or the
+// acceptance checks
+type statusError struct {
+	Message string
+	Issues  []string // Why acceptance was refused; forcing accepts anyway
+}
+
+func (e *statusError) Error() string {
+	return e.Message
+}
+
+// setStatus changes an ADR's status following the rules of drduck set-status
+// and, unless forced, the content checks of drduck accept
+func (s *Server) setStatus(target *adr.ADR, newStatus adr.Status, force bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previousStatus := target.Status
+	issues, err := s.adrs.ChangeStatus(target, newStatus, force)
+	if errors.Is(err, adr.ErrInvalidTransition) {
+		return &statusError{Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	if len(issues) > 0 {
+		return &statusError{Message: "ADR is not ready for acceptance", Issues: issues}
+	}
+
+	if target.Status != previousStatus {
+		fmt.Printf("📊 ADR-%04d status updated from %s to %s\n", target.ID, previousStatus, target.Status)
+	}
+	return nil
+}
+
+// lookupADR finds the ADR named in the request path, writing a 404 if there is none
+func (s *Server) lookupADR(w http.ResponseWriter, r *http.Request) (*adr.ADR, bool) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(strings.TrimLeft(idStr, "0"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid ADR ID: %s", idStr), http.StatusNotFound)
+		return nil, false
+	}
+
+	target, err := s.adrs.GetADRByID(id)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("ADR not found: %v", err), http.StatusNotFound)
+		return nil, false
+	}
+	return target, true
+}
+
+// sameOrigin reports whether a form was posted from this server's own pages,
+// so other websites open in the browser cannot change ADRs. Requests without
+// the browser's origin headers are only trusted from this machine.
+func sameOrigin(r *http.Request) bool {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		return err == nil && u.Host == r.Host
+	}
+	switch r.Header.Get("Sec-Fetch-Site") {
+	case "same-origin", "none":
+		return true
+	case "":
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		return err == nil && IsLoopback(host)
+	}
+	return false
+}
+
+// IsLoopback reports whether a host name or address only reaches this machine
+func IsLoopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// statusCount is the number of ADRs with a status
+type statusCount struct {
+	Status adr.Status
+	Count  int
+}
+
+// statusPage is the data of the dashboard, the same overview as drduck status
+type statusPage struct {
+	Config      *config.Config
+	AIAvailable bool
+	Total       int
+	Counts      []statusCount
+	Drafts      []*adr.ADR
+	Error       string
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	data := statusPage{
+		Config:      s.opts.Config,
+		AIAvailable: ai.NewManager(s.opts.Config).IsAvailable(),
+	}
+
+	counts, err := s.adrs.GetStatusCounts()
+	if err != nil {
+		data.Error = fmt.Sprintf("Could not get ADR status: %v", err)
+	} else {
+		for _, status := range allStatuses {
+			data.Total += counts[status]
+			if counts[status] > 0 {
+				data.Counts = append(data.Counts, statusCount{Status: status, Count: counts[status]})
+			}
+		}
+		if counts[adr.StatusDraft] > 0 {
+			data.Drafts, _ = s.adrs.GetDraftADRs()
+		}
+	}
+
+	s.render(w, http.StatusOK, "status.html", "Status", data)
+}
+
+// debtPage is the data of the cache and decision debt view
+type debtPage struct {
+	Stats []stat
+	Debt  []cache.DebtItem
+	Error string
+}
+
+// stat is a labelled cache statistic
+type stat struct {
+	Label string
+	Value any
+}
+
+func (s *Server) handleDebt(w http.ResponseWriter, r *http.Request) {
+	var data debtPage
+
+	stats, err := s.opts.Cache.GetCacheStats()
+	if err != nil {
+		data.Error = fmt.Sprintf("Could not get cache statistics: %v", err)
+	} else {
+		data.Stats = []stat{
+			{"Backend", stats["backend"]},
+			{"Total entries", stats["total_entries"]},
+			{"Resolved entries", stats["resolved_entries"]},
+			{"Unresolved entries", stats["unresolved_entries"]},
+			{"Cached summaries", stats["chunk_summaries"]},
+			{"Max age (days)", stats["max_age_days"]},
+			{"Max entries", stats["max_entries"]},
+		}
+		if hitRate, ok := stats["file_hit_rate"].(float64); ok {
+			data.Stats = append(data.Stats, stat{"Per-file hit rate", fmt.Sprintf("%.1f%%", hitRate*100)})
+		}
+	}
+
+	data.Debt, err = s.opts.Cache.Debt()
+	if err != nil {
+		data.Error = fmt.Sprintf("Could not load decision debt: %v", err)
+	}
+
+	s.render(w, http.StatusOK, "debt.html", "Cache & Debt", data)
+}
+
+// statusIcon returns the icon drduck list shows for a status. Statuses are
+// adr.Status or plain strings, as stored in the search index.
+func statusIcon(status any) string {
+	switch adr.Status(fmt.Sprint(status)) {
+	case adr.StatusDraft:
+		return "📝"
+	case adr.StatusInProgress:
+		return "⚡"
+	case adr.StatusAccepted:
+		return "✅"
+	case adr.StatusSuperseded:
+		return "⏭️"
+	case adr.StatusRejected:
+		return "❌"
+	default:
+		return "❓"
+	}
+}
+
+// statusSlug returns a status as used in search filters, e.g. "in-progress"
+func statusSlug(status any) string {
+	return strings.ReplaceAll(strings.ToLower(fmt.Sprint(status)), " ", "-")
+}
+
+// statusClass returns the CSS class of a status, e.g. "status-in-progress"
+func statusClass(status any) string {
+	return "status-" + statusSlug(status)
+}
+
+// formatAge renders how long ago something happened in days, weeks or months
+func formatAge(t time.Time) string {
+	days := int(time.Since(t).Hours() / 24)
+	switch {
+	case days < 1:
+		return "today"
+	case days < 14:
+		return fmt.Sprintf("%d days", days)
+	case days < 60:
+		return fmt.Sprintf("%d weeks", days/7)
+	default:
+		return fmt.Sprintf("%d months", days/30)
+	}
+}
+
+// highlightHTML escapes text and marks the highlighted spans
+func highlightHTML(text string, spans []search.Span) template.HTML {
+	var b strings.Builder
+	last := 0
+	for _, span := range spans {
+		b.WriteString(template.HTMLEscapeString(text[last:span.Start]))
+		b.WriteString("<mark>" + template.HTMLEscapeString(text[span.Start:span.End]) + "</mark>")
+		last = span.End
+	}
+	b.WriteString(template.HTMLEscapeString(text[last:]))
+	return template.HTML(b.String())
+}
+
+// shortHash abbreviates a content hash for display
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
+// shortCommit abbreviates a commit hash for display
+func shortCommit(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,292 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/SilverFlin/DrDuck/internal/adr"
+	"github.com/SilverFlin/DrDuck/internal/config"
+)
+
+// newTestServer creates a server over a project with one draft ADR
+func newTestServer(t *testing.T, opts Options) *Server {
+	t.Helper()
+
+	t.Chdir(t.TempDir())
+	opts.Config = config.DefaultConfig()
+	if _, err := adr.NewManager(opts.Config).Create("Use Redis for caching"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	server, err := New(opts)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return server
+}
+
+// serve sends a request addressed to localhost:8080 from the UI's own origin
+func serve(server *Server, method, target string, form url.Values, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	var body *strings.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	} else {
+		body = strings.NewReader("")
+	}
+
+	req := httptest.NewRequest(method, target, body)
+	req.Host = "localhost:8080"
+	req.Header.Set("Origin", "http://localhost:8080")
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, req)
+	return recorder
+}
+
+var csrfPattern = regexp.MustCompile(`name="csrf" value="([0-9a-f]+)"`)
+
+// openADR loads an ADR page, returning its session cookies and CSRF token
+func openADR(t *testing.T, server *Server) ([]*http.Cookie, string) {
+	t.Helper()
+	return openPage(t, server, "/adrs/1")
+}
+
+// openPage loads a page with a form, returning its session cookies and CSRF token
+func openPage(t *testing.T, server *Server, target string) ([]*http.Cookie, string) {
+	t.Helper()
+
+	response := serve(server, http.MethodGet, target, nil, nil)
+	if response.Code != http.StatusOK {
+		t.Fatalf("GET %s = %d\n%s", target, response.Code, response.Body)
+	}
+	match := csrfPattern.FindStringSubmatch(response.Body.String())
+	if match == nil {
+		t.Fatalf("forms of %s carry no CSRF token:\n%s", target, response.Body)
+	}
+	return response.Result().Cookies(), match[1]
+}
+
+// storedStatus reads the status of ADR 1 from disk
+func storedStatus(t *testing.T, server *Server) adr.Status {
+	t.Helper()
+
+	stored, err := server.adrs.GetADRByID(1)
+	if err != nil {
+		t.Fatalf("GetADRByID: %v", err)
+	}
+	return stored.Status
+}
+
+func TestSetStatusRequiresCSRFToken(t *testing.T) {
+	server := newTestServer(t, Options{Host: "localhost"})
+	cookies, token := openADR(t, server)
+
+	otherCookies, _ := openADR(t, server)
+	tests := []struct {
+		name    string
+		form    url.Values
+		cookies []*http.Cookie
+	}{
+		{"no token", url.Values{"status": {"In Progress"}}, cookies},
+		{"wrong token", url.Values{"status": {"In Progress"}, "csrf": {strings.Repeat("0", 64)}}, cookies},
+		{"no session", url.Values{"status": {"In Progress"}, "csrf": {token}}, nil},
+		{"other session", url.Values{"status": {"In Progress"}, "csrf": {token}}, otherCookies},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := serve(server, http.MethodPost, "/adrs/1/status", tt.form, tt.cookies)
+			if response.Code != http.StatusForbidden {
+				t.Errorf("POST = %d, want 403", response.Code)
+			}
+		})
+	}
+	if status := storedStatus(t, server); status != adr.StatusDraft {
+		t.Fatalf("status changed to %s by a rejected request", status)
+	}
+
+	response := serve(server, http.MethodPost, "/adrs/1/status", url.Values{"status": {"In Progress"}, "csrf": {token}}, cookies)
+	if response.Code != http.StatusSeeOther {
+		t.Fatalf("POST with CSRF token = %d, want 303\n%s", response.Code, response.Body)
+	}
+	if status := storedStatus(t, server); status != adr.StatusInProgress {
+		t.Errorf("status = %s, want In Progress", status)
+	}
+}
+
+func TestAllowedHost(t *testing.T) {
+	tests := []struct {
+		bound string
+		host  string
+		want  bool
+	}{
+		{"localhost", "localhost:8080", true},
+		{"localhost", "127.0.0.1:8080", true},
+		{"localhost", "[::1]:8080", true},
+		{"localhost", "LOCALHOST", true},
+		{"localhost", "rebind.example.com:8080", false},
+		{"localhost", "192.168.1.20:8080", false},
+		{"drduck.internal", "drduck.internal:8080", true},
+		{"drduck.internal", "rebind.example.com:8080", false},
+		{"192.168.1.20", "192.168.1.20:8080", true},
+		{"192.168.1.20", "192.168.1.21:8080", false},
+		{"0.0.0.0", "192.168.1.20:8080", true},
+		{"::", "[fe80::1]:8080", true},
+		{"0.0.0.0", "rebind.example.com:8080", false},
+	}
+	for _, tt := range tests {
+		server := &Server{opts: Options{Host: tt.bound}}
+		if got := server.allowedHost(tt.host); got != tt.want {
+			t.Errorf("bound to %s, allowedHost(%q) = %v, want %v", tt.bound, tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestHandlerRejectsUnknownHost(t *testing.T) {
+	server := newTestServer(t, Options{Host: "localhost", API: true})
+	cookies, token := openADR(t, server)
+
+	for _, target := range []string{"/adrs/1", "/adrs/1/status", APIPrefix + "/adrs"} {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(url.Values{"status": {"In Progress"}, "csrf": {token}}.Encode()))
+		req.Host = "rebind.example.com:8080"
+		req.Header.Set("Origin", "http://rebind.example.com:8080")
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusForbidden {
+			t.Errorf("POST %s from a rebound host = %d, want 403", target, recorder.Code)
+		}
+	}
+	if status := storedStatus(t, server); status != adr.StatusDraft {
+		t.Errorf("status changed to %s", status)
+	}
+}
+
+func TestUIStatusChangeRequiresSignIn(t *testing.T) {
+	server := newTestServer(t, Options{Host: "localhost", Token: "secret"})
+
+	page := serve(server, http.MethodGet, "/adrs/1", nil, nil)
+	if !strings.Contains(page.Body.String(), `href="/login?next=/adrs/1"`) || strings.Contains(page.Body.String(), `name="status"`) {
+		t.Errorf("signed-out page offers status changes:\n%s", page.Body)
+	}
+
+	cookies, csrf := openPage(t, server, "/login?next=/adrs/1")
+	change := url.Values{"status": {"In Progress"}, "csrf": {csrf}}
+
+	// A valid CSRF token alone is not enough once a token is configured
+	if response := serve(server, http.MethodPost, "/adrs/1/status", change, cookies); response.Code != http.StatusUnauthorized {
+		t.Errorf("tokenless POST = %d, want 401", response.Code)
+	}
+	forged := append(slices.Clone(cookies), &http.Cookie{Name: authCookie, Value: "secret"})
+	if response := serve(server, http.MethodPost, "/adrs/1/status", change, forged); response.Code != http.StatusUnauthorized {
+		t.Errorf("POST with a forged sign-in cookie = %d, want 401", response.Code)
+	}
+
+	wrong := serve(server, http.MethodPost, "/login", url.Values{"token": {"guess"}, "csrf": {csrf}, "next": {"/adrs/1"}}, cookies)
+	if wrong.Code != http.StatusUnauthorized || len(wrong.Result().Cookies()) != 0 {
+		t.Errorf("sign-in with a wrong token = %d, cookies %v", wrong.Code, wrong.Result().Cookies())
+	}
+	if status := storedStatus(t, server); status != adr.StatusDraft {
+		t.Fatalf("status changed to %s before signing in", status)
+	}
+
+	login := serve(server, http.MethodPost, "/login", url.Values{"token": {"secret"}, "csrf": {csrf}, "next": {"/adrs/1"}}, cookies)
+	if login.Code != http.StatusSeeOther || login.Header().Get("Location") != "/adrs/1" {
+		t.Fatalf("sign-in = %d to %q, want 303 to /adrs/1", login.Code, login.Header().Get("Location"))
+	}
+	cookies = append(cookies, login.Result().Cookies()...)
+	for _, cookie := range cookies {
+		if cookie.Name == authCookie && strings.Contains(cookie.Value, "secret") {
+			t.Errorf("sign-in cookie holds the token: %s", cookie.Value)
+		}
+	}
+
+	// Signed in, a request from another host is still refused
+	req := httptest.NewRequest(http.MethodPost, "/adrs/1/status", strings.NewReader(change.Encode()))
+	req.Host = "rebind.example.com"
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusForbidden {
+		t.Errorf("cross-host POST = %d, want 403", recorder.Code)
+	}
+
+	if response := serve(server, http.MethodPost, "/adrs/1/status", change, cookies); response.Code != http.StatusSeeOther {
+		t.Fatalf("signed-in POST = %d, want 303\n%s", response.Code, response.Body)
+	}
+	if status := storedStatus(t, server); status != adr.StatusInProgress {
+		t.Errorf("status = %s, want In Progress", status)
+	}
+}
+
+func TestAPIStatusChangeRequiresToken(t *testing.T) {
+	server := newTestServer(t, Options{Host: "localhost", API: true, Token: "secret"})
+
+	tests := []struct {
+		name          string
+		host          string
+		authorization string
+		want          int
+	}{
+		{"no token", "localhost:8080", "", http.StatusUnauthorized},
+		{"wrong token", "localhost:8080", "Bearer guess", http.StatusUnauthorized},
+		{"cross host", "rebind.example.com:8080", "Bearer secret", http.StatusForbidden},
+		{"valid token", "localhost:8080", "Bearer secret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, APIPrefix+"/adrs/1/status", strings.NewReader(`{"status": "In Progress"}`))
+			req.Host = tt.host
+			req.Header.Set("Content-Type", "application/json")
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			recorder := httptest.NewRecorder()
+			server.Handler().ServeHTTP(recorder, req)
+			if recorder.Code != tt.want {
+				t.Fatalf("PUT = %d, want %d\n%s", recorder.Code, tt.want, recorder.Body)
+			}
+
+			want := adr.StatusDraft
+			if tt.want == http.StatusOK {
+				want = adr.StatusInProgress
+			}
+			if status := storedStatus(t, server); status != want {
+				t.Errorf("status = %s, want %s", status, want)
+			}
+		})
+	}
+}
+
+func TestLocalPath(t *testing.T) {
+	tests := map[string]string{
+		"/adrs/1":             "/adrs/1",
+		"":                    "/",
+		"https://example.com": "/",
+		"//example.com":       "/",
+		`/\example.com`:       "/",
+	}
+	for target, want := range tests {
+		if got := localPath(target); got != want {
+			t.Errorf("localPath(%q) = %q, want %q", target, got, want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	// sessionCookie identifies a browser session; the CSRF token of the UI's
+	// forms is derived from it
+	sessionCookie = "drduck_session"
+
+	// authCookie proves a browser signed in with the server's token. It holds
+	// a value derived from the token rather than the token itself.
+	authCookie = "drduck_auth"
+)
+
+// checkHost turns away requests addressed to a host the server does not
+// listen on. A DNS rebinding attack points a domain of its own at the server,
+// so a page on that domain would otherwise pass as same-origin.
+func (s *Server) checkHost(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.allowedHost(r.Host) {
+			http.Error(w, "unknown host: "+r.Host, http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowedHost reports whether a request's Host header names the address the
+// server is bound to or this machine by a loopback name
+func (s *Server) allowedHost(hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	bound := strings.Trim(s.opts.Host, "[]")
+
+	if IsLoopback(host) || strings.EqualFold(host, bound) {
+		return true
+	}
+
+	// Listening on all interfaces, the server is reached by one of the
+	// machine's addresses or its name, which a rebinding domain cannot be
+	if ip := net.ParseIP(bound); bound == "" || (ip != nil && ip.IsUnspecified()) {
+		if net.ParseIP(host) != nil {
+			return true
+		}
+		hostname, err := os.Hostname()
+		return err == nil && strings.EqualFold(host, hostname)
+	}
+	return false
+}
+
+// csrfToken returns the CSRF token of the request's browser session,
+// starting a session if there is none
+func (s *Server) csrfToken(w http.ResponseWriter, r *http.Request) string {
+	if cookie, err := r.Cookie(sessionCookie); err == nil && cookie.Value != "" {
+		return s.sign(cookie.Value)
+	}
+
+	session := make([]byte, 32)
+	rand.Read(session)
+	value := hex.EncodeToString(session)
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    value,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	return s.sign(value)
+}
+
+// validCSRF reports whether a posted form carries its session's CSRF token
+func (s *Server) validCSRF(r *http.Request) bool {
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil || cookie.Value == "" {
+		return false
+	}
+	return hmac.Equal([]byte(r.PostFormValue("csrf")), []byte(s.sign(cookie.Value)))
+}
+
+// sign returns the HMAC of a value under the server's secret
+func (s *Server) sign(value string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// signedIn reports whether a browser may change ADRs: always without a
+// token, and otherwise once it signed in with the token
+func (s *Server) signedIn(r *http.Request) bool {
+	if s.opts.Token == "" {
+		return true
+	}
+	cookie, err := r.Cookie(authCookie)
+	return err == nil && hmac.Equal([]byte(cookie.Value), []byte(s.authValue()))
+}
+
+// authValue is the value of the sign-in cookie, derived from the token
+func (s *Server) authValue() string {
+	mac := hmac.New(sha256.New, []byte(s.opts.Token))
+	mac.Write([]byte("drduck ui sign-in"))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// loginPage is the data of the sign-in form
+type loginPage struct {
+	CSRF  string
+	Next  string
+	Error string
+}
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	data := loginPage{Next: localPath(r.FormValue("next"))}
+
+	if r.Method == http.MethodPost {
+		if !sameOrigin(r) || !s.validCSRF(r) {
+			http.Error(w, "invalid or missing CSRF token, reload the page and try again", http.StatusForbidden)
+			return
+		}
+
+		token := r.PostFormValue("token")
+		if s.opts.Token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) == 1 {
+			http.SetCookie(w, &http.Cookie{
+				Name:     authCookie,
+				Value:    s.authValue(),
+				Path:     "/",
+				HttpOnly: true,
+				SameSite: http.SameSiteStrictMode,
+			})
+			http.Redirect(w, r, data.Next, http.StatusSeeOther)
+			return
+		}
+		data.Error = "Invalid token"
+	}
+
+	data.CSRF = s.csrfToken(w, r)
+	status := http.StatusOK
+	if data.Error != "" {
+		status = http.StatusUnauthorized
+	}
+	s.render(w, status, "login.html", "Sign in", data)
+}
+
+// localPath returns a redirect target if it stays on this server, or "/"
+func localPath(target string) string {
+	if !strings.HasPrefix(target, "/") || strings.HasPrefix(target, "//") || strings.HasPrefix(target, "/\\") {
+		return "/"
+	}
+	return target
+}
@@ -1,6 +1,7 @@
 package claude
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -8,6 +9,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 )
 
 // TokenUsage tracks token consumption for AI requests
@@ -33,11 +35,16 @@ type ClaudeSession struct {
 	Files       []string `json:"files,omitempty"`
 	Context     string   `json:"context,omitempty"`
 	LastMessage string   `json:"last_message,omitempty"`
+	Path        string   `json:"path"`                // Transcript file
+	Branch      string   `json:"branch,omitempty"`    // Git branch the session ran on
+	Intent      []string `json:"intent,omitempty"`    // The user's prompts: the first and the latest ones
+	Decisions   []string `json:"decisions,omitempty"` // Sentences discussing choices and trade-offs
 }
 
 // Integration handles Claude Code CLI integration
 type Integration struct {
-	// Future: Add configuration options
+	Model string // Model passed to the CLI with --model; empty for the CLI default
+	Dir   string // Repository whose sessions are read; the git root of the working directory when empty
 }
 
 // NewIntegration creates a new Claude integration instance
@@ -51,48 +58,39 @@ func (i *Integration) IsAvailable() bool {
 	return err == nil
 }
 
-// GetCurrentSession attempts to get information about the current Claude session
+// GetCurrentSession returns the most recently active Claude Code session of
+// the repository, read from its transcript under ~/.claude/projects
 func (i *Integration) GetCurrentSession() (*ClaudeSession, error) {
-	if !i.IsAvailable() {
-		return nil, fmt.Errorf("claude command not available")
+	transcripts, err := i.findTranscripts()
+	if err != nil {
+		return nil, err
 	}
 
-	// TODO: Implement actual Claude CLI integration
-	// This is a placeholder structure for future implementation
-	// We would need to:
-	// 1. Check for Claude session files/state
-	// 2. Parse Claude's conversation history
-	// 3. Extract file changes and context
-	// 4. Return session information
-
-	return &ClaudeSession{
-		ID:   "placeholder",
-		Name: "Current Session",
-	}, fmt.Errorf("not implemented: Claude CLI integration is planned for future releases")
+	root, err := i.projectDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine project directory: %w", err)
+	}
+	return ParseTranscript(transcripts[0], root)
 }
 
-// ExtractContext extracts relevant context from Claude session for ADR generation
+// ExtractContext extracts relevant context from Claude session for ADR generation:
+// what the user asked for, the files edited and the decisions discussed
 func (i *Integration) ExtractContext(session *ClaudeSession) (string, error) {
-	// TODO: Implement context extraction
-	// This would analyze:
-	// - Recent conversation messages
-	// - Code changes discussed
-	// - Decision points mentioned
-	// - Architectural considerations
+	if session == nil {
+		return "", fmt.Errorf("no Claude session")
+	}
 
-	return "", fmt.Errorf("not implemented: context extraction is planned for future releases")
+	session.Context = formatContext(session)
+	return session.Context, nil
 }
 
 // GetChangedFiles returns files that have been modified in the current session
 func (i *Integration) GetChangedFiles() ([]string, error) {
-	// TODO: Implement file change tracking
-	// This could work by:
-	// 1. Monitoring Claude's file access
-	// 2. Comparing timestamps
-	// 3. Integrating with git to track changes
-	// 4. Parsing Claude's conversation for file mentions
-
-	return nil, fmt.Errorf("not implemented: file change tracking is planned for future releases")
+	session, err := i.GetCurrentSession()
+	if err != nil {
+		return nil, err
+	}
+	return session.Files, nil
 }
 
 // SuggestADRContent generates ADR content suggestions based on Claude session
@@ -123,12 +121,16 @@ func (i *Integration) GetClaudeDirectory() (string, error) {
 	}
 
 	possibleDirs := []string{
+		os.Getenv("CLAUDE_CONFIG_DIR"), // Overrides the default location
 		filepath.Join(homeDir, ".claude"),
 		filepath.Join(homeDir, ".config", "claude"),
 		filepath.Join(homeDir, "Library", "Application Support", "claude"), // macOS
 	}
 
 	for _, dir := range possibleDirs {
+		if dir == "" {
+			continue
+		}
 		if info, err := os.Stat(dir); err == nil && info.IsDir() {
 			return dir, nil
 		}
@@ -137,11 +139,11 @@ func (i *Integration) GetClaudeDirectory() (string, error) {
 	return "", fmt.Errorf("claude directory not found")
 }
 
-// WatchForChanges sets up file system watching for Claude session changes
-func (i *Integration) WatchForChanges() error {
-	// TODO: Implement file system watching
-	// This would monitor Claude's session files and trigger ADR updates
-	return fmt.Errorf("not implemented: change watching is planned for future releases")
+// WatchForChanges polls the repository's latest Claude session every interval
+// and calls onChange with it whenever its transcript changes, until the context
+// is cancelled
+func (i *Integration) WatchForChanges(ctx context.Context, interval time.Duration, onChange func(*ClaudeSession)) error {
+	return i.watch(ctx, interval, onChange)
 }
 
 // AnalyzeChanges sends a prompt to Claude for change analysis
@@ -151,17 +153,16 @@ func (i *Integration) AnalyzeChanges(prompt string) (string, error) {
 	}
 
 	// Use claude command with -p flag for non-interactive analysis
-	cmd := exec.Command("claude", "-p", prompt)
+	cmd := exec.Command("claude", i.args("-p", prompt)...)
 	
 	output, err := cmd.Output()
 	if err != nil {
-		// If direct command fails, provide a fallback analysis
-		return i.fallbackAnalysis(prompt)
+		return "", fmt.Errorf("claude command failed: %w", err)
 	}
 
 	response := strings.TrimSpace(string(output))
 	if response == "" {
-		return i.fallbackAnalysis(prompt)
+		return "", fmt.Errorf("claude returned an empty response")
 	}
 
 	return response, nil
@@ -170,21 +171,11 @@ func (i *Integration) AnalyzeChanges(prompt string) (string, error) {
 // AnalyzeChangesWithTokens sends a prompt to Claude for change analysis and returns token usage  
 func (i *Integration) AnalyzeChangesWithTokens(prompt string) (string, *TokenUsage, error) {
 	if !i.IsAvailable() {
-		response, err := i.fallbackAnalysis(prompt)
-		if err != nil {
-			return "", nil, fmt.Errorf("claude command not available and fallback failed: %w", err)
-		}
-		// Return fallback result with estimated token usage
-		tokenUsage := &TokenUsage{
-			InputTokens:  estimateTokens(prompt),
-			OutputTokens: estimateTokens(response),
-			TotalTokens:  estimateTokens(prompt) + estimateTokens(response),
-		}
-		return response, tokenUsage, nil
+		return "", nil, fmt.Errorf("claude command not available")
 	}
 
 	// Try to use claude command with json output to capture token information
-	cmd := exec.Command("claude", "-p", prompt, "--json")
+	cmd := exec.Command("claude", i.args("-p", prompt, "--json")...)
 	
 	output, err := cmd.Output()
 	if err != nil {
@@ -214,11 +205,7 @@ func (i *Integration) AnalyzeChangesWithTokens(prompt string) (string, *TokenUsa
 		// If JSON parsing fails, treat output as plain text response
 		response := strings.TrimSpace(string(output))
 		if response == "" {
-			fallbackResponse, err := i.fallbackAnalysis(prompt)
-			if err != nil {
-				return "", nil, err
-			}
-			response = fallbackResponse
+			return "", nil, fmt.Errorf("claude returned an empty response")
 		}
 		tokenUsage := &TokenUsage{
 			InputTokens:  estimateTokens(prompt),
@@ -237,6 +224,14 @@ func (i *Integration) AnalyzeChangesWithTokens(prompt string) (string, *TokenUsa
 	return result.Response, tokenUsage, nil
 }
 
+// args appends the configured model to claude CLI arguments
+func (i *Integration) args(args ...string) []string {
+	if i.Model != "" {
+		args = append(args, "--model", i.Model)
+	}
+	return args
+}
+
 // estimateTokens provides a rough estimate of token count for a given text
 // Using approximately 4 characters per token as a rough estimate
 func estimateTokens(text string) int {
@@ -248,106 +243,3 @@ func estimateTokens(text string) int {
 	cleanText = regexp.MustCompile(`\s+`).ReplaceAllString(cleanText, " ")
 	return len(strings.TrimSpace(cleanText)) / 4
 }
-
-// fallbackAnalysis provides basic heuristic analysis when AI is unavailable
-func (i *Integration) fallbackAnalysis(prompt string) (string, error) {
-	// Extract changes from the prompt for basic analysis
-	changes := i.extractChangesFromPrompt(prompt)
-	
-	// Basic heuristics for architectural decisions
-	architecturalKeywords := []string{
-		"database", "api", "framework", "architecture", "design pattern",
-		"authentication", "authorization", "security", "performance",
-		"integration", "microservice", "monolith", "deployment",
-		"technology stack", "library", "dependency", "configuration",
-	}
-	
-	uiKeywords := []string{
-		"css", "style", "ui", "frontend", "button", "color", "theme",
-		"layout", "responsive", "animation", "visual", "design system",
-	}
-	
-	bugfixKeywords := []string{
-		"fix", "bug", "error", "issue", "typo", "hotfix",
-		"patch", "correction", "debug",
-	}
-	
-	changes = strings.ToLower(changes)
-	
-	// Check for bug fixes first (lowest priority)
-	for _, keyword := range bugfixKeywords {
-		if strings.Contains(changes, keyword) {
-			return `**Decision**: No
-**Reasoning**: Changes appear to be bug fixes or patches, which typically don't require architectural documentation
-**Suggested ADR Title**: N/A
-**Key Points**: N/A`, nil
-		}
-	}
-	
-	// Check for UI-only changes
-	uiScore := 0
-	for _, keyword := range uiKeywords {
-		if strings.Contains(changes, keyword) {
-			uiScore++
-		}
-	}
-	
-	// Check for architectural changes
-	archScore := 0
-	for _, keyword := range architecturalKeywords {
-		if strings.Contains(changes, keyword) {
-			archScore++
-		}
-	}
-	
-	// Decision logic
-	if archScore > 0 {
-		return `**Decision**: Yes
-**Reasoning**: Changes contain architectural keywords suggesting significant system decisions that should be documented
-**Suggested ADR Title**: document-recent-architectural-changes
-**Key Points**: 
-- Document the architectural decision and its rationale
-- Consider long-term implications and alternatives
-- Ensure team alignment on the approach`, nil
-	}
-	
-	if uiScore > 2 && archScore == 0 {
-		return `**Decision**: No
-**Reasoning**: Changes appear to be primarily UI/styling updates without architectural implications
-**Suggested ADR Title**: N/A
-**Key Points**: N/A`, nil
-	}
-	
-	// Default to requiring ADR for safety when uncertain
-	return `**Decision**: Yes
-**Reasoning**: Unable to definitively categorize changes - recommending ADR for safety and team communication
-**Suggested ADR Title**: document-recent-changes
-**Key Points**: 
-- Review and document the purpose of these changes
-- Consider if they establish new patterns or approaches
-- Ensure team understanding and alignment`, nil
-}
-
-// extractChangesFromPrompt extracts the actual code changes from the analysis prompt
-func (i *Integration) extractChangesFromPrompt(prompt string) string {
-	lines := strings.Split(prompt, "\n")
-	inChangesSection := false
-	var changes []string
-	
-	for _, line := range lines {
-		if strings.Contains(line, "## Code Changes to Analyze") {
-			inChangesSection = true
-			continue
-		}
-		if inChangesSection {
-			if strings.HasPrefix(line, "##") && !strings.Contains(line, "Code Changes") {
-				break
-			}
-			if !strings.HasPrefix(line, "```") {
-				changes = append(changes, line)
-			}
-		}
-	}
-	
-	return strings.Join(changes, "\n")
-}
\ No newline at end of file
@@ -0,0 +1,351 @@
+package claude
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SilverFlin/DrDuck/internal/transcript"
+)
+
+// editTools are the Claude Code tools that write files, with the input field
+// holding the path
+var editTools = map[string]string{
+	"Edit":         "file_path",
+	"MultiEdit":    "file_path",
+	"Write":        "file_path",
+	"NotebookEdit": "notebook_path",
+}
+
+var (
+	nonAlphanumericPattern = regexp.MustCompile(`[^a-zA-Z0-9]`)
+	systemReminderPattern  = regexp.MustCompile(`(?s)<system-reminder>.*?</system-reminder>`)
+)
+
+// transcriptEntry is one line of a Claude Code session transcript
+type transcriptEntry struct {
+	Type             string `json:"type"`
+	SessionID        string `json:"sessionId"`
+	Cwd              string `json:"cwd"`
+	GitBranch        string `json:"gitBranch"`
+	Timestamp        string `json:"timestamp"`
+	IsSidechain      bool   `json:"isSidechain"`
+	IsMeta           bool   `json:"isMeta"`
+	IsCompactSummary bool   `json:"isCompactSummary"`
+	Summary          string `json:"summary"`
+	Message          *struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// contentBlock is a block of a message's content
+type contentBlock struct {
+	Type  string                     `json:"type"`
+	Text  string                     `json:"text"`
+	Name  string                     `json:"name"`
+	Input map[string]json.RawMessage `json:"input"`
+}
+
+// EncodeProjectPath returns the name Claude Code gives a project's directory
+// under ~/.claude/projects: the path with every non-alphanumeric character
+// replaced by a dash
+func EncodeProjectPath(dir string) string {
+	return nonAlphanumericPattern.ReplaceAllString(dir, "-")
+}
+
+// projectDir returns the repository the integration reads sessions for
+func (i *Integration) projectDir() (string, error) {
+	if i.Dir != "" {
+		return filepath.Abs(i.Dir)
+	}
+	if output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output(); err == nil {
+		return filepath.Abs(strings.TrimSpace(string(output)))
+	}
+	return os.Getwd()
+}
+
+// findTranscripts returns the session transcripts of the repository and its
+// subdirectories, most recently updated first
+func (i *Integration) findTranscripts() ([]string, error) {
+	root, err := i.projectDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine project directory: %w", err)
+	}
+	claudeDir, err := i.GetClaudeDirectory()
+	if err != nil {
+		return nil, err
+	}
+	projectsDir := filepath.Join(claudeDir, "projects")
+	entries, err := os.ReadDir(projectsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Claude projects directory: %w", err)
+	}
+
+	// Claude Code may have seen the repository through a symlink or its real path
+	roots := []string{root}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil && resolved != root {
+		roots = append(roots, resolved)
+	}
+
+	type transcript struct {
+		path    string
+		modTime time.Time
+	}
+	var transcripts []transcript
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		exact, nested := false, false
+		for _, r := range roots {
+			encoded := EncodeProjectPath(r)
+			exact = exact || entry.Name() == encoded
+			nested = nested || strings.HasPrefix(entry.Name(), encoded+"-")
+		}
+		if !exact && !nested {
+			continue
+		}
+
+		dir := filepath.Join(projectsDir, entry.Name())
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			if file.IsDir() || !strings.HasSuffix(file.Name(), ".jsonl") || strings.HasPrefix(file.Name(), "agent-") {
+				continue
+			}
+			path := filepath.Join(dir, file.Name())
+			// The encoding is ambiguous: /repo-x and /repo/x share a prefix
+			if !exact && !transcriptWithin(path, roots) {
+				continue
+			}
+			info, err := file.Info()
+			if err != nil {
+				continue
+			}
+			transcripts = append(transcripts, transcript{path: path, modTime: info.ModTime()})
+		}
+	}
+	if len(transcripts) == 0 {
+		return nil, fmt.Errorf("no Claude Code sessions found for %s", root)
+	}
+
+	sort.Slice(transcripts, func(a, b int) bool {
+		return transcripts[a].modTime.After(transcripts[b].modTime)
+	})
+	paths := make([]string, len(transcripts))
+	for n, t := range transcripts {
+		paths[n] = t.path
+	}
+	return paths, nil
+}
+
+// transcriptWithin reports whether a transcript was recorded in one of the
+// roots or below them, according to the working directory it records
+func transcriptWithin(path string, roots []string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 32*1024*1024)
+	for scanner.Scan() {
+		var entry transcriptEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil || entry.Cwd == "" {
+			continue
+		}
+		for _, root := range roots {
+			if entry.Cwd == root || strings.HasPrefix(entry.Cwd, root+string(filepath.Separator)) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// ParseTranscript reads a Claude Code session transcript. Edited files are
+// reported relative to projectDir; files outside it are left out.
+func ParseTranscript(path, projectDir string) (*ClaudeSession, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer file.Close()
+
+	session := &ClaudeSession{
+		ID:   strings.TrimSuffix(filepath.Base(path), ".jsonl"),
+		Path: path,
+	}
+	var prompts []string
+	seenFiles := map[string]bool{}
+	seenDecisions := map[string]bool{}
+
+	addDecisions := func(text string) {
+		for _, sentence := range transcript.Sentences(text) {
+			if !transcript.DecisionPattern.MatchString(sentence) || seenDecisions[sentence] {
+				continue
+			}
+			seenDecisions[sentence] = true
+			session.Decisions = append(session.Decisions, transcript.Truncate(sentence, transcript.MaxDecisionLength))
+		}
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 32*1024*1024)
+	for scanner.Scan() {
+		var entry transcriptEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue // Tolerate lines from newer transcript formats
+		}
+
+		if entry.Type == "summary" && entry.Summary != "" {
+			session.Name = entry.Summary
+			continue
+		}
+		if entry.IsSidechain || entry.Message == nil || (entry.Type != "user" && entry.Type != "assistant") {
+			continue
+		}
+
+		if entry.SessionID != "" {
+			session.ID = entry.SessionID
+		}
+		if entry.GitBranch != "" {
+			session.Branch = entry.GitBranch
+		}
+		if entry.Timestamp != "" {
+			if session.CreatedAt == "" {
+				session.CreatedAt = entry.Timestamp
+			}
+			session.UpdatedAt = entry.Timestamp
+		}
+
+		text, blocks := messageContent(entry.Message.Content)
+		switch entry.Type {
+		case "user":
+			// Tool results, injected notes and compaction summaries are not the user speaking
+			if entry.IsMeta || entry.IsCompactSummary {
+				continue
+			}
+			prompt := strings.TrimSpace(systemReminderPattern.ReplaceAllString(text, ""))
+			if prompt == "" || strings.HasPrefix(prompt, "<") {
+				continue
+			}
+			session.Messages++
+			prompts = append(prompts, prompt)
+			session.LastMessage = prompt
+			addDecisions(prompt)
+
+		case "assistant":
+			session.Messages++
+			addDecisions(text)
+			for _, block := range blocks {
+				field, ok := editTools[block.Name]
+				if block.Type != "tool_use" || !ok {
+					continue
+				}
+				var filePath string
+				if json.Unmarshal(block.Input[field], &filePath) != nil || filePath == "" {
+					continue
+				}
+				if relative, ok := transcript.RelativeTo(projectDir, filePath); ok && !seenFiles[relative] {
+					seenFiles[relative] = true
+					session.Files = append(session.Files, relative)
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	if session.Messages == 0 {
+		return nil, fmt.Errorf("session %s has no messages", session.ID)
+	}
+
+	session.Intent = transcript.Intent(prompts)
+	if session.Name == "" {
+		session.Name = transcript.Truncate(strings.SplitN(prompts[0], "\n", 2)[0], 80)
+	}
+	if len(session.Decisions) > transcript.MaxDecisions {
+		session.Decisions = session.Decisions[len(session.Decisions)-transcript.MaxDecisions:]
+	}
+	sort.Strings(session.Files)
+
+	return session, nil
+}
+
+// messageContent returns a message's text and its content blocks. Content is
+// either a plain string or a list of blocks.
+func messageContent(raw json.RawMessage) (string, []contentBlock) {
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return text, nil
+	}
+
+	var blocks []contentBlock
+	if json.Unmarshal(raw, &blocks) != nil {
+		return "", nil
+	}
+	var parts []string
+	for _, block := range blocks {
+		if block.Type == "text" {
+			parts = append(parts, block.Text)
+		}
+	}
+	return strings.Join(parts, "\n"), blocks
+}
+
+// formatContext renders a session as context for an ADR prompt
+func formatContext(session *ClaudeSession) string {
+	heading := fmt.Sprintf("Claude Code session %q (%d messages", session.Name, session.Messages)
+	if session.Branch != "" {
+		heading += ", branch " + session.Branch
+	}
+	if session.UpdatedAt != "" {
+		heading += ", last active " + session.UpdatedAt
+	}
+	return transcript.FormatContext(heading+")", session.Intent, session.Files, session.Decisions)
+}
+
+// watch polls the newest transcript and calls onChange with the parsed session
+// whenever it changes, until the context is cancelled
+func (i *Integration) watch(ctx context.Context, interval time.Duration, onChange func(*ClaudeSession)) error {
+	root, err := i.projectDir()
+	if err != nil {
+		return fmt.Errorf("failed to determine project directory: %w", err)
+	}
+
+	var lastPath string
+	var lastSize int64
+	var lastModTime time.Time
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if transcripts, err := i.findTranscripts(); err == nil {
+			if info, err := os.Stat(transcripts[0]); err == nil &&
+				(transcripts[0] != lastPath || info.Size() != lastSize || !info.ModTime().Equal(lastModTime)) {
+				lastPath, lastSize, lastModTime = transcripts[0], info.Size(), info.ModTime()
+				if session, err := ParseTranscript(lastPath, root); err == nil {
+					onChange(session)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// useTranscript installs a testdata transcript as the only Claude Code session
+// of the /repo project, recorded in the fixtures
+func useTranscript(t *testing.T, name string) *Integration {
+	t.Helper()
+
+	claudeDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", claudeDir)
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	projectDir := filepath.Join(claudeDir, "projects", EncodeProjectPath("/repo"))
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, name), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return &Integration{Dir: "/repo"}
+}
+
+func TestTranscripts(t *testing.T) {
+	tests := []struct {
+		transcript  string
+		wantFiles   []string
+		wantContext string
+		wantErr     bool
+	}{
+		{
+			transcript: "session.jsonl",
+			wantFiles:  []string{"internal/cache/storage.go", "internal/cache/types.go"},
+			wantContext: `Claude Code session "Cache analysis results" (4 messages, branch feature/cache, last active 2025-06-01T10:05:09Z)
+
+What the developer asked for:
+- Cache the analysis results so the hooks stay fast.
+- Also expire entries after a week.
+
+Files edited in the session:
+- internal/cache/storage.go
+- internal/cache/types.go
+
+Decision discussion:
+- I went with a JSON file instead of SQLite.`,
+		},
+		{
+			transcript: "untitled.jsonl",
+			wantFiles:  []string{"notebooks/load.ipynb"},
+			wantContext: `Claude Code session "Rename the config loader" (2 messages, last active 2025-06-02T08:00:04Z)
+
+What the developer asked for:
+- Rename the config loader and keep the old name as an alias.
+
+Files edited in the session:
+- notebooks/load.ipynb`,
+		},
+		{
+			transcript: "empty.jsonl",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.transcript, func(t *testing.T) {
+			integration := useTranscript(t, tt.transcript)
+
+			files, err := integration.GetChangedFiles()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error for a session without messages")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetChangedFiles: %v", err)
+			}
+			if !reflect.DeepEqual(files, tt.wantFiles) {
+				t.Errorf("GetChangedFiles() = %q, want %q", files, tt.wantFiles)
+			}
+
+			session, err := integration.GetCurrentSession()
+			if err != nil {
+				t.Fatalf("GetCurrentSession: %v", err)
+			}
+			context, err := integration.ExtractContext(session)
+			if err != nil {
+				t.Fatalf("ExtractContext: %v", err)
+			}
+			if context != tt.wantContext {
+				t.Errorf("ExtractContext() =\n%s\nwant\n%s", context, tt.wantContext)
+			}
+		})
+	}
+}
+
+func TestExtractContextWithoutSession(t *testing.T) {
+	if _, err := NewIntegration().ExtractContext(nil); err == nil {
+		t.Error("expected an error without a session")
+	}
+}
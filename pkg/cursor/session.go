@@ -0,0 +1,419 @@
+package cursor
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SilverFlin/DrDuck/internal/transcript"
+	_ "modernc.org/sqlite" // Registers the pure Go "sqlite" driver
+)
+
+// Keys Cursor stores its AI conversations under. Chats and the composer index
+// live in the workspace database; composer conversations in newer versions
+// live in the global one, with their messages stored separately.
+const (
+	itemTable       = "ItemTable"
+	diskKVTable     = "cursorDiskKV"
+	chatDataKey     = "workbench.panel.aichat.view.aichat.chatdata"
+	composerDataKey = "composer.composerData"
+)
+
+// editTools are the composer agent tools that write files, with the argument
+// holding the path
+var editTools = map[string]string{
+	"edit_file":      "target_file",
+	"search_replace": "file_path",
+	"write":          "file_path",
+}
+
+var driveLetterPattern = regexp.MustCompile(`^/[a-zA-Z]:`)
+
+// workspace is a folder Cursor has opened, with its state database
+type workspace struct {
+	folder   string
+	database string
+}
+
+// bubble is a message of a chat or composer conversation
+type bubble struct {
+	Type       json.RawMessage `json:"type"` // "user" or "ai" in chats, 1 or 2 in composers
+	BubbleID   string          `json:"bubbleId"`
+	Text       string          `json:"text"`
+	RawText    string          `json:"rawText"`
+	CodeBlocks []struct {
+		URI *fileURI `json:"uri"`
+	} `json:"codeBlocks"`
+	ToolFormerData *struct {
+		Name    string `json:"name"`
+		RawArgs string `json:"rawArgs"`
+	} `json:"toolFormerData"`
+}
+
+// fileURI is a serialized VS Code URI
+type fileURI struct {
+	FsPath string `json:"fsPath"`
+	Path   string `json:"path"`
+}
+
+// chatData is the chat panel's state
+type chatData struct {
+	Tabs []struct {
+		TabID        string   `json:"tabId"`
+		ChatTitle    string   `json:"chatTitle"`
+		LastSendTime int64    `json:"lastSendTime"`
+		Bubbles      []bubble `json:"bubbles"`
+	} `json:"tabs"`
+}
+
+// composerData is a composer conversation, or its entry in the workspace's
+// composer index
+type composerData struct {
+	ComposerID                  string                     `json:"composerId"`
+	Name                        string                     `json:"name"`
+	CreatedAt                   int64                      `json:"createdAt"`
+	LastUpdatedAt               int64                      `json:"lastUpdatedAt"`
+	Conversation                []bubble                   `json:"conversation"`
+	FullConversationHeadersOnly []bubble                   `json:"fullConversationHeadersOnly"`
+	CodeBlockData               map[string]json.RawMessage `json:"codeBlockData"`
+}
+
+// projectDir returns the repository the integration reads sessions for
+func (i *Integration) projectDir() (string, error) {
+	if i.Dir != "" {
+		return filepath.Abs(i.Dir)
+	}
+	if output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output(); err == nil {
+		return filepath.Abs(strings.TrimSpace(string(output)))
+	}
+	return os.Getwd()
+}
+
+// findWorkspaces returns the workspaces opened on the repository or one of its
+// subdirectories
+func (i *Integration) findWorkspaces(root string) ([]workspace, error) {
+	dataDir, err := i.GetCursorDirectory()
+	if err != nil {
+		return nil, err
+	}
+	storageDir := filepath.Join(dataDir, "User", "workspaceStorage")
+	entries, err := os.ReadDir(storageDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Cursor workspace storage: %w", err)
+	}
+
+	// Cursor may have opened the repository through a symlink or its real path
+	roots := []string{root}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil && resolved != root {
+		roots = append(roots, resolved)
+	}
+
+	var workspaces []workspace
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(storageDir, entry.Name())
+		data, err := os.ReadFile(filepath.Join(dir, "workspace.json"))
+		if err != nil {
+			continue
+		}
+		var info struct {
+			Folder string `json:"folder"`
+		}
+		if json.Unmarshal(data, &info) != nil || info.Folder == "" {
+			continue // Multi-root workspaces have no single folder
+		}
+		folder, ok := uriToPath(info.Folder)
+		if !ok {
+			continue
+		}
+		for _, r := range roots {
+			if _, within := transcript.RelativeTo(r, folder); within {
+				workspaces = append(workspaces, workspace{folder: folder, database: filepath.Join(dir, "state.vscdb")})
+				break
+			}
+		}
+	}
+	if len(workspaces) == 0 {
+		return nil, fmt.Errorf("no Cursor workspace found for %s", root)
+	}
+	return workspaces, nil
+}
+
+// openDatabase opens a state database read-only. Cursor keeps it open while it
+// runs; as immutable, SQLite neither locks it nor reads or creates its journal.
+func openDatabase(path string) (*sql.DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open Cursor database: %w", err)
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	// Windows paths get a slash before the drive letter, as in file:///C:/...
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	db, err := sql.Open("sqlite", "file://"+(&url.URL{Path: path}).EscapedPath()+"?mode=ro&immutable=1")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Cursor database: %w", err)
+	}
+	return db, nil
+}
+
+// get returns the value stored under key in one of the key/value tables of a
+// state database. Databases of older versions lack some of the tables.
+func get(db *sql.DB, table, key string) ([]byte, bool, error) {
+	var tables int
+	if err := db.QueryRow("SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&tables); err != nil {
+		return nil, false, err
+	}
+	if tables == 0 {
+		return nil, false, nil
+	}
+
+	var value []byte
+	err := db.QueryRow(`SELECT value FROM "`+table+`" WHERE key = ?`, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// readSessions returns the chats and composers of a workspace, without their
+// conversations parsed
+func readSessions(ws workspace) ([]*CursorSession, error) {
+	db, err := openDatabase(ws.database)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	var sessions []*CursorSession
+	if value, found, err := get(db, itemTable, chatDataKey); err != nil {
+		return nil, fmt.Errorf("failed to read Cursor chats: %w", err)
+	} else if found {
+		var chats chatData
+		if err := json.Unmarshal(value, &chats); err != nil {
+			return nil, fmt.Errorf("failed to parse Cursor chats: %w", err)
+		}
+		for _, tab := range chats.Tabs {
+			session := &CursorSession{ID: tab.TabID, ProjectID: ws.folder, Name: tab.ChatTitle, Kind: "chat",
+				lastActive: tab.LastSendTime, bubbles: tab.Bubbles}
+			sessions = append(sessions, session)
+		}
+	}
+
+	if value, found, err := get(db, itemTable, composerDataKey); err != nil {
+		return nil, fmt.Errorf("failed to read Cursor composers: %w", err)
+	} else if found {
+		var index struct {
+			AllComposers []composerData `json:"allComposers"`
+		}
+		if err := json.Unmarshal(value, &index); err != nil {
+			return nil, fmt.Errorf("failed to parse Cursor composers: %w", err)
+		}
+		for n := range index.AllComposers {
+			composer := &index.AllComposers[n]
+			session := &CursorSession{ID: composer.ComposerID, ProjectID: ws.folder, Name: composer.Name, Kind: "composer",
+				lastActive: composer.LastUpdatedAt, composer: composer}
+			if session.lastActive == 0 {
+				session.lastActive = composer.CreatedAt
+			}
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+// loadComposer fills in a composer's conversation from the global database
+// when the workspace index only lists it
+func (i *Integration) loadComposer(composer *composerData) error {
+	if len(composer.Conversation) > 0 {
+		return nil
+	}
+	dataDir, err := i.GetCursorDirectory()
+	if err != nil {
+		return err
+	}
+	db, err := openDatabase(filepath.Join(dataDir, "User", "globalStorage", "state.vscdb"))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	value, found, err := get(db, diskKVTable, "composerData:"+composer.ComposerID)
+	if err != nil {
+		return fmt.Errorf("failed to read Cursor composer: %w", err)
+	}
+	if !found {
+		return fmt.Errorf("composer %s not found in Cursor storage", composer.ComposerID)
+	}
+	var full composerData
+	if err := json.Unmarshal(value, &full); err != nil {
+		return fmt.Errorf("failed to parse Cursor composer: %w", err)
+	}
+
+	// Newer versions keep only headers in the conversation and store each message on its own
+	if len(full.Conversation) == 0 {
+		for _, header := range full.FullConversationHeadersOnly {
+			value, found, err := get(db, diskKVTable, "bubbleId:"+composer.ComposerID+":"+header.BubbleID)
+			if err != nil {
+				return fmt.Errorf("failed to read Cursor message: %w", err)
+			}
+			var message bubble
+			if found && json.Unmarshal(value, &message) == nil {
+				full.Conversation = append(full.Conversation, message)
+			}
+		}
+	}
+
+	composer.Conversation = full.Conversation
+	if composer.CodeBlockData == nil {
+		composer.CodeBlockData = full.CodeBlockData
+	}
+	return nil
+}
+
+// parseConversation collects what the developer asked for, the files the
+// composer edited and the decisions discussed. Edited files are reported
+// relative to projectDir; files outside it are left out.
+func parseConversation(session *CursorSession, bubbles []bubble, codeBlockData map[string]json.RawMessage, projectDir string) {
+	var prompts []string
+	seenFiles := map[string]bool{}
+	seenDecisions := map[string]bool{}
+
+	addFile := func(path string) {
+		if relative, ok := transcript.RelativeTo(projectDir, path); ok && !seenFiles[relative] {
+			seenFiles[relative] = true
+			session.Files = append(session.Files, relative)
+		}
+	}
+	addDecisions := func(text string) {
+		for _, sentence := range transcript.Sentences(text) {
+			if !transcript.DecisionPattern.MatchString(sentence) || seenDecisions[sentence] {
+				continue
+			}
+			seenDecisions[sentence] = true
+			session.Decisions = append(session.Decisions, transcript.Truncate(sentence, transcript.MaxDecisionLength))
+		}
+	}
+
+	for _, message := range bubbles {
+		text := strings.TrimSpace(message.Text)
+		if text == "" {
+			text = strings.TrimSpace(message.RawText)
+		}
+
+		switch message.role() {
+		case "user":
+			if text == "" {
+				continue
+			}
+			session.Messages++
+			prompts = append(prompts, text)
+			session.LastMessage = text
+			addDecisions(text)
+
+		case "ai":
+			session.Messages++
+			addDecisions(text)
+			// Chat answers only suggest code; composers apply theirs
+			if session.Kind != "composer" {
+				continue
+			}
+			for _, block := range message.CodeBlocks {
+				if block.URI != nil {
+					addFile(block.URI.path())
+				}
+			}
+			if tool := message.ToolFormerData; tool != nil {
+				field, ok := editTools[tool.Name]
+				var args map[string]json.RawMessage
+				if !ok || json.Unmarshal([]byte(tool.RawArgs), &args) != nil {
+					continue
+				}
+				var path string
+				if json.Unmarshal(args[field], &path) == nil && path != "" {
+					addFile(path)
+				}
+			}
+		}
+	}
+	for uri := range codeBlockData {
+		if path, ok := uriToPath(uri); ok {
+			addFile(path)
+		}
+	}
+
+	session.Intent = transcript.Intent(prompts)
+	if session.Name == "" && len(prompts) > 0 {
+		session.Name = transcript.Truncate(strings.SplitN(prompts[0], "\n", 2)[0], 80)
+	}
+	if len(session.Decisions) > transcript.MaxDecisions {
+		session.Decisions = session.Decisions[len(session.Decisions)-transcript.MaxDecisions:]
+	}
+	sort.Strings(session.Files)
+}
+
+// role returns "user" or "ai"
+func (b bubble) role() string {
+	switch strings.Trim(string(b.Type), `"`) {
+	case "user", "1":
+		return "user"
+	case "ai", "2":
+		return "ai"
+	}
+	return ""
+}
+
+// path returns the file system path of a serialized URI
+func (u *fileURI) path() string {
+	if u.FsPath != "" {
+		return u.FsPath
+	}
+	path, _ := uriToPath("file://" + u.Path)
+	return path
+}
+
+// uriToPath converts a file URI to a path. Windows URIs look like
+// file:///c%3A/Users/...
+func uriToPath(uri string) (string, bool) {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "file" {
+		return "", false
+	}
+	path := parsed.Path
+	if driveLetterPattern.MatchString(path) {
+		path = path[1:]
+	}
+	return filepath.FromSlash(path), true
+}
+
+// formatMillis formats a Unix time in milliseconds as RFC 3339
+func formatMillis(millis int64) string {
+	return time.UnixMilli(millis).UTC().Format(time.RFC3339)
+}
+
+// formatContext renders a session as context for an ADR prompt
+func formatContext(session *CursorSession) string {
+	heading := fmt.Sprintf("Cursor %s %q (%d messages", session.Kind, session.Name, session.Messages)
+	if session.UpdatedAt != "" {
+		heading += ", last active " + session.UpdatedAt
+	}
+	return transcript.FormatContext(heading+")", session.Intent, session.Files, session.Decisions)
+}
@@ -0,0 +1,279 @@
+package cursor
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// useFixtures reads sessions of a directory from the Cursor data directory
+// made by testdata/generate.sh
+func useFixtures(dir string) *Integration {
+	return &Integration{DataDir: filepath.Join("testdata", "Cursor"), Dir: dir}
+}
+
+// repoWorkspace is the workspace of /repo in the fixtures
+var repoWorkspace = workspace{
+	folder:   filepath.FromSlash("/repo"),
+	database: filepath.Join("testdata", "Cursor", "User", "workspaceStorage", "1a2b3c-repo", "state.vscdb"),
+}
+
+func TestFindWorkspaces(t *testing.T) {
+	tests := []struct {
+		root    string
+		want    []string
+		wantErr bool
+	}{
+		{root: "/repo", want: []string{"/repo", "/repo/service"}},
+		{root: "/repo/service", want: []string{"/repo/service"}},
+		{root: "/other", want: []string{"/other"}},
+		{root: "/rep", wantErr: true},
+		{root: "/home/dev", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.root, func(t *testing.T) {
+			workspaces, err := useFixtures(tt.root).findWorkspaces(filepath.FromSlash(tt.root))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", workspaces)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("findWorkspaces: %v", err)
+			}
+			var folders []string
+			for _, ws := range workspaces {
+				folders = append(folders, filepath.ToSlash(ws.folder))
+				if filepath.Base(ws.database) != "state.vscdb" {
+					t.Errorf("database of %s = %s", ws.folder, ws.database)
+				}
+			}
+			if !reflect.DeepEqual(folders, tt.want) {
+				t.Errorf("folders = %q, want %q", folders, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadSessions(t *testing.T) {
+	sessions, err := readSessions(repoWorkspace)
+	if err != nil {
+		t.Fatalf("readSessions: %v", err)
+	}
+
+	type summary struct {
+		ID, Name, Kind string
+		LastActive     int64
+		Bubbles        int
+		Listed         bool // A composer known only from the index
+	}
+	var got []summary
+	for _, session := range sessions {
+		s := summary{ID: session.ID, Name: session.Name, Kind: session.Kind, LastActive: session.lastActive, Bubbles: len(session.bubbles)}
+		if session.composer != nil {
+			s.Bubbles = len(session.composer.Conversation)
+			s.Listed = s.Bubbles == 0
+		}
+		if session.ProjectID != repoWorkspace.folder {
+			t.Errorf("%s belongs to %s", session.ID, session.ProjectID)
+		}
+		got = append(got, s)
+	}
+	want := []summary{
+		{ID: "chat-1", Name: "Caching options", Kind: "chat", LastActive: 1748772000000, Bubbles: 2},
+		{ID: "5e1f", Name: "Add a JSON flag", Kind: "composer", LastActive: 1748700500000, Bubbles: 2},
+		{ID: "c0ffee", Name: "Expire cache entries", Kind: "composer", LastActive: 1748776000000, Listed: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sessions = %+v, want %+v", got, want)
+	}
+
+	if _, err := readSessions(workspace{folder: "/other", database: filepath.Join("testdata", "missing.vscdb")}); err == nil {
+		t.Error("expected an error for a workspace without a database")
+	}
+}
+
+func TestLoadComposer(t *testing.T) {
+	integration := useFixtures("/repo")
+
+	composer := &composerData{ComposerID: "c0ffee"}
+	if err := integration.loadComposer(composer); err != nil {
+		t.Fatalf("loadComposer: %v", err)
+	}
+	// The message without a row of its own is left out
+	var ids []string
+	for _, message := range composer.Conversation {
+		ids = append(ids, message.BubbleID)
+	}
+	if want := []string{"b1", "b2", "b3", "b4"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("conversation = %q, want %q", ids, want)
+	}
+	if len(composer.CodeBlockData) != 2 {
+		t.Errorf("code block data = %v", composer.CodeBlockData)
+	}
+
+	// Conversations already in the index are kept without reading the global database
+	inline := &composerData{ComposerID: "5e1f", Conversation: []bubble{{Text: "kept"}}}
+	if err := (&Integration{DataDir: t.TempDir()}).loadComposer(inline); err != nil || inline.Conversation[0].Text != "kept" {
+		t.Errorf("loadComposer of an inline conversation = %v, %+v", err, inline.Conversation)
+	}
+
+	if err := integration.loadComposer(&composerData{ComposerID: "unknown"}); err == nil {
+		t.Error("expected an error for a composer missing from the global database")
+	}
+}
+
+func TestParseConversation(t *testing.T) {
+	var bubbles []bubble
+	if err := json.Unmarshal([]byte(`[
+		{"type": 1, "text": "Move the cache to SQLite."},
+		{"type": 2, "text": "We chose SQLite over Bolt because of the tooling.", "codeBlocks": [{"uri": {"fsPath": "/repo/internal/cache/sqlite.go"}}, {"uri": {"path": "/other/notes.md"}}]},
+		{"type": 2, "toolFormerData": {"name": "read_file", "rawArgs": "{\"target_file\": \"/repo/README.md\"}"}},
+		{"type": 2, "toolFormerData": {"name": "search_replace", "rawArgs": "{\"file_path\": \"/repo/go.mod\"}"}},
+		{"type": 1, "text": "  "},
+		{"type": 1, "text": "Second prompt."},
+		{"type": 1, "text": "Third prompt."},
+		{"type": 1, "text": "Fourth prompt."},
+		{"type": 1, "text": "Fifth prompt."},
+		{"type": 1, "text": "Sixth prompt, we went with SQLite."},
+		{"type": 3, "text": "Unknown roles are skipped."}
+	]`), &bubbles); err != nil {
+		t.Fatal(err)
+	}
+	codeBlockData := map[string]json.RawMessage{"file:///repo/internal/cache/types.go": nil, "vscode-notebook-cell:/repo/a.ipynb": nil}
+
+	tests := []struct {
+		kind          string
+		wantFiles     []string
+		wantDecisions []string
+	}{
+		{
+			kind:          "composer",
+			wantFiles:     []string{"go.mod", "internal/cache/sqlite.go", "internal/cache/types.go"},
+			wantDecisions: []string{"We chose SQLite over Bolt because of the tooling.", "Sixth prompt, we went with SQLite."},
+		},
+		{
+			// Chat answers only suggest code
+			kind:          "chat",
+			wantFiles:     []string{"internal/cache/types.go"},
+			wantDecisions: []string{"We chose SQLite over Bolt because of the tooling.", "Sixth prompt, we went with SQLite."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			session := &CursorSession{Kind: tt.kind}
+			parseConversation(session, bubbles, codeBlockData, filepath.FromSlash("/repo"))
+
+			if session.Messages != 9 || session.Name != "Move the cache to SQLite." || session.LastMessage != "Sixth prompt, we went with SQLite." {
+				t.Errorf("messages %d, name %q, last message %q", session.Messages, session.Name, session.LastMessage)
+			}
+			// The first prompt and the latest ones
+			wantIntent := []string{"Move the cache to SQLite.", "Third prompt.", "Fourth prompt.", "Fifth prompt.", "Sixth prompt, we went with SQLite."}
+			if !reflect.DeepEqual(session.Intent, wantIntent) {
+				t.Errorf("intent = %q, want %q", session.Intent, wantIntent)
+			}
+			if !reflect.DeepEqual(session.Files, tt.wantFiles) {
+				t.Errorf("files = %q, want %q", session.Files, tt.wantFiles)
+			}
+			if !reflect.DeepEqual(session.Decisions, tt.wantDecisions) {
+				t.Errorf("decisions = %q, want %q", session.Decisions, tt.wantDecisions)
+			}
+		})
+	}
+}
+
+func TestSessions(t *testing.T) {
+	integration := useFixtures("/repo")
+
+	files, err := integration.GetChangedFiles()
+	if err != nil {
+		t.Fatalf("GetChangedFiles: %v", err)
+	}
+	if want := []string{"internal/cache/storage.go", "internal/cache/types.go", "internal/config/config.go"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("GetChangedFiles() = %q, want %q", files, want)
+	}
+
+	session, err := integration.GetCurrentSession()
+	if err != nil {
+		t.Fatalf("GetCurrentSession: %v", err)
+	}
+	if session.ID != "c0ffee" || session.Kind != "composer" {
+		t.Fatalf("current session = %s %s, want composer c0ffee", session.Kind, session.ID)
+	}
+	context, err := integration.ExtractContext(session)
+	if err != nil {
+		t.Fatalf("ExtractContext: %v", err)
+	}
+	want := `Cursor composer "Expire cache entries" (4 messages, last active 2025-06-01T11:06:40Z)
+
+What the developer asked for:
+- Expire cache entries after a week.
+- Make the week configurable.
+
+Files edited in the session:
+- internal/cache/storage.go
+- internal/cache/types.go
+- internal/config/config.go
+
+Decision discussion:
+- I went with a TTL per entry instead of a sweep on startup.`
+	if context != want {
+		t.Errorf("ExtractContext() =\n%s\nwant\n%s", context, want)
+	}
+
+	// Sessions of a subdirectory's workspace are read on their own
+	session, err = useFixtures("/repo/service").GetCurrentSession()
+	if err != nil {
+		t.Fatalf("GetCurrentSession of /repo/service: %v", err)
+	}
+	if session.ID != "chat-2" || session.Messages != 2 || session.UpdatedAt != "2025-05-30T10:13:20Z" || len(session.Files) != 0 {
+		t.Errorf("session = %+v", session)
+	}
+
+	if _, err := useFixtures("/other").GetCurrentSession(); err == nil || !strings.Contains(err.Error(), "no Cursor conversations") {
+		t.Errorf("GetCurrentSession of a workspace without a database = %v", err)
+	}
+	if _, err := integration.ExtractContext(nil); err == nil {
+		t.Error("expected an error without a session")
+	}
+}
+
+func TestGet(t *testing.T) {
+	db, err := openDatabase(repoWorkspace.database)
+	if err != nil {
+		t.Fatalf("openDatabase: %v", err)
+	}
+	defer db.Close()
+
+	tests := []struct {
+		table, key string
+		wantFound  bool
+	}{
+		{table: itemTable, key: composerDataKey, wantFound: true},
+		{table: itemTable, key: "missing"},
+		{table: diskKVTable, key: "composerData:c0ffee"}, // Only the global database has the table
+	}
+	for _, tt := range tests {
+		value, found, err := get(db, tt.table, tt.key)
+		if err != nil {
+			t.Fatalf("get(%s, %s): %v", tt.table, tt.key, err)
+		}
+		if found != tt.wantFound || (found && !json.Valid(value)) {
+			t.Errorf("get(%s, %s) = %.40q, %v; want found %v", tt.table, tt.key, value, found, tt.wantFound)
+		}
+	}
+
+	other, err := openDatabase(filepath.Join("testdata", "generate.sh"))
+	if err == nil {
+		defer other.Close()
+		_, _, err = get(other, itemTable, chatDataKey)
+	}
+	if err == nil {
+		t.Error("expected an error for a file that is not a SQLite database")
+	}
+}
@@ -0,0 +1,37 @@
+#!/bin/sh
+# Regenerates the state databases of the Cursor data directory fixture with the
+# sqlite3 CLI, from the JSON values in values/.
+#
+#   User/workspaceStorage/1a2b3c-repo     /repo: a chat and two composers, the
+#                                         newest listed only in the index
+#   User/workspaceStorage/4d5e6f-service  /repo/service: an older chat
+#   User/globalStorage                    the newest composer's conversation,
+#                                         one row per message
+#
+# The workspaces of /other and of a multi-root workspace have no database.
+set -e
+cd "$(dirname "$0")"
+storage=Cursor/User/workspaceStorage
+global=Cursor/User/globalStorage/state.vscdb
+rm -f $storage/*/state.vscdb "$global"
+
+sqlite3 $storage/1a2b3c-repo/state.vscdb <<'SQL'
+CREATE TABLE ItemTable (key TEXT UNIQUE ON CONFLICT REPLACE, value BLOB);
+INSERT INTO ItemTable VALUES ('workbench.panel.aichat.view.aichat.chatdata', readfile('values/chatdata.json'));
+INSERT INTO ItemTable VALUES ('composer.composerData', readfile('values/composers.json'));
+SQL
+
+sqlite3 $storage/4d5e6f-service/state.vscdb <<'SQL'
+CREATE TABLE ItemTable (key TEXT UNIQUE ON CONFLICT REPLACE, value BLOB);
+INSERT INTO ItemTable VALUES ('workbench.panel.aichat.view.aichat.chatdata', readfile('values/service-chatdata.json'));
+SQL
+
+sqlite3 "$global" <<'SQL'
+CREATE TABLE ItemTable (key TEXT UNIQUE ON CONFLICT REPLACE, value BLOB);
+CREATE TABLE cursorDiskKV (key TEXT UNIQUE ON CONFLICT REPLACE, value BLOB);
+INSERT INTO cursorDiskKV VALUES ('composerData:c0ffee', readfile('values/composer-c0ffee.json'));
+INSERT INTO cursorDiskKV VALUES ('bubbleId:c0ffee:b1', readfile('values/bubble-b1.json'));
+INSERT INTO cursorDiskKV VALUES ('bubbleId:c0ffee:b2', readfile('values/bubble-b2.json'));
+INSERT INTO cursorDiskKV VALUES ('bubbleId:c0ffee:b3', readfile('values/bubble-b3.json'));
+INSERT INTO cursorDiskKV VALUES ('bubbleId:c0ffee:b4', readfile('values/bubble-b4.json'));
+SQL